@@ -0,0 +1,102 @@
+package gorender
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// WithStaticDir configura la función "asset", que devuelve la URL de un
+// archivo estático con un parámetro de versión calculado a partir de su
+// contenido, para que el navegador lo vuelva a descargar sólo cuando cambia.
+// urlPrefix es la ruta desde la que se sirven los archivos y dir el directorio
+// donde están, en el disco o dentro del sistema de archivos de WithFS.
+//
+// Ejemplo:
+//
+//	gorender.WithStaticDir("/static", "static")
+//
+//	<link rel="stylesheet" href="{{ asset "app.css" }}"> → /static/app.css?v=3f2a9c1b7d
+func WithStaticDir(urlPrefix, dir string) OptionFunc {
+	return func(re *Render) {
+		re.staticPrefix = "/" + strings.Trim(urlPrefix, "/")
+		re.staticDir = dir
+	}
+}
+
+// asset devuelve la URL de name con su versión. La versión se calcula una vez
+// y se guarda hasta que se vuelven a leer las plantillas. Si el archivo no
+// existe se registra un aviso y se devuelve la URL sin versión.
+func (re *Render) asset(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	url := path.Join("/", re.staticPrefix, name)
+
+	re.assetMu.RLock()
+	versioned, ok := re.assetURLs[name]
+	re.assetMu.RUnlock()
+	if ok {
+		return versioned
+	}
+
+	versioned = url
+	data, err := re.readStatic(name)
+	if err != nil {
+		re.log().Warn("static asset not found", "asset", name, "error", err)
+	} else {
+		sum := sha256.Sum256(data)
+		versioned = url + "?v=" + hex.EncodeToString(sum[:5])
+	}
+
+	re.assetMu.Lock()
+	if re.assetURLs == nil {
+		re.assetURLs = map[string]string{}
+	}
+	re.assetURLs[name] = versioned
+	re.assetMu.Unlock()
+
+	return versioned
+}
+
+// readStatic lee un archivo estático del sistema de archivos configurado o
+// del disco.
+func (re *Render) readStatic(name string) ([]byte, error) {
+	if re.fs != nil {
+		return fs.ReadFile(re.fs, fsPath(path.Join(re.staticDir, name)))
+	}
+
+	return os.ReadFile(filepath.Join(re.staticDir, filepath.FromSlash(name)))
+}
+
+// resetAssets olvida las versiones calculadas para que se calculen de nuevo.
+func (re *Render) resetAssets() {
+	re.assetMu.Lock()
+	re.assetURLs = nil
+	re.assetMu.Unlock()
+}
+
+// staticFiles devuelve los archivos estáticos del disco para que WithWatch
+// detecte sus cambios.
+func (re *Render) staticFiles() map[string]fileState {
+	files := map[string]fileState{}
+	if re.staticDir == "" || re.fs != nil {
+		return files
+	}
+
+	_ = filepath.WalkDir(re.staticDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files[path] = fileState{info.ModTime(), info.Size()}
+		return nil
+	})
+
+	return files
+}
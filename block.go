@@ -0,0 +1,116 @@
+package gorender
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Block procesa sólo el bloque indicado de una página, por ejemplo el
+// {{ define "results-table" }} que se devuelve en una respuesta parcial de
+// HTMX, en lugar de la página completa con su base.
+func (re *Render) Block(w http.ResponseWriter, r *http.Request, tmpl string, block string, td *TemplateData) (err error) {
+	start := time.Now()
+	defer func() { re.finish(w, r, start, tmpl, td, err) }()
+
+	t, set, err := re.lookupSet(tmpl)
+	if err != nil {
+		return err
+	}
+
+	buf := getBuffer()
+	td = re.addDefaultData(td, r)
+	td.template = set.name(tmpl)
+	re.drainFlashes(w, r, td)
+	t, err = re.withFuncs(set, t, set.pristine[td.template], tmpl, td)
+	if err != nil {
+		putBuffer(buf)
+		return err
+	}
+	err = re.executeBlock(buf, r, t, tmpl, block, td)
+	if err != nil {
+		re.stats.failed(td.template)
+		putBuffer(buf)
+		return err
+	}
+	re.stats.rendered(td.template)
+
+	return re.respond(w, r, tmpl, buf, td)
+}
+
+// Fragments procesa varios bloques de una página, en orden, y los escribe
+// juntos en una sola respuesta. Pensado para las actualizaciones "out of band"
+// de HTMX, donde se devuelve por ejemplo la fila actualizada, el aviso y un
+// contador a la vez. Si algún bloque falla no se escribe nada y el error indica
+// qué bloque ha sido.
+func (re *Render) Fragments(w http.ResponseWriter, r *http.Request, tmpl string, blocks []string, td *TemplateData) (err error) {
+	start := time.Now()
+	defer func() { re.finish(w, r, start, tmpl, td, err) }()
+
+	t, set, err := re.lookupSet(tmpl)
+	if err != nil {
+		return err
+	}
+
+	buf := getBuffer()
+	td = re.addDefaultData(td, r)
+	td.template = set.name(tmpl)
+	re.drainFlashes(w, r, td)
+	t, err = re.withFuncs(set, t, set.pristine[td.template], tmpl, td)
+	if err != nil {
+		putBuffer(buf)
+		return err
+	}
+	for _, block := range blocks {
+		err = re.executeBlock(buf, r, t, tmpl, block, td)
+		if err != nil {
+			re.stats.failed(td.template)
+			putBuffer(buf)
+			return fmt.Errorf("rendering block %q: %w", block, err)
+		}
+	}
+	re.stats.rendered(td.template)
+
+	return re.respond(w, r, tmpl, buf, td)
+}
+
+// HTMX procesa sólo el bloque indicado cuando la petición la hace HTMX
+// (cabecera HX-Request) y la página completa en caso contrario.
+func (re *Render) HTMX(w http.ResponseWriter, r *http.Request, tmpl string, block string, td *TemplateData) error {
+	if r.Header.Get("HX-Request") == "true" {
+		return re.Block(w, r, tmpl, block, td)
+	}
+
+	return re.Template(w, r, tmpl, td)
+}
+
+// executeBlock ejecuta el bloque indicado de t sobre buf. Si el bloque no
+// existe, el error enumera los bloques definidos en la página.
+func (re *Render) executeBlock(buf *bytes.Buffer, r *http.Request, t *template.Template, tmpl, block string, td *TemplateData) error {
+	if t.Lookup(block) == nil {
+		return fmt.Errorf("%w: block %q not defined in %s, defined blocks: %s", ErrTemplateNotFound, block, tmpl, strings.Join(definedBlocks(t), ", "))
+	}
+
+	return re.executeGuarded(buf, r, tmpl, func(w io.Writer) error {
+		return t.ExecuteTemplate(w, block, td)
+	}, "block", block)
+}
+
+// definedBlocks devuelve los nombres ordenados de las plantillas definidas
+// dentro de t.
+func definedBlocks(t *template.Template) []string {
+	var names []string
+	for _, tt := range t.Templates() {
+		if tt.Name() != "" {
+			names = append(names, tt.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
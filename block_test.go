@@ -0,0 +1,97 @@
+package gorender
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// blockFiles es una página con su base y dos bloques que se pueden procesar
+// por separado.
+var blockFiles = map[string]string{
+	"shared/base.html": `{{ define "base" }}<html>{{ template "content" . }}</html>{{ end }}`,
+	"pages/users.html": `{{ template "base" . }}` +
+		`{{ define "content" }}<h1>Usuarios</h1>{{ template "rows" . }}{{ end }}` +
+		`{{ define "rows" }}{{ range .Data.users }}<tr>{{ . }}</tr>{{ end }}{{ end }}` +
+		`{{ define "count" }}<span id="count" hx-swap-oob="true">{{ len .Data.users }}</span>{{ end }}`,
+}
+
+func usersData() *TemplateData {
+	return NewData().Set("users", []string{"ana", "luis"}).Build()
+}
+
+func TestBlock(t *testing.T) {
+	re := newTestRender(t, blockFiles)
+
+	rec := httptest.NewRecorder()
+	if err := re.Block(rec, httptest.NewRequest("GET", "/", nil), "users.html", "rows", usersData()); err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	if got, want := rec.Body.String(), "<tr>ana</tr><tr>luis</tr>"; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	err := re.Block(rec, httptest.NewRequest("GET", "/", nil), "users.html", "missing", usersData())
+	if !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("Block with an unknown block: err = %v, want ErrTemplateNotFound", err)
+	}
+	if !strings.Contains(err.Error(), `block "missing"`) || !strings.Contains(err.Error(), "count, rows") {
+		t.Errorf("error = %q, want the block name and the defined blocks", err)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("failed Block wrote %q", rec.Body.String())
+	}
+}
+
+func TestFragments(t *testing.T) {
+	re := newTestRender(t, blockFiles)
+
+	rec := httptest.NewRecorder()
+	if err := re.Fragments(rec, httptest.NewRequest("GET", "/", nil), "users.html", []string{"rows", "count"}, usersData()); err != nil {
+		t.Fatalf("Fragments: %v", err)
+	}
+	if got, want := rec.Body.String(), `<tr>ana</tr><tr>luis</tr><span id="count" hx-swap-oob="true">2</span>`; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	err := re.Fragments(rec, httptest.NewRequest("GET", "/", nil), "users.html", []string{"rows", "missing"}, usersData())
+	if !errors.Is(err, ErrTemplateNotFound) || !strings.HasPrefix(err.Error(), `rendering block "missing"`) {
+		t.Errorf("Fragments with an unknown block: err = %v", err)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("failed Fragments wrote %q", rec.Body.String())
+	}
+}
+
+func TestHTMX(t *testing.T) {
+	re := newTestRender(t, blockFiles)
+
+	tests := []struct {
+		name      string
+		hxRequest string
+		want      string
+	}{
+		{"htmx request", "true", "<tr>ana</tr><tr>luis</tr>"},
+		{"normal request", "", "<html><h1>Usuarios</h1><tr>ana</tr><tr>luis</tr></html>"},
+		{"other value", "false", "<html><h1>Usuarios</h1><tr>ana</tr><tr>luis</tr></html>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.hxRequest != "" {
+				req.Header.Set("HX-Request", tt.hxRequest)
+			}
+			rec := httptest.NewRecorder()
+			if err := re.HTMX(rec, req, "users.html", "rows", usersData()); err != nil {
+				t.Fatalf("HTMX: %v", err)
+			}
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
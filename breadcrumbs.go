@@ -0,0 +1,120 @@
+package gorender
+
+import (
+	"encoding/json"
+	"html/template"
+	"strings"
+)
+
+// Breadcrumb es un elemento de la ruta de navegación de la página. Si Key no
+// está vacía, la etiqueta es su traducción con translateKey en lugar de Label.
+type Breadcrumb struct {
+	Label string
+	Key   string
+	URL   string
+}
+
+// AddCrumb añade un elemento a Breadcrumbs y devuelve td para encadenar
+// llamadas. label se muestra tal cual; para traducirlo se usa AddCrumbKey.
+//
+// Ejemplo:
+//
+//	td.AddCrumbKey("nav.home", "/").AddCrumb("Usuarios", "/users").AddCrumb(u.Name, "")
+func (td *TemplateData) AddCrumb(label, url string) *TemplateData {
+	td.Breadcrumbs = append(td.Breadcrumbs, Breadcrumb{Label: label, URL: url})
+
+	return td
+}
+
+// AddCrumbKey añade un elemento a Breadcrumbs cuya etiqueta es la traducción
+// de key y devuelve td para encadenar llamadas.
+func (td *TemplateData) AddCrumbKey(key, url string) *TemplateData {
+	td.Breadcrumbs = append(td.Breadcrumbs, Breadcrumb{Key: key, URL: url})
+
+	return td
+}
+
+// breadcrumbsTemplate escribe la ruta de navegación. Se usa html/template para
+// que las direcciones pasen por el mismo filtro que en cualquier otro href y
+// una como "javascript:..." se quede en "#ZgotmplZ".
+var breadcrumbsTemplate = template.Must(template.New("breadcrumbs").Parse(
+	`<nav aria-label="breadcrumb"><ol>` +
+		`{{range .}}{{if .Current}}<li aria-current="page">{{.Label}}</li>` +
+		`{{else if .URL}}<li><a href="{{.URL}}">{{.Label}}</a></li>` +
+		`{{else}}<li>{{.Label}}</li>{{end}}{{end}}` +
+		`</ol></nav>`))
+
+// renderBreadcrumbs escribe la ruta de navegación como una lista accesible,
+// con el último elemento sin enlace porque es la página actual.
+//
+// Ejemplo:
+//
+//	{{ renderBreadcrumbs .Breadcrumbs }}
+func (re *Render) renderBreadcrumbs(crumbs []Breadcrumb, locale ...string) (template.HTML, error) {
+	if len(crumbs) == 0 {
+		return "", nil
+	}
+
+	type item struct {
+		Label   string
+		URL     string
+		Current bool
+	}
+	items := make([]item, len(crumbs))
+	for i, c := range crumbs {
+		items[i] = item{Label: re.crumbLabel(c, locale...), URL: c.URL, Current: i == len(crumbs)-1}
+	}
+
+	var b strings.Builder
+	if err := breadcrumbsTemplate.Execute(&b, items); err != nil {
+		return "", err
+	}
+
+	return template.HTML(b.String()), nil
+}
+
+// breadcrumbsJSONLD escribe la ruta de navegación como un BreadcrumbList de
+// schema.org para los buscadores, que esperan direcciones absolutas en URL.
+//
+// Ejemplo:
+//
+//	{{ breadcrumbsJSONLD .Breadcrumbs }}
+func (re *Render) breadcrumbsJSONLD(crumbs []Breadcrumb, locale ...string) (template.HTML, error) {
+	if len(crumbs) == 0 {
+		return "", nil
+	}
+
+	type listItem struct {
+		Type     string `json:"@type"`
+		Position int    `json:"position"`
+		Name     string `json:"name"`
+		Item     string `json:"item,omitempty"`
+	}
+	items := make([]listItem, len(crumbs))
+	for i, c := range crumbs {
+		items[i] = listItem{Type: "ListItem", Position: i + 1, Name: re.crumbLabel(c, locale...), Item: c.URL}
+	}
+
+	// json.Marshal escapa <, > y &, así que el resultado no puede cerrar el
+	// <script>.
+	data, err := json.Marshal(struct {
+		Context string     `json:"@context"`
+		Type    string     `json:"@type"`
+		Items   []listItem `json:"itemListElement"`
+	}{"https://schema.org", "BreadcrumbList", items})
+	if err != nil {
+		return "", err
+	}
+
+	return template.HTML(`<script type="application/ld+json">` + string(data) + `</script>`), nil
+}
+
+// crumbLabel devuelve la etiqueta de c: la traducción de Key si la tiene o
+// Label tal cual.
+func (re *Render) crumbLabel(c Breadcrumb, locale ...string) string {
+	if c.Key == "" {
+		return c.Label
+	}
+
+	return re.translateKey(c.Key, locale...)
+}
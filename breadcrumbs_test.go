@@ -0,0 +1,56 @@
+package gorender
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRenderBreadcrumbs(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/crumbs.html": `{{ renderBreadcrumbs .Breadcrumbs }}`})
+
+	td := &TemplateData{}
+	td.AddCrumb("Home", "/").
+		AddCrumb("Evil", "javascript:alert(1)").
+		AddCrumb("example.com", "/sites?q=a&b=<c>").
+		AddCrumb("<b>Current</b>", "/ignored")
+
+	got, err := re.TemplateString("crumbs.html", td)
+	if err != nil {
+		t.Fatalf("TemplateString: %v", err)
+	}
+
+	for _, want := range []string{
+		`<li><a href="/">Home</a></li>`,
+		`<li><a href="#ZgotmplZ">Evil</a></li>`,
+		`<li><a href="/sites?q=a&amp;b=%3cc%3e">example.com</a></li>`,
+		`<li aria-current="page">&lt;b&gt;Current&lt;/b&gt;</li>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output %q does not contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "javascript:") {
+		t.Errorf("output %q keeps the javascript: URL", got)
+	}
+}
+
+func TestCrumbLabel(t *testing.T) {
+	catalogs := fstest.MapFS{"es_ES.json": &fstest.MapFile{Data: []byte(`{"nav": {"home": "Inicio"}}`)}}
+	re := newTestRender(t, nil, WithTranslationsFS(catalogs))
+
+	tests := []struct {
+		crumb Breadcrumb
+		want  string
+	}{
+		{Breadcrumb{Label: "example.com"}, "example.com"},
+		{Breadcrumb{Label: "v1.2"}, "v1.2"},
+		{Breadcrumb{Label: "nav.home"}, "nav.home"},
+		{Breadcrumb{Key: "nav.home"}, "Inicio"},
+	}
+	for _, tt := range tests {
+		if got := re.crumbLabel(tt.crumb); got != tt.want {
+			t.Errorf("crumbLabel(%+v) = %q, want %q", tt.crumb, got, tt.want)
+		}
+	}
+}
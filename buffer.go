@@ -0,0 +1,33 @@
+package gorender
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBuffer es la capacidad máxima de un búfer para volver al pool. Los
+// búferes más grandes se descartan para que una página enorme puntual no deje
+// memoria retenida para siempre.
+const maxPooledBuffer = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// getBuffer devuelve un búfer vacío del pool.
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// putBuffer devuelve el búfer al pool. Sólo debe llamarse cuando ya no se va a
+// usar, nunca si una escritura a partir de él se ha quedado a medias.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+
+	buf.Reset()
+	bufferPool.Put(buf)
+}
@@ -0,0 +1,73 @@
+package gorender
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPutBufferDropsLargeBuffers(t *testing.T) {
+	big := bytes.NewBuffer(make([]byte, 0, maxPooledBuffer+1))
+	putBuffer(big)
+
+	for i := 0; i < 10; i++ {
+		if buf := getBuffer(); buf == big {
+			t.Fatal("a buffer larger than maxPooledBuffer went back to the pool")
+		}
+	}
+}
+
+func TestTemplateWriteError(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": `hola`})
+
+	err := re.Template(failingWriter{httptest.NewRecorder()}, httptest.NewRequest("GET", "/", nil), "index.html", nil)
+	if !errors.Is(err, ErrWrite) || !errors.Is(err, errBrokenPipe) {
+		t.Errorf("Template error = %v, want ErrWrite wrapping the write error", err)
+	}
+}
+
+// BenchmarkTemplate renderiza una página de unos 40 KB, el caso que motivó el
+// pool de búferes.
+func BenchmarkTemplate(b *testing.B) {
+	re := newTestRender(b, map[string]string{
+		"pages/index.html": `<ul>{{ range .Data.items }}<li>{{ . }}</li>{{ end }}</ul>`,
+	})
+	items := make([]string, 2000)
+	for i := range items {
+		items[i] = strings.Repeat("x", 10)
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rec := httptest.NewRecorder()
+		td := &TemplateData{Data: map[string]interface{}{"items": items}}
+		if err := re.Template(rec, req, "index.html", td); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkBuffer compara el pool con un búfer nuevo en cada renderizado.
+func BenchmarkBuffer(b *testing.B) {
+	page := []byte(strings.Repeat("x", 40<<10))
+
+	b.Run("pool", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf := getBuffer()
+			buf.Write(page)
+			putBuffer(buf)
+		}
+	})
+	b.Run("new", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf := new(bytes.Buffer)
+			buf.Write(page)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package gorender
+
+// DataBuilder construye un TemplateData encadenando llamadas:
+//
+//	td := gorender.NewData().
+//		Set("user", u).
+//		Feedback("success", "Guardado").
+//		Form(fd).
+//		Build()
+//
+// Los métodos se pueden llamar en cualquier orden y el mismo constructor se
+// puede reutilizar: cada llamada a Build devuelve una copia independiente.
+type DataBuilder struct {
+	td TemplateData
+}
+
+// NewData crea un constructor de TemplateData vacío.
+func NewData() *DataBuilder {
+	return &DataBuilder{}
+}
+
+// Set guarda un valor en Data.
+func (b *DataBuilder) Set(key string, value interface{}) *DataBuilder {
+	if b.td.Data == nil {
+		b.td.Data = map[string]interface{}{}
+	}
+	b.td.Data[key] = value
+
+	return b
+}
+
+// Feedback añade un mensaje del nivel indicado, igual que
+// TemplateData.AddFeedback.
+func (b *DataBuilder) Feedback(level, message string) *DataBuilder {
+	b.td.AddFeedback(level, message)
+
+	return b
+}
+
+// Form guarda los datos del formulario.
+func (b *DataBuilder) Form(fd FormData) *DataBuilder {
+	b.td.FormData = fd
+
+	return b
+}
+
+// Session guarda los datos de la sesión.
+func (b *DataBuilder) Session(session interface{}) *DataBuilder {
+	b.td.SessionData = session
+
+	return b
+}
+
+// Page guarda la paginación.
+func (b *DataBuilder) Page(p Pages) *DataBuilder {
+	b.td.Page = p
+
+	return b
+}
+
+// Build devuelve el TemplateData construido. Los mapas se copian, así que
+// seguir usando el constructor no modifica los datos ya devueltos.
+func (b *DataBuilder) Build() *TemplateData {
+	td := b.td
+
+	if b.td.Data != nil {
+		td.Data = make(map[string]interface{}, len(b.td.Data))
+		for k, v := range b.td.Data {
+			td.Data[k] = v
+		}
+	}
+	if b.td.FeedbackData != nil {
+		td.FeedbackData = make(map[string]string, len(b.td.FeedbackData))
+		for k, v := range b.td.FeedbackData {
+			td.FeedbackData[k] = v
+		}
+	}
+	if b.td.Messages != nil {
+		td.Messages = make(map[string][]string, len(b.td.Messages))
+		for k, v := range b.td.Messages {
+			td.Messages[k] = append([]string{}, v...)
+		}
+	}
+
+	return &td
+}
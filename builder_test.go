@@ -0,0 +1,55 @@
+package gorender
+
+import "testing"
+
+func TestDataBuilder(t *testing.T) {
+	form := NewForm()
+	form.AddError("email", "no válido")
+	session := struct{ User string }{"ana"}
+	page := NewPages(100, 10, 2)
+
+	b := NewData().
+		Set("user", "ana").
+		Set("posts", 3).
+		Feedback(FeedbackSuccess, "Guardado").
+		Feedback(FeedbackSuccess, "Publicado").
+		Form(form).
+		Session(session).
+		Page(page)
+	td := b.Build()
+
+	if td.Data["user"] != "ana" || td.Data["posts"] != 3 {
+		t.Errorf("Data = %v", td.Data)
+	}
+	if got := td.Messages[FeedbackSuccess]; len(got) != 2 || got[0] != "Guardado" || got[1] != "Publicado" {
+		t.Errorf("Messages[success] = %q, want both messages in order", got)
+	}
+	if td.FeedbackData[FeedbackSuccess] != "Publicado" {
+		t.Errorf("FeedbackData[success] = %q, want the last message", td.FeedbackData[FeedbackSuccess])
+	}
+	if !td.FormData.HasErrors || td.FormData.Errors["email"] != "no válido" {
+		t.Errorf("FormData = %+v", td.FormData)
+	}
+	if td.SessionData != session {
+		t.Errorf("SessionData = %v, want %v", td.SessionData, session)
+	}
+	if td.Page != page {
+		t.Errorf("Page = %+v, want %+v", td.Page, page)
+	}
+
+	// Seguir usando el constructor no modifica lo ya construido.
+	b.Set("user", "luis").Feedback(FeedbackSuccess, "Otro").Feedback(FeedbackError, "Mal")
+	if td.Data["user"] != "ana" {
+		t.Errorf("Data[user] = %v after reusing the builder, want ana", td.Data["user"])
+	}
+	if len(td.Messages[FeedbackSuccess]) != 2 || td.Messages[FeedbackError] != nil || td.FeedbackData[FeedbackError] != "" {
+		t.Errorf("Messages = %q after reusing the builder", td.Messages)
+	}
+	if again := b.Build(); again.Data["user"] != "luis" || len(again.Messages[FeedbackSuccess]) != 3 {
+		t.Errorf("second Build = %v, %q", again.Data, again.Messages)
+	}
+
+	if empty := NewData().Build(); empty == nil || empty.Data != nil || empty.Messages != nil {
+		t.Errorf("NewData().Build() = %+v, want an empty TemplateData", empty)
+	}
+}
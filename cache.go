@@ -0,0 +1,350 @@
+package gorender
+
+import (
+	"html/template"
+	"path"
+	"sync"
+	texttemplate "text/template"
+	"time"
+)
+
+// TemplateCache guarda las plantillas ya procesadas, indexadas por nombre. Es
+// seguro para uso concurrente: las lecturas pueden hacerse mientras otra
+// goroutine reconstruye la caché, ya que la reconstrucción sustituye el mapa
+// completo de una sola vez.
+//
+// Las plantillas guardadas se ejecutan a la vez desde varias peticiones, así
+// que no se modifican nunca: ni Funcs, ni Option, ni Parse, ni New. Lo que
+// necesite cambiarlas, como TemplateData.Funcs, trabaja sobre una copia hecha
+// con Clone antes de ejecutarlas.
+//
+// Antes era un map[string]*template.Template. El acceso directo al mapa se
+// sustituye por Get, Set y Len.
+type TemplateCache struct {
+	mu  sync.RWMutex
+	set *templateSet
+}
+
+// NewTemplateCache crea una caché vacía.
+func NewTemplateCache() *TemplateCache {
+	return &TemplateCache{set: newTemplateSet()}
+}
+
+// Get devuelve la plantilla guardada con el nombre indicado. El nombre es la
+// ruta relativa a PageTemplatesPath, aunque también se acepta el nombre del
+// archivo a secas si ninguna otra página se llama igual. La plantilla es la
+// misma que usan las peticiones en curso, así que no debe modificarse; para
+// cambiarla hay que usar una copia hecha con Clone.
+func (tc *TemplateCache) Get(name string) (*template.Template, bool) {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+
+	if tc.set == nil {
+		return nil, false
+	}
+
+	return tc.set.lookup(name)
+}
+
+// Set guarda o sustituye la plantilla con el nombre indicado, que pasa a estar
+// disponible también por su nombre de archivo si ninguna otra página se llama
+// igual. A partir de ese momento t no debe modificarse, y tampoco debe haberse
+// ejecutado para que funcionen TemplateData.Funcs y WithLocales.
+//
+// La plantilla no tiene archivo de origen, así que Reload la descarta y
+// Rebuild o Invalidate la sustituyen por la página del disco o de AddTemplate
+// con el mismo nombre, si existe.
+func (tc *TemplateCache) Set(name string, t *template.Template) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	set := tc.set.clone()
+	set.drop(name)
+	delete(set.invalidated, name)
+	set.templates[name] = t
+	if c, err := t.Clone(); err == nil {
+		set.pristine[name] = c
+	}
+	set.merge(&templateSet{aliases: map[string]string{path.Base(name): name}})
+	tc.set = set
+}
+
+// ModTime devuelve la fecha de modificación más reciente entre el archivo de
+// la página name y las plantillas compartidas, tal y como estaban al construir
+// la caché.
+func (tc *TemplateCache) ModTime(name string) (time.Time, bool) {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+
+	if tc.set == nil {
+		return time.Time{}, false
+	}
+
+	return tc.set.modTime(name)
+}
+
+// Len devuelve la cantidad de plantillas guardadas.
+func (tc *TemplateCache) Len() int {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+
+	if tc.set == nil {
+		return 0
+	}
+
+	return len(tc.set.templates)
+}
+
+// current devuelve el conjunto de plantillas actual. El conjunto no se modifica
+// nunca una vez publicado, así que puede usarse sin mantener el bloqueo.
+func (tc *TemplateCache) current() *templateSet {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+
+	if tc.set == nil {
+		return newTemplateSet()
+	}
+
+	return tc.set
+}
+
+// swap sustituye todas las plantillas de la caché por las indicadas.
+func (tc *TemplateCache) swap(set *templateSet) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	tc.set = set
+}
+
+// templateSet es el resultado de procesar todas las páginas. Se construye
+// entero antes de publicarlo en TemplateCache y a partir de entonces ni sus
+// mapas ni sus plantillas cambian, salvo layouts, que tiene su propio bloqueo;
+// los cambios se hacen sobre clone y se publican con swap.
+type templateSet struct {
+	// templates son las páginas indexadas por su ruta relativa.
+	templates map[string]*template.Template
+	// texts son las páginas de texto plano indexadas por su ruta relativa.
+	texts map[string]*texttemplate.Template
+	// aliases relaciona el nombre de archivo de una página con su ruta
+	// relativa, sólo cuando ninguna otra página tiene el mismo nombre.
+	aliases map[string]string
+	// ambiguous guarda los nombres de archivo compartidos por varias páginas.
+	ambiguous map[string][]string
+	// pageFiles relaciona cada página con el archivo del que se ha leído.
+	pageFiles map[string]string
+	// sharedFiles son las plantillas compartidas procesadas con cada página.
+	sharedFiles []string
+	// modTimes guarda, para cada página, la fecha de modificación más reciente
+	// entre su archivo y las plantillas compartidas.
+	modTimes map[string]time.Time
+	// sources guarda el origen de cada archivo procesado, uno de los Source*.
+	sources map[string]string
+	// memory guarda el contenido de las páginas registradas con AddTemplate.
+	memory map[string]string
+	// pristine guarda, con WithRequestFuncs o WithLocales, una copia sin
+	// ejecutar de cada página para los renderizados con TemplateData.Funcs o
+	// en otro idioma.
+	pristine map[string]*template.Template
+	// invalidated son las páginas quitadas con Invalidate, que se procesan de
+	// nuevo al pedirlas.
+	invalidated map[string]bool
+
+	// layoutsMu protege layouts, que se rellena bajo demanda.
+	layoutsMu sync.Mutex
+	// layouts son las páginas procesadas dentro de una base concreta,
+	// indexadas por base y página.
+	layouts map[layoutKey]*template.Template
+	// layoutsPristine es el equivalente de pristine para layouts.
+	layoutsPristine map[layoutKey]*template.Template
+
+	// localizedMu protege localized, que se rellena bajo demanda.
+	localizedMu sync.Mutex
+	// localized son las copias de las páginas con las funciones atadas a un
+	// idioma, indexadas por la copia sin ejecutar de la que salen y el idioma.
+	localized map[localizedKey]*template.Template
+}
+
+type localizedKey struct {
+	pristine *template.Template
+	locale   string
+}
+
+type layoutKey struct {
+	layout string
+	page   string
+}
+
+func newTemplateSet() *templateSet {
+	return &templateSet{
+		templates: map[string]*template.Template{},
+		texts:     map[string]*texttemplate.Template{},
+		aliases:   map[string]string{},
+		ambiguous: map[string][]string{},
+		pageFiles: map[string]string{},
+		modTimes:  map[string]time.Time{},
+		sources:   map[string]string{},
+		memory:    map[string]string{},
+		pristine:  map[string]*template.Template{},
+		layouts:   map[layoutKey]*template.Template{},
+
+		layoutsPristine: map[layoutKey]*template.Template{},
+		invalidated:     map[string]bool{},
+		localized:       map[localizedKey]*template.Template{},
+	}
+}
+
+// clone devuelve una copia del conjunto que se puede modificar sin afectar a
+// quien esté usando el original. Acepta un conjunto nil.
+func (s *templateSet) clone() *templateSet {
+	c := newTemplateSet()
+	if s == nil {
+		return c
+	}
+
+	for k, v := range s.templates {
+		c.templates[k] = v
+	}
+	for k, v := range s.texts {
+		c.texts[k] = v
+	}
+	for k, v := range s.aliases {
+		c.aliases[k] = v
+	}
+	for k, v := range s.ambiguous {
+		c.ambiguous[k] = v
+	}
+	for k, v := range s.pageFiles {
+		c.pageFiles[k] = v
+	}
+	for k, v := range s.modTimes {
+		c.modTimes[k] = v
+	}
+	for k, v := range s.sources {
+		c.sources[k] = v
+	}
+	for k, v := range s.memory {
+		c.memory[k] = v
+	}
+	for k, v := range s.pristine {
+		c.pristine[k] = v
+	}
+	for k, v := range s.invalidated {
+		c.invalidated[k] = v
+	}
+	c.sharedFiles = s.sharedFiles
+
+	return c
+}
+
+// resolve devuelve la clave de la página con el nombre indicado, que puede ser
+// su ruta relativa o su nombre de archivo si no es ambiguo.
+func (s *templateSet) resolve(name string) (string, bool) {
+	if _, ok := s.templates[name]; ok {
+		return name, true
+	}
+
+	key, ok := s.aliases[name]
+	if _, exists := s.templates[key]; !ok || !exists {
+		return "", false
+	}
+
+	return key, true
+}
+
+// keys devuelve las claves de todas las páginas, HTML y de texto.
+func (s *templateSet) keys() []string {
+	keys := make([]string, 0, len(s.templates)+len(s.texts))
+	for key := range s.templates {
+		keys = append(keys, key)
+	}
+	for key := range s.texts {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// name devuelve la clave de la página name, o name tal cual si no existe.
+func (s *templateSet) name(name string) string {
+	if key, ok := s.resolve(name); ok {
+		return key
+	}
+	if key, ok := s.aliases[name]; ok {
+		return key
+	}
+
+	return name
+}
+
+// modTime devuelve la fecha de modificación de la página name.
+func (s *templateSet) modTime(name string) (time.Time, bool) {
+	key, ok := s.resolve(name)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	t, ok := s.modTimes[key]
+	return t, ok
+}
+
+// has indica si name es una página del conjunto, HTML o de texto, o un nombre
+// de archivo que comparten varias.
+func (s *templateSet) has(name string) bool {
+	if _, ok := s.lookup(name); ok {
+		return true
+	}
+	if _, ok := s.lookupText(name); ok {
+		return true
+	}
+	_, ok := s.ambiguous[name]
+
+	return ok
+}
+
+func (s *templateSet) lookup(name string) (*template.Template, bool) {
+	if t, ok := s.templates[name]; ok {
+		return t, true
+	}
+
+	if key, ok := s.aliases[name]; ok {
+		t, ok := s.templates[key]
+		return t, ok
+	}
+
+	return nil, false
+}
+
+func (s *templateSet) lookupText(name string) (*texttemplate.Template, bool) {
+	if t, ok := s.texts[name]; ok {
+		return t, true
+	}
+
+	if key, ok := s.aliases[name]; ok {
+		t, ok := s.texts[key]
+		return t, ok
+	}
+
+	return nil, false
+}
+
+// Reload vuelve a procesar todas las plantillas y sustituye la caché. Si alguna
+// plantilla falla, la caché anterior se mantiene intacta y se devuelve el
+// error, de modo que la aplicación sigue sirviendo las plantillas previas. Es
+// seguro llamarlo mientras se atienden peticiones. Las plantillas registradas
+// con AddTemplate y AddPartial se conservan.
+func (re *Render) Reload() error {
+	re.reloadMu.Lock()
+	defer re.reloadMu.Unlock()
+
+	set, err := re.createTemplateCache()
+	if err != nil {
+		re.log().Error("error reloading template cache:", "error", err)
+		return err
+	}
+
+	re.TemplateCache.swap(set)
+	re.log().Info("template cache reloaded", "templates", len(set.templates))
+	re.liveReload.broadcast()
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package gorender
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestTemplateCacheConcurrent(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/index.html": `{{ template "title" . }}`,
+		"shared/base.html": `{{ define "title" }}hola{{ end }}`,
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				switch j % 4 {
+				case 0:
+					rec := httptest.NewRecorder()
+					if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "index.html", nil); err != nil {
+						t.Errorf("Template: %v", err)
+					}
+				case 1:
+					if _, ok := re.TemplateCache.Get("index.html"); !ok {
+						t.Error("index.html missing from the cache")
+					}
+					re.TemplateCache.Len()
+				case 2:
+					name := fmt.Sprintf("g%d.html", i)
+					re.TemplateCache.Set(name, template.Must(template.New(name).Parse("x")))
+				case 3:
+					if i%10 == 0 {
+						if err := re.Reload(); err != nil {
+							t.Errorf("Reload: %v", err)
+						}
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestTemplateCacheSet(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": `old`}, WithRequestFuncs())
+	render := func(td *TemplateData) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := re.RenderTo(&buf, "index.html", td); err != nil {
+			t.Fatalf("RenderTo: %v", err)
+		}
+		return buf.String()
+	}
+
+	re.TemplateCache.Set("index.html", template.Must(template.New("index.html").Parse(`new`)))
+	if got := render(&TemplateData{}); got != "new" {
+		t.Errorf("after Set = %q, want %q", got, "new")
+	}
+	if got := render(&TemplateData{Funcs: template.FuncMap{"x": func() string { return "" }}}); got != "new" {
+		t.Errorf("after Set with Funcs = %q, want %q", got, "new")
+	}
+
+	re.TemplateCache.Set("blog/post.html", template.Must(template.New("post.html").Parse(`post`)))
+	if _, ok := re.TemplateCache.Get("post.html"); !ok {
+		t.Error("Get by file name after Set failed")
+	}
+
+	if err := re.Rebuild("index.html"); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	if got := render(&TemplateData{}); got != "old" {
+		t.Errorf("after Rebuild = %q, want %q", got, "old")
+	}
+}
+
+// TestCachedTemplatesConcurrentRender renderiza la misma página de la caché
+// desde muchas goroutines, con y sin TemplateData.Funcs, para que -race
+// detecte cualquier modificación de las plantillas compartidas.
+func TestCachedTemplatesConcurrentRender(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/user.html":  `{{ template "name" . }}{{ if can }}!{{ end }}`,
+		"shared/name.html": `{{ define "name" }}{{ .Data.name }}{{ end }}`,
+	}, WithRequestFuncs("can"))
+	before, _ := re.TemplateCache.Get("user.html")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				name := fmt.Sprintf("user%d-%d", i, j)
+				allowed := (i+j)%2 == 0
+				td := &TemplateData{
+					Data:  map[string]interface{}{"name": name},
+					Funcs: template.FuncMap{"can": func() bool { return allowed }},
+				}
+
+				var buf bytes.Buffer
+				if err := re.RenderTo(&buf, "user.html", td); err != nil {
+					t.Errorf("RenderTo: %v", err)
+					return
+				}
+				want := name
+				if allowed {
+					want += "!"
+				}
+				if buf.String() != want {
+					t.Errorf("output = %q, want %q", buf.String(), want)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if after, _ := re.TemplateCache.Get("user.html"); after != before {
+		t.Error("the cached template was replaced while rendering")
+	}
+}
@@ -0,0 +1,129 @@
+package gorender
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"text/template/parse"
+)
+
+// Validate procesa todas las plantillas y comprueba que cada
+// {{ template "nombre" }} de cada página apunte a una plantilla definida, para
+// detectar al arrancar, o en las pruebas de integración, los errores que de
+// otro modo sólo aparecen al visitar la página. Devuelve todos los problemas
+// encontrados, incluidos los errores al procesar, o nil si no hay ninguno. Las
+// plantillas se leen de nuevo aunque la caché esté habilitada, pero la caché
+// no se modifica.
+//
+// Ejemplo:
+//
+//	if errs := ren.Validate(); len(errs) > 0 {
+//		log.Fatal(errors.Join(errs...))
+//	}
+func (re *Render) Validate() []error {
+	return re.validate(false)
+}
+
+// DryRun hace lo mismo que Validate y además ejecuta cada página con un
+// TemplateData vacío, descartando el resultado, para encontrar los errores
+// que sólo aparecen al ejecutar. Las páginas que necesitan datos concretos
+// pueden fallar aquí aunque funcionen en la aplicación.
+func (re *Render) DryRun() []error {
+	return re.validate(true)
+}
+
+func (re *Render) validate(execute bool) []error {
+	set, err := re.buildSet("")
+	if err != nil {
+		var joined interface{ Unwrap() []error }
+		if errors.As(err, &joined) {
+			return joined.Unwrap()
+		}
+		return []error{err}
+	}
+
+	var errs []error
+	for _, key := range sortedKeys(set.templates) {
+		page := set.templates[key]
+		for _, t := range page.Templates() {
+			errs = append(errs, undefinedTemplates(key, t.Tree, func(name string) bool {
+				ref := page.Lookup(name)
+				return ref != nil && ref.Tree != nil
+			})...)
+		}
+		if execute {
+			if err := page.Execute(io.Discard, re.baseData(nil)); err != nil {
+				errs = append(errs, executeError(key, err))
+			}
+		}
+	}
+	for _, key := range sortedKeys(set.texts) {
+		page := set.texts[key]
+		for _, t := range page.Templates() {
+			errs = append(errs, undefinedTemplates(key, t.Tree, func(name string) bool {
+				ref := page.Lookup(name)
+				return ref != nil && ref.Tree != nil
+			})...)
+		}
+		if execute {
+			if err := page.Execute(io.Discard, re.baseData(nil)); err != nil {
+				errs = append(errs, executeError(key, err))
+			}
+		}
+	}
+
+	return errs
+}
+
+// undefinedTemplates devuelve un error por cada {{ template }} de tree que
+// apunta a una plantilla para la que defined devuelve false.
+func undefinedTemplates(page string, tree *parse.Tree, defined func(string) bool) []error {
+	if tree == nil {
+		return nil
+	}
+
+	var errs []error
+	walkNodes(tree.Root, func(n *parse.TemplateNode) {
+		if !defined(n.Name) {
+			location, _ := tree.ErrorContext(n)
+			errs = append(errs, fmt.Errorf("%s: %s: undefined template referenced: %w: %q", page, location, ErrTemplateNotFound, n.Name))
+		}
+	})
+
+	return errs
+}
+
+// walkNodes llama a fn por cada {{ template }} que hay dentro de node.
+func walkNodes(node parse.Node, fn func(*parse.TemplateNode)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkNodes(child, fn)
+		}
+	case *parse.TemplateNode:
+		fn(n)
+	case *parse.IfNode:
+		walkNodes(n.List, fn)
+		walkNodes(n.ElseList, fn)
+	case *parse.RangeNode:
+		walkNodes(n.List, fn)
+		walkNodes(n.ElseList, fn)
+	case *parse.WithNode:
+		walkNodes(n.List, fn)
+		walkNodes(n.ElseList, fn)
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,64 @@
+package gorender
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/index.html":   `{{ template "header" . }}hola{{ if .Data.x }}{{ template "sidebar" . }}{{ end }}`,
+		"pages/about.html":   `{{ template "footer" . }}`,
+		"shared/footer.html": `{{ define "footer" }}pie{{ end }}`,
+	})
+
+	errs := re.Validate()
+	if len(errs) != 2 {
+		t.Fatalf("Validate = %v, want 2 errors", errs)
+	}
+	for i, name := range []string{`"header"`, `"sidebar"`} {
+		if !errors.Is(errs[i], ErrTemplateNotFound) || !strings.Contains(errs[i].Error(), "index.html") || !strings.Contains(errs[i].Error(), name) {
+			t.Errorf("error %d = %v, want ErrTemplateNotFound for %s in index.html", i, errs[i], name)
+		}
+	}
+}
+
+func TestValidateMissingFunction(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": `{{ shout .Data.name }}`}, WithCache(false))
+
+	errs := re.Validate()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `function "shout" not defined`) {
+		t.Errorf("Validate = %v, want the undefined function", errs)
+	}
+}
+
+func TestValidateClean(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/index.html":   `{{ template "footer" . }}`,
+		"pages/report.txt":   `{{ .Data.total }}`,
+		"shared/footer.html": `{{ define "footer" }}pie{{ end }}`,
+	}, WithTextExtensions(".txt"))
+
+	if errs := re.Validate(); errs != nil {
+		t.Errorf("Validate = %v, want nil", errs)
+	}
+	if errs := re.DryRun(); errs != nil {
+		t.Errorf("DryRun = %v, want nil", errs)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/index.html": `{{ index .Data.items 3 }}`,
+		"pages/ok.html":    `hola`,
+	})
+
+	if errs := re.Validate(); errs != nil {
+		t.Errorf("Validate = %v, want nil because the page parses", errs)
+	}
+	errs := re.DryRun()
+	if len(errs) != 1 || !errors.Is(errs[0], ErrExecute) || !strings.Contains(errs[0].Error(), "index.html") {
+		t.Errorf("DryRun = %v, want the execution error of index.html", errs)
+	}
+}
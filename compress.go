@@ -0,0 +1,143 @@
+package gorender
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	gzipWriters  = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
+	flateWriters = sync.Pool{New: func() any {
+		fw, _ := flate.NewWriter(nil, flate.DefaultCompression)
+		return fw
+	}}
+)
+
+// WithCompression comprime con gzip o deflate las respuestas de al menos
+// minSize bytes cuando el cliente lo admite. Si la respuesta ya tiene
+// Content-Encoding, por ejemplo porque un middleware ya la comprime, no se
+// hace nada. Con minSize negativo se desactiva.
+func WithCompression(minSize int) OptionFunc {
+	return func(re *Render) {
+		re.compressMin = minSize
+		re.compress = minSize >= 0
+	}
+}
+
+// responseEncoding decide si la respuesta con el cuerpo buf se va a comprimir
+// y devuelve la codificación, o una cadena vacía si no. Se llama antes de
+// notModified para que un 304 lleve las mismas cabeceras Vary y ETag que el
+// 200 al que sustituye.
+//
+// Vary: Accept-Encoding se pone siempre que la compresión está activa, también
+// en las respuestas pequeñas, porque la misma URL puede devolver después un
+// cuerpo comprimido y las cachés deben distinguirlos.
+func (re *Render) responseEncoding(w http.ResponseWriter, r *http.Request, buf *bytes.Buffer) string {
+	if !re.compress || r == nil {
+		return ""
+	}
+
+	header := w.Header()
+	addVary(header, "Accept-Encoding")
+	if buf.Len() < re.compressMin || header.Get("Content-Encoding") != "" {
+		return ""
+	}
+
+	return acceptedEncoding(r.Header.Get("Accept-Encoding"))
+}
+
+// compressBody devuelve buf comprimido con encoding, la que ha elegido
+// responseEncoding, y pone las cabeceras Content-Encoding y Content-Length. Si
+// encoding está vacía devuelve buf tal cual. El búfer original vuelve al pool
+// cuando se sustituye.
+func (re *Render) compressBody(w http.ResponseWriter, buf *bytes.Buffer, encoding string) *bytes.Buffer {
+	if encoding == "" {
+		return buf
+	}
+
+	header := w.Header()
+	out := getBuffer()
+	var err error
+	switch encoding {
+	case "gzip":
+		zw := gzipWriters.Get().(*gzip.Writer)
+		err = compressTo(zw, out, buf)
+		gzipWriters.Put(zw)
+	case "deflate":
+		fw := flateWriters.Get().(*flate.Writer)
+		err = compressTo(fw, out, buf)
+		flateWriters.Put(fw)
+	}
+	if err != nil {
+		re.log().Warn("compressing response failed, sending it uncompressed", "error", err)
+		if etag := header.Get("ETag"); etag != "" {
+			header.Set("ETag", uncompressedETag(etag))
+		}
+		putBuffer(out)
+		return buf
+	}
+
+	header.Set("Content-Encoding", encoding)
+	header.Set("Content-Length", strconv.Itoa(out.Len()))
+
+	putBuffer(buf)
+	return out
+}
+
+// addVary añade name a la cabecera Vary si no está ya.
+func addVary(header http.Header, name string) {
+	for _, v := range header.Values("Vary") {
+		for _, field := range strings.Split(v, ",") {
+			field = strings.TrimSpace(field)
+			if field == "*" || strings.EqualFold(field, name) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", name)
+}
+
+// resetWriter es la parte común de gzip.Writer y flate.Writer.
+type resetWriter interface {
+	io.WriteCloser
+	Reset(io.Writer)
+}
+
+func compressTo(zw resetWriter, dst, src *bytes.Buffer) error {
+	zw.Reset(dst)
+	if _, err := zw.Write(src.Bytes()); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// acceptedEncoding elige gzip o deflate según la cabecera Accept-Encoding,
+// con preferencia por gzip. Devuelve una cadena vacía si no admite ninguna.
+func acceptedEncoding(header string) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if q, err := strconv.ParseFloat(v, 64); err == nil && q <= 0 {
+				continue
+			}
+		}
+		accepted[name] = true
+	}
+
+	switch {
+	case accepted["gzip"] || accepted["*"]:
+		return "gzip"
+	case accepted["deflate"]:
+		return "deflate"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,76 @@
+package gorender
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompression(t *testing.T) {
+	files := map[string]string{"pages/page.html": `{{ .Data.body }}`}
+	big := strings.Repeat("hola ", 1000)
+
+	tests := []struct {
+		name     string
+		opts     []OptionFunc
+		body     string
+		accept   string
+		encoding string
+		vary     bool
+	}{
+		{"disabled", nil, big, "gzip", "", false},
+		{"below min size", []OptionFunc{WithCompression(1024)}, "hola", "gzip", "", true},
+		{"not accepted", []OptionFunc{WithCompression(1024)}, big, "", "", true},
+		{"gzip", []OptionFunc{WithCompression(1024)}, big, "gzip, deflate", "gzip", true},
+		{"deflate", []OptionFunc{WithCompression(1024)}, big, "deflate", "deflate", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := newTestRender(t, files, tt.opts...)
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept-Encoding", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			td := &TemplateData{Data: map[string]interface{}{"body": tt.body}}
+			if err := re.Template(rec, req, "page.html", td); err != nil {
+				t.Fatalf("Template: %v", err)
+			}
+
+			if got := rec.Header().Get("Content-Encoding"); got != tt.encoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.encoding)
+			}
+			if got := rec.Header().Get("Vary") == "Accept-Encoding"; got != tt.vary {
+				t.Errorf("Vary = %q, want Accept-Encoding: %v", rec.Header().Get("Vary"), tt.vary)
+			}
+			if tt.encoding == "gzip" {
+				zr, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatalf("gzip.NewReader: %v", err)
+				}
+				data, err := io.ReadAll(zr)
+				if err != nil {
+					t.Fatalf("reading gzip body: %v", err)
+				}
+				if string(data) != tt.body {
+					t.Errorf("decompressed body has %d bytes, want %d", len(data), len(tt.body))
+				}
+			}
+		})
+	}
+}
+
+func TestAddVary(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/page.html": `ok`}, WithCompression(0))
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Vary", "accept-encoding")
+	if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "page.html", nil); err != nil {
+		t.Fatalf("Template: %v", err)
+	}
+	if got := rec.Header().Values("Vary"); len(got) != 1 {
+		t.Errorf("Vary = %q, want a single value", got)
+	}
+}
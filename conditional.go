@@ -0,0 +1,133 @@
+package gorender
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WithETag hace que las respuestas HTML lleven una cabecera ETag calculada a
+// partir del contenido y que, si la petición trae un If-None-Match que
+// coincide, se responda 304 sin cuerpo. El token CSRF y el nonce CSP se quitan
+// antes de calcularla, porque cambian en cada petición aunque la página sea
+// la misma; el token de la página que ya tiene el navegador sigue siendo
+// válido. Las páginas de exclude nunca llevan ETag.
+func WithETag(enabled bool, exclude ...string) OptionFunc {
+	return func(re *Render) {
+		re.etag = enabled
+		re.etagExclude = map[string]bool{}
+		for _, tmpl := range exclude {
+			re.etagExclude[tmpl] = true
+		}
+	}
+}
+
+// WithLastModified hace que las páginas lleven una cabecera Last-Modified con
+// la fecha de modificación más reciente entre su plantilla y las compartidas,
+// y que se responda 304 a las peticiones con un If-Modified-Since posterior.
+// Sólo tiene sentido en páginas cuyo contenido depende únicamente de las
+// plantillas; las demás se pueden excluir con TemplateData.SkipLastModified.
+func WithLastModified(enabled bool) OptionFunc {
+	return func(re *Render) {
+		re.lastModified = enabled
+	}
+}
+
+// notModified pone las cabeceras de validación de la respuesta y, si la
+// petición ya tiene la versión actual, responde 304 y devuelve true. Sólo se
+// aplica a las peticiones GET y HEAD que acaban en 200. Como indica el RFC
+// 9110, si la petición trae If-None-Match no se tiene en cuenta
+// If-Modified-Since.
+//
+// encoding es la compresión con la que se enviaría el cuerpo; el ETag lleva
+// su sufijo, como el de la respuesta 200, para que las cachés no mezclen las
+// dos variantes.
+func (re *Render) notModified(w http.ResponseWriter, r *http.Request, tmpl string, buf *bytes.Buffer, td *TemplateData, encoding string) bool {
+	if r == nil || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+		return false
+	}
+	if td.Status != 0 && td.Status != http.StatusOK {
+		return false
+	}
+
+	matched := false
+	if re.etag && !re.etagExclude[tmpl] {
+		etag := bodyETag(buf.Bytes(), td.CSRFToken, td.CSPNonce)
+		w.Header().Set("ETag", encodedETag(etag, encoding))
+		matched = etagMatches(r.Header.Get("If-None-Match"), etag)
+	}
+
+	if re.lastModified && !td.SkipLastModified && !td.modTime.IsZero() {
+		modTime := td.modTime.UTC().Truncate(time.Second)
+		w.Header().Set("Last-Modified", modTime.Format(http.TimeFormat))
+		if r.Header.Get("If-None-Match") == "" {
+			since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+			matched = err == nil && !modTime.After(since)
+		}
+	}
+
+	if !matched {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// bodyETag devuelve un ETag fuerte con el resumen de body sin las cadenas de
+// volatile, tal cual o escapadas como lo hace html/template.
+func bodyETag(body []byte, volatile ...string) string {
+	for _, v := range volatile {
+		if v == "" {
+			continue
+		}
+		escaped := strings.ReplaceAll(template.HTMLEscapeString(v), "+", "&#43;")
+		body = bytes.ReplaceAll(body, []byte(v), nil)
+		body = bytes.ReplaceAll(body, []byte(escaped), nil)
+	}
+
+	sum := sha256.Sum256(body)
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+// encodedETag añade a etag el sufijo de la compresión encoding, de modo que
+// la versión comprimida y la sin comprimir tengan ETag distintos.
+func encodedETag(etag, encoding string) string {
+	if encoding == "" {
+		return etag
+	}
+	return strings.TrimSuffix(etag, `"`) + "-" + encoding + `"`
+}
+
+// uncompressedETag quita el sufijo que encodedETag añade al ETag de las
+// respuestas comprimidas.
+func uncompressedETag(etag string) string {
+	for _, encoding := range []string{"gzip", "deflate"} {
+		if trimmed, ok := strings.CutSuffix(etag, "-"+encoding+`"`); ok {
+			return trimmed + `"`
+		}
+	}
+	return etag
+}
+
+// etagMatches compara etag con la cabecera If-None-Match. Como indica el RFC
+// 9110 se usa la comparación débil, así que W/"x" coincide con "x".
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || uncompressedETag(candidate) == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,233 @@
+package gorender
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestETag(t *testing.T) {
+	files := map[string]string{
+		"pages/index.html": `<form><input name="csrf" value="{{ .CSRFToken }}"></form>`,
+		"pages/other.html": `otra`,
+	}
+	tokens := 0
+	re := newTestRender(t, files, WithETag(true, "other.html"), WithCSRFTokenFunc(func(*http.Request) string {
+		tokens++
+		return strings.Repeat("t", tokens) + "+/="
+	}))
+
+	get := func(page, ifNoneMatch string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		if err := re.Template(rec, req, page, nil); err != nil {
+			t.Fatalf("Template: %v", err)
+		}
+		return rec
+	}
+
+	first := get("index.html", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("first response: status %d, ETag %q", first.Code, etag)
+	}
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		code        int
+	}{
+		{"match", etag, http.StatusNotModified},
+		{"weak validator", "W/" + etag, http.StatusNotModified},
+		{"in a list", `"other", ` + etag, http.StatusNotModified},
+		{"wildcard", "*", http.StatusNotModified},
+		{"mismatch", `"other"`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get("index.html", tt.ifNoneMatch)
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d", rec.Code, tt.code)
+			}
+			if got := rec.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag = %q, want %q with a different CSRF token", got, etag)
+			}
+			if tt.code == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("304 has a body: %q", rec.Body.String())
+			}
+		})
+	}
+
+	if rec := get("other.html", ""); rec.Header().Get("ETag") != "" {
+		t.Errorf("excluded page has ETag %q", rec.Header().Get("ETag"))
+	}
+}
+
+func TestETagOnlyForGetAndOK(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": `hola`}, WithETag(true))
+	etag := bodyETag([]byte("hola"))
+
+	tests := []struct {
+		name   string
+		method string
+		status int
+		code   int
+	}{
+		{"post", "POST", 0, http.StatusOK},
+		{"not found", "GET", http.StatusNotFound, http.StatusNotFound},
+		{"head", "HEAD", 0, http.StatusNotModified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			req.Header.Set("If-None-Match", etag)
+			rec := httptest.NewRecorder()
+			if err := re.Template(rec, req, "index.html", &TemplateData{Status: tt.status}); err != nil {
+				t.Fatalf("Template: %v", err)
+			}
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d", rec.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestETagWithCompression(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": `{{ .Data.body }}`}, WithETag(true), WithCompression(0))
+	td := func() *TemplateData {
+		return &TemplateData{Data: map[string]interface{}{"body": strings.Repeat("hola ", 100)}}
+	}
+	get := func(acceptEncoding, ifNoneMatch string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		if err := re.Template(rec, req, "index.html", td()); err != nil {
+			t.Fatalf("Template: %v", err)
+		}
+		return rec
+	}
+
+	for _, encoding := range []string{"gzip", "identity"} {
+		t.Run(encoding, func(t *testing.T) {
+			first := get(encoding, "")
+			etag := first.Header().Get("ETag")
+			if first.Code != http.StatusOK || etag == "" {
+				t.Fatalf("first response: status %d, ETag %q", first.Code, etag)
+			}
+			if compressed := first.Header().Get("Content-Encoding") == "gzip"; compressed != strings.HasSuffix(etag, `-gzip"`) {
+				t.Errorf("ETag %q does not match Content-Encoding %q", etag, first.Header().Get("Content-Encoding"))
+			}
+
+			rec := get(encoding, etag)
+			if rec.Code != http.StatusNotModified {
+				t.Fatalf("status = %d, want 304", rec.Code)
+			}
+			if got := rec.Header().Get("ETag"); got != etag {
+				t.Errorf("304 ETag = %q, want %q like the 200", got, etag)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("304 Vary = %q, want Accept-Encoding", got)
+			}
+			if rec.Header().Get("Content-Encoding") != "" || rec.Body.Len() != 0 {
+				t.Errorf("304 has Content-Encoding %q and a %d byte body", rec.Header().Get("Content-Encoding"), rec.Body.Len())
+			}
+		})
+	}
+
+	// El ETag comprimido también valida la versión sin comprimir.
+	gzipETag := get("gzip", "").Header().Get("ETag")
+	if rec := get("identity", gzipETag); rec.Code != http.StatusNotModified {
+		t.Errorf("status with the gzip ETag = %d, want 304", rec.Code)
+	}
+}
+
+func TestLastModified(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pages/index.html": &fstest.MapFile{Data: []byte(`{{ template "nav" }}hola`), ModTime: testTime},
+		"shared/nav.html":  &fstest.MapFile{Data: []byte(`{{ define "nav" }}<nav></nav>{{ end }}`), ModTime: testTime.Add(-time.Hour)},
+	}
+	re := newTestRender(t, nil, WithFS(fsys), WithLastModified(true))
+	lastModified := testTime.Format(http.TimeFormat)
+
+	get := func(ifModifiedSince string, td *TemplateData) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/", nil)
+		if ifModifiedSince != "" {
+			req.Header.Set("If-Modified-Since", ifModifiedSince)
+		}
+		rec := httptest.NewRecorder()
+		if err := re.Template(rec, req, "index.html", td); err != nil {
+			t.Fatalf("Template: %v", err)
+		}
+		return rec
+	}
+
+	first := get("", nil)
+	if got := first.Header().Get("Last-Modified"); first.Code != http.StatusOK || got != lastModified {
+		t.Fatalf("first response: status %d, Last-Modified %q, want %q", first.Code, got, lastModified)
+	}
+
+	tests := []struct {
+		name            string
+		ifModifiedSince string
+		code            int
+	}{
+		{"same date", lastModified, http.StatusNotModified},
+		{"later date", testTime.Add(time.Hour).Format(http.TimeFormat), http.StatusNotModified},
+		{"earlier date", testTime.Add(-time.Second).Format(http.TimeFormat), http.StatusOK},
+		{"invalid date", "ayer", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(tt.ifModifiedSince, nil)
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d", rec.Code, tt.code)
+			}
+			if got := rec.Header().Get("Last-Modified"); got != lastModified {
+				t.Errorf("Last-Modified = %q, want %q", got, lastModified)
+			}
+			if tt.code == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("304 has a body: %q", rec.Body.String())
+			}
+		})
+	}
+
+	t.Run("newer shared template", func(t *testing.T) {
+		fsys["shared/nav.html"].ModTime = testTime.Add(2 * time.Hour)
+		if err := re.Reload(); err != nil {
+			t.Fatalf("Reload: %v", err)
+		}
+
+		rec := get(lastModified, nil)
+		if want := testTime.Add(2 * time.Hour).Format(http.TimeFormat); rec.Header().Get("Last-Modified") != want {
+			t.Errorf("Last-Modified = %q, want %q", rec.Header().Get("Last-Modified"), want)
+		}
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d, want 200 after the shared template changed", rec.Code)
+		}
+	})
+
+	t.Run("SkipLastModified", func(t *testing.T) {
+		rec := get(lastModified, &TemplateData{SkipLastModified: true})
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d, want 200", rec.Code)
+		}
+		if got := rec.Header().Get("Last-Modified"); got != "" {
+			t.Errorf("Last-Modified = %q, want none", got)
+		}
+		if rec.Body.String() != "<nav></nav>hola" {
+			t.Errorf("body = %q", rec.Body.String())
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package gorender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// WithRenderTimeout limita lo que puede tardar cada ejecución de una
+// plantilla. Al superarlo la ejecución se detiene en la siguiente escritura y
+// se devuelve un error que envuelve ErrRenderTimeout, sin escribir nada en la
+// respuesta. Una función que se queda bloqueada sin que la plantilla escriba
+// no se interrumpe, pero sí se descarta su resultado.
+func WithRenderTimeout(d time.Duration) OptionFunc {
+	return func(re *Render) {
+		re.renderTimeout = d
+	}
+}
+
+// guardedWriter deja de escribir en cuanto se cancela ctx, para que una
+// plantilla no siga ejecutándose cuando el cliente se ha ido o se ha agotado
+// el tiempo.
+type guardedWriter struct {
+	w   io.Writer
+	ctx context.Context
+}
+
+func (g *guardedWriter) Write(p []byte) (int, error) {
+	if err := g.ctx.Err(); err != nil {
+		return 0, contextError(err)
+	}
+
+	return g.w.Write(p)
+}
+
+// WithMaxRenderSize limita a size bytes lo que puede ocupar una página. Si la
+// ejecución lo supera, por ejemplo por un range sobre una lista sin límite, se
+// detiene con un error que envuelve ErrRenderTooLarge y, como la página está
+// en el búfer, no se escribe nada. Con cero, lo normal, no hay límite.
+func WithMaxRenderSize(size int64) OptionFunc {
+	return func(re *Render) {
+		re.maxRenderSize = size
+	}
+}
+
+// limitedWriter devuelve un error en cuanto se escriben más de limit bytes.
+type limitedWriter struct {
+	w       io.Writer
+	tmpl    string
+	limit   int64
+	written int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.written+int64(len(p)) > l.limit {
+		return 0, fmt.Errorf("%w: %s exceeds %d bytes", ErrRenderTooLarge, l.tmpl, l.limit)
+	}
+
+	n, err := l.w.Write(p)
+	l.written += int64(n)
+
+	return n, err
+}
+
+// executeGuarded llama a exec con un writer sobre out que se detiene cuando se
+// cancela la petición, vence WithRenderTimeout o se supera WithMaxRenderSize,
+// y registra el error si lo hay. attrs se añaden al registro.
+func (re *Render) executeGuarded(out io.Writer, r *http.Request, tmpl string, exec func(io.Writer) error, attrs ...any) error {
+	ctx := context.Background()
+	if r != nil {
+		ctx = r.Context()
+	}
+	if re.renderTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, re.renderTimeout)
+		defer cancel()
+	}
+
+	w := out
+	if re.maxRenderSize > 0 {
+		w = &limitedWriter{w: w, tmpl: tmpl, limit: re.maxRenderSize}
+	}
+	if ctx.Done() != nil {
+		w = &guardedWriter{w: w, ctx: ctx}
+	}
+
+	err := runGuarded(tmpl, w, exec)
+	if err == nil && ctx.Err() != nil {
+		// La plantilla ha terminado sin volver a escribir después de vencer el
+		// plazo: el resultado tampoco se usa.
+		err = contextError(ctx.Err())
+	}
+	if err == nil {
+		return nil
+	}
+
+	attrs = append(attrs, "error", err)
+	if errors.Is(err, ErrClientGone) || errors.Is(err, ErrRenderTimeout) {
+		re.log().Warn("template execution aborted:", logAttrs(r, tmpl, attrs...)...)
+		return fmt.Errorf("%s: %w", tmpl, err)
+	}
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		re.log().Error("template execution panicked:", logAttrs(r, tmpl, append(attrs, "stack", string(panicErr.Stack))...)...)
+		// PanicError ya lleva el nombre de la plantilla.
+		return fmt.Errorf("%w: %w", ErrExecute, err)
+	}
+	if errors.Is(err, ErrRenderTooLarge) {
+		re.log().Error("template output too large:", logAttrs(r, tmpl, attrs...)...)
+		return err
+	}
+
+	re.log().Error("error executing template:", logAttrs(r, tmpl, attrs...)...)
+	return executeError(tmpl, err)
+}
+
+// runGuarded llama a exec y convierte en un PanicError cualquier panic, para
+// que una página rota no tumbe la petición entera. Como la página va a un
+// búfer, no sale nada de lo que se haya escrito. http.ErrAbortHandler se deja
+// pasar porque es la forma de cortar la respuesta a propósito.
+func runGuarded(tmpl string, w io.Writer, exec func(io.Writer) error) (err error) {
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+		if v == http.ErrAbortHandler {
+			panic(v)
+		}
+		err = &PanicError{Template: tmpl, Value: v, Stack: debug.Stack()}
+	}()
+
+	return exec(w)
+}
+
+// requestGone devuelve un error si la petición ya se ha cancelado y no merece
+// la pena escribir la respuesta.
+func requestGone(r *http.Request) error {
+	if r == nil {
+		return nil
+	}
+	if err := r.Context().Err(); err != nil {
+		return contextError(err)
+	}
+
+	return nil
+}
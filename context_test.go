@@ -0,0 +1,132 @@
+package gorender
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaxRenderSize(t *testing.T) {
+	files := map[string]string{"pages/big.html": `{{.Data.body}}`}
+	td := func() *TemplateData {
+		return &TemplateData{Data: map[string]interface{}{"body": strings.Repeat("x", 100000)}}
+	}
+
+	tests := []struct {
+		name string
+		opts []OptionFunc
+		ctx  func() (context.Context, context.CancelFunc)
+	}{
+		{"background", nil, func() (context.Context, context.CancelFunc) {
+			return context.Background(), func() {}
+		}},
+		{"cancelable", nil, func() (context.Context, context.CancelFunc) {
+			return context.WithCancel(context.Background())
+		}},
+		{"render timeout", []OptionFunc{WithRenderTimeout(time.Minute)}, func() (context.Context, context.CancelFunc) {
+			return context.Background(), func() {}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := newTestRender(t, files, append(tt.opts, WithMaxRenderSize(1000))...)
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+			err := re.Template(rec, req, "big.html", td())
+			if !errors.Is(err, ErrRenderTooLarge) {
+				t.Fatalf("Template error = %v, want ErrRenderTooLarge", err)
+			}
+			if !strings.Contains(err.Error(), "big.html") {
+				t.Errorf("error %q does not name the template", err)
+			}
+			if rec.Body.Len() != 0 {
+				t.Errorf("body has %d bytes, want none", rec.Body.Len())
+			}
+		})
+	}
+}
+
+func TestMaxRenderSizeUnlimited(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/big.html": `{{.Data.body}}`})
+
+	var buf bytes.Buffer
+	td := &TemplateData{Data: map[string]interface{}{"body": strings.Repeat("x", 100000)}}
+	if err := re.RenderTo(&buf, "big.html", td); err != nil {
+		t.Fatalf("RenderTo: %v", err)
+	}
+	if buf.Len() != 100000 {
+		t.Errorf("output has %d bytes, want 100000", buf.Len())
+	}
+}
+
+func TestPanickingFunc(t *testing.T) {
+	type user struct{ Name string }
+	re := newTestRender(t, map[string]string{"pages/profile.html": `<p>{{ userName .Data.user }}</p>`},
+		WithFunctions(template.FuncMap{"userName": func(u *user) string { return u.Name }}))
+
+	rec := httptest.NewRecorder()
+	td := &TemplateData{Data: map[string]interface{}{"user": (*user)(nil)}}
+	err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "profile.html", td)
+	if !errors.Is(err, ErrExecute) {
+		t.Fatalf("Template error = %v, want ErrExecute", err)
+	}
+	for _, want := range []string{"profile.html", "userName"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("body = %q, want none", rec.Body.String())
+	}
+
+	// Con WithAutoErrorPages el fallo acaba en la página de error.
+	re = newTestRender(t, map[string]string{
+		"pages/profile.html": `<p>{{ userName .Data.user }}</p>`,
+		"pages/500.html":     `error page`,
+	}, WithFunctions(template.FuncMap{"userName": func(u *user) string { return u.Name }}),
+		WithErrorTemplates(map[int]string{http.StatusInternalServerError: "500.html"}), WithAutoErrorPages(true))
+	rec = httptest.NewRecorder()
+	_ = re.Template(rec, httptest.NewRequest("GET", "/", nil), "profile.html", td)
+	if rec.Code != http.StatusInternalServerError || rec.Body.String() != "error page" {
+		t.Errorf("response = %d %q, want the 500 error page", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRunGuarded(t *testing.T) {
+	var buf bytes.Buffer
+	err := runGuarded("page.html", &buf, func(w io.Writer) error {
+		_, _ = io.WriteString(w, "partial")
+		var m map[string]int
+		m["x"] = 1
+		return nil
+	})
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("runGuarded error = %v, want a PanicError", err)
+	}
+	if panicErr.Template != "page.html" || len(panicErr.Stack) == 0 {
+		t.Errorf("PanicError = %+v, want the template name and the stack", panicErr)
+	}
+	if !strings.Contains(err.Error(), "page.html: panic: assignment to entry in nil map") {
+		t.Errorf("error = %q", err)
+	}
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler to pass through", v)
+		}
+	}()
+	_ = runGuarded("page.html", &buf, func(io.Writer) error { panic(http.ErrAbortHandler) })
+}
@@ -0,0 +1,56 @@
+package gorender
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCSPNonce(t *testing.T) {
+	files := map[string]string{"pages/index.html": `[{{ .CSPNonce }}]`}
+
+	t.Run("without CSP", func(t *testing.T) {
+		re := newTestRender(t, files)
+		rec := httptest.NewRecorder()
+		if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "index.html", nil); err != nil {
+			t.Fatalf("Template: %v", err)
+		}
+		if got := rec.Body.String(); got != "[]" {
+			t.Errorf("body = %q, want an empty nonce", got)
+		}
+		if h := rec.Header().Get("Content-Security-Policy"); h != "" {
+			t.Errorf("Content-Security-Policy = %q, want none", h)
+		}
+	})
+
+	t.Run("with CSP", func(t *testing.T) {
+		re := newTestRender(t, files, WithCSP("script-src 'nonce-{nonce}'"))
+		rec := httptest.NewRecorder()
+		if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "index.html", nil); err != nil {
+			t.Fatalf("Template: %v", err)
+		}
+		nonce := strings.Trim(rec.Body.String(), "[]")
+		if nonce == "" {
+			t.Fatal("no nonce rendered")
+		}
+		if h, want := rec.Header().Get("Content-Security-Policy"), "script-src 'nonce-"+nonce+"'"; h != want {
+			t.Errorf("Content-Security-Policy = %q, want %q", h, want)
+		}
+	})
+
+	t.Run("middleware without CSP", func(t *testing.T) {
+		re := newTestRender(t, files)
+		rec := httptest.NewRecorder()
+		var nonce string
+		re.CSPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nonce = re.CSPNonce(r)
+			if err := re.Template(w, r, "index.html", nil); err != nil {
+				t.Errorf("Template: %v", err)
+			}
+		})).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		if nonce == "" || rec.Body.String() != "["+nonce+"]" {
+			t.Errorf("body = %q, want the middleware nonce %q", rec.Body.String(), nonce)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package gorender
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+)
+
+// csvFlushRows es cada cuántas filas CSVStream envía al cliente lo escrito.
+const csvFlushRows = 500
+
+// utf8BOM es la marca de orden de bytes que necesita Excel para abrir un CSV
+// en UTF-8 sin estropear las tildes.
+const utf8BOM = "\uFEFF"
+
+// WithCSVBOM hace que CSV y CSVStream empiecen con la marca BOM de UTF-8, para
+// que Excel detecte la codificación al abrir el archivo.
+func WithCSVBOM(enabled bool) OptionFunc {
+	return func(re *Render) {
+		re.csvBOM = enabled
+	}
+}
+
+// CSV escribe headers y rows como un archivo CSV que el navegador descarga con
+// el nombre filename. Los valores se entrecomillan con encoding/csv cuando
+// hace falta. El archivo se prepara entero antes de escribirlo, así que ante
+// un error no se envía nada; para exportaciones grandes usa CSVStream.
+func (re *Render) CSV(w http.ResponseWriter, filename string, headers []string, rows [][]string) error {
+	if err := re.checkStarted(w, filename); err != nil {
+		return err
+	}
+
+	buf := getBuffer()
+	err := re.writeCSV(buf, headers, func(yield func([]string, error) bool) {
+		for _, row := range rows {
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}, nil)
+	if err != nil {
+		re.log().Error("error encoding csv:", "error", err)
+		putBuffer(buf)
+		return err
+	}
+
+	setCSVHeaders(w, filename)
+	setContentLength(w, buf, http.StatusOK)
+	w.WriteHeader(http.StatusOK)
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		re.log().Error("error writing csv to browser:", "error", err)
+		return fmt.Errorf("%w: %w", ErrWrite, err)
+	}
+
+	putBuffer(buf)
+	return nil
+}
+
+// CSVStream es como CSV pero escribe las filas a medida que las devuelve rows,
+// sin tenerlas todas en memoria, y las envía al cliente cada cierto número de
+// filas. Se detiene cuando rows devuelve un error o se cancela la petición;
+// como la respuesta ya ha empezado, el archivo queda incompleto y sólo se
+// devuelve el error. A las peticiones HEAD sólo se les envían las cabeceras,
+// sin llamar a rows.
+//
+// Ejemplo:
+//
+//	ren.CSVStream(w, r, "users.csv", []string{"id", "email"}, func(yield func([]string, error) bool) {
+//		for rows.Next() {
+//			var id, email string
+//			err := rows.Scan(&id, &email)
+//			if !yield([]string{id, email}, err) {
+//				return
+//			}
+//		}
+//	})
+func (re *Render) CSVStream(w http.ResponseWriter, r *http.Request, filename string, headers []string, rows iter.Seq2[[]string, error]) error {
+	if err := requestGone(r); err != nil {
+		return err
+	}
+	if err := re.checkStarted(w, filename); err != nil {
+		return err
+	}
+
+	setCSVHeaders(w, filename)
+	w.WriteHeader(http.StatusOK)
+
+	// A una petición HEAD no se le envía el cuerpo, así que no hace falta
+	// recorrer las filas.
+	if r.Method == http.MethodHead {
+		return nil
+	}
+
+	flusher, _ := w.(http.Flusher)
+	err := re.writeCSV(w, headers, rows, func(n int) error {
+		if err := requestGone(r); err != nil {
+			return err
+		}
+		if n%csvFlushRows == 0 && flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if errors.Is(err, ErrClientGone) || errors.Is(err, ErrRenderTimeout) {
+		re.log().Debug("request canceled while streaming csv", logAttrs(r, filename, "error", err)...)
+		return err
+	}
+	if err != nil {
+		re.log().Error("error streaming csv:", logAttrs(r, filename, "error", err)...)
+		return err
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	return nil
+}
+
+// writeCSV escribe en out la BOM, si se usa, la cabecera y las filas. each,
+// si no es nil, se llama después de cada fila con el número de filas escritas
+// y detiene la escritura si devuelve un error.
+func (re *Render) writeCSV(out io.Writer, headers []string, rows iter.Seq2[[]string, error], each func(int) error) error {
+	if re.csvBOM {
+		if _, err := io.WriteString(out, utf8BOM); err != nil {
+			return err
+		}
+	}
+
+	cw := csv.NewWriter(out)
+	if len(headers) > 0 {
+		if err := cw.Write(headers); err != nil {
+			return err
+		}
+	}
+
+	n := 0
+	for row, err := range rows {
+		if err != nil {
+			return err
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+		n++
+
+		if each == nil {
+			continue
+		}
+		if n%csvFlushRows == 0 {
+			cw.Flush()
+		}
+		if err := each(n); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func setCSVHeaders(w http.ResponseWriter, filename string) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", contentDisposition("attachment", filename))
+}
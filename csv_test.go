@@ -0,0 +1,87 @@
+package gorender
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCSV(t *testing.T) {
+	rows := [][]string{{"1", "Ana, la de \"arriba\""}, {"2", "línea\nnueva"}}
+
+	tests := []struct {
+		name string
+		opts []OptionFunc
+		want string
+	}{
+		{"default", nil, "id,name\n1,\"Ana, la de \"\"arriba\"\"\"\n2,\"línea\nnueva\"\n"},
+		{"bom", []OptionFunc{WithCSVBOM(true)}, utf8BOM + "id,name\n1,\"Ana, la de \"\"arriba\"\"\"\n2,\"línea\nnueva\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := newTestRender(t, nil, tt.opts...)
+			rec := httptest.NewRecorder()
+			if err := re.CSV(rec, "users.csv", []string{"id", "name"}, rows); err != nil {
+				t.Fatalf("CSV: %v", err)
+			}
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "text/csv; charset=utf-8" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			if got := rec.Header().Get("Content-Disposition"); !strings.Contains(got, "users.csv") {
+				t.Errorf("Content-Disposition = %q", got)
+			}
+		})
+	}
+}
+
+func TestCSVWriteError(t *testing.T) {
+	re := newTestRender(t, nil)
+
+	err := re.CSV(failingWriter{httptest.NewRecorder()}, "users.csv", []string{"id"}, [][]string{{"1"}})
+	if !errors.Is(err, ErrWrite) || !errors.Is(err, errBrokenPipe) {
+		t.Errorf("CSV error = %v, want ErrWrite wrapping the write error", err)
+	}
+}
+
+func TestCSVStream(t *testing.T) {
+	re := newTestRender(t, nil)
+	errRows := errors.New("rows failed")
+	rows := func(fail bool) func(yield func([]string, error) bool) {
+		return func(yield func([]string, error) bool) {
+			if !yield([]string{"1"}, nil) {
+				return
+			}
+			if fail {
+				yield(nil, errRows)
+				return
+			}
+			yield([]string{"2"}, nil)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	if err := re.CSVStream(rec, httptest.NewRequest("GET", "/", nil), "n.csv", []string{"n"}, rows(false)); err != nil {
+		t.Fatalf("CSVStream: %v", err)
+	}
+	if got := rec.Body.String(); got != "n\n1\n2\n" {
+		t.Errorf("body = %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	err := re.CSVStream(rec, httptest.NewRequest("GET", "/", nil), "n.csv", []string{"n"}, rows(true))
+	if !errors.Is(err, errRows) {
+		t.Errorf("CSVStream error = %v, want the rows error", err)
+	}
+
+	rec = httptest.NewRecorder()
+	if err := re.CSVStream(rec, httptest.NewRequest("HEAD", "/", nil), "n.csv", []string{"n"}, rows(false)); err != nil {
+		t.Fatalf("CSVStream HEAD: %v", err)
+	}
+	if rec.Body.Len() != 0 || rec.Header().Get("Content-Type") == "" {
+		t.Errorf("HEAD response: body %q, headers %v", rec.Body.String(), rec.Header())
+	}
+}
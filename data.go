@@ -0,0 +1,347 @@
+package gorender
+
+import (
+	"html/template"
+	"net/http"
+	"reflect"
+)
+
+// WithGlobalData indica datos comunes a todas las páginas, como el nombre del
+// sitio o la versión, que se añaden a TemplateData.Data y TemplateData.Global
+// en cada renderizado. Los valores de la petición prevalecen sobre los globales
+// con la misma clave.
+func WithGlobalData(data map[string]interface{}) OptionFunc {
+	return func(re *Render) {
+		re.globalMu.Lock()
+		defer re.globalMu.Unlock()
+
+		if re.globalData == nil {
+			re.globalData = map[string]interface{}{}
+		}
+		for k, v := range data {
+			re.globalData[k] = v
+		}
+	}
+}
+
+// SetGlobal cambia un dato global en tiempo de ejecución, por ejemplo para
+// mostrar u ocultar un aviso. Es seguro llamarlo mientras se renderiza.
+func (re *Render) SetGlobal(key string, value interface{}) {
+	re.globalMu.Lock()
+	defer re.globalMu.Unlock()
+
+	if re.globalData == nil {
+		re.globalData = map[string]interface{}{}
+	}
+	re.globalData[key] = value
+}
+
+// DeleteGlobal elimina un dato global en tiempo de ejecución.
+func (re *Render) DeleteGlobal(key string) {
+	re.globalMu.Lock()
+	defer re.globalMu.Unlock()
+
+	delete(re.globalData, key)
+}
+
+// baseData prepara td para renderizar: lo inicializa con initData y le añade
+// los datos globales.
+func (re *Render) baseData(td *TemplateData) *TemplateData {
+	td = initData(td)
+
+	re.globalMu.RLock()
+	defer re.globalMu.RUnlock()
+
+	td.Global = make(map[string]interface{}, len(re.globalData))
+	for k, v := range re.globalData {
+		td.Global[k] = v
+		if _, ok := td.Data[k]; !ok {
+			td.Data[k] = v
+		}
+	}
+
+	return td
+}
+
+// DefaultDataFunc rellena los datos por defecto de cada renderizado, como el
+// usuario de la sesión o los elementos del menú. Recibe la petición real para
+// poder leer su contexto y sus cookies.
+type DefaultDataFunc func(td *TemplateData, r *http.Request)
+
+// WithDefaultDataFunc registra funciones que rellenan los datos por defecto de
+// cada renderizado. Se ejecutan en el orden en que se registran, después de
+// añadir el token CSRF y antes de ejecutar la plantilla. Los valores que pasa
+// el manejador prevalecen sobre los que añaden estas funciones.
+func WithDefaultDataFunc(fns ...DefaultDataFunc) OptionFunc {
+	return func(re *Render) {
+		re.defaultDataFuncs = append(re.defaultDataFuncs, fns...)
+	}
+}
+
+// WithTemplateDataFactory indica una función que crea el TemplateData de partida
+// de cada renderizado, por ejemplo con los avisos de la sesión o un título por
+// defecto. Si el manejador pasa nil se usa el resultado de fn tal cual; si pasa
+// sus propios datos, se completan con los de fn: los mapas clave a clave y el
+// resto de campos sólo cuando el del manejador está vacío.
+func WithTemplateDataFactory(fn func(r *http.Request) *TemplateData) OptionFunc {
+	return func(re *Render) {
+		re.dataFactory = fn
+	}
+}
+
+// fromFactory combina td con los datos de partida de WithTemplateDataFactory.
+func (re *Render) fromFactory(td *TemplateData, r *http.Request) *TemplateData {
+	if re.dataFactory == nil {
+		return td
+	}
+
+	base := re.dataFactory(r)
+	if td == nil {
+		return base
+	}
+	if base == nil {
+		return td
+	}
+
+	td = initData(td)
+	fillMissing(td, initData(base))
+
+	return td
+}
+
+// runDefaultDataFuncs ejecuta las funciones de datos por defecto sobre un
+// TemplateData aparte y completa td con lo que le falte.
+func (re *Render) runDefaultDataFuncs(td *TemplateData, r *http.Request) {
+	for _, fn := range re.defaultDataFuncs {
+		defaults := initData(nil)
+		fn(defaults, r)
+		fillMissing(td, defaults)
+	}
+}
+
+// fillMissing completa dst con los valores de src que dst no tiene: en los
+// mapas clave a clave y en el resto de campos sólo cuando el de dst está vacío.
+func fillMissing(dst, src *TemplateData) {
+	if src == nil {
+		return
+	}
+
+	for k, v := range src.Data {
+		if _, ok := dst.Data[k]; !ok {
+			dst.Data[k] = v
+		}
+	}
+	for k, v := range src.FeedbackData {
+		if _, ok := dst.FeedbackData[k]; !ok {
+			dst.FeedbackData[k] = v
+		}
+	}
+	for k, v := range src.Messages {
+		if _, ok := dst.Messages[k]; !ok {
+			dst.Messages[k] = v
+		}
+	}
+	for k, v := range src.FormData.Errors {
+		if _, ok := dst.FormData.Errors[k]; !ok {
+			dst.FormData.Errors[k] = v
+		}
+	}
+	for k, v := range src.FormData.Values {
+		if _, ok := dst.FormData.Values[k]; !ok {
+			dst.FormData.Values[k] = v
+		}
+	}
+	dst.FormData.HasErrors = dst.FormData.HasErrors || src.FormData.HasErrors
+	for k, v := range src.Funcs {
+		if dst.Funcs == nil {
+			dst.Funcs = template.FuncMap{}
+		}
+		if _, ok := dst.Funcs[k]; !ok {
+			dst.Funcs[k] = v
+		}
+	}
+
+	if dst.Model == nil {
+		dst.Model = src.Model
+	}
+	dst.Meta.merge(src.Meta, false)
+	if len(dst.Breadcrumbs) == 0 {
+		dst.Breadcrumbs = src.Breadcrumbs
+	}
+	if dst.SessionData == nil {
+		dst.SessionData = src.SessionData
+	}
+	if dst.CSRFToken == "" {
+		dst.CSRFToken = src.CSRFToken
+	}
+	if dst.Page == (Pages{}) {
+		dst.Page = src.Page
+	}
+	if dst.CSPNonce == "" {
+		dst.CSPNonce = src.CSPNonce
+	}
+	if dst.Locale == "" {
+		dst.Locale = src.Locale
+	}
+	if dst.URL == nil {
+		dst.URL = src.URL
+	}
+	if dst.Request.isZero() {
+		dst.Request = src.Request
+	}
+	if dst.ContentType == "" {
+		dst.ContentType = src.ContentType
+	}
+	if dst.Status == 0 {
+		dst.Status = src.Status
+	}
+}
+
+// AsTemplateData convierte los datos que pasan los frameworks, de tipo
+// interface{}, en un TemplateData: un *TemplateData o un TemplateData se usan
+// tal cual, un mapa con claves de texto (echo.Map, gin.H, fiber.Map...) pasa a
+// ser Data, y cualquier otro valor se guarda en Data["Data"]. Con nil devuelve
+// nil.
+func AsTemplateData(data interface{}) *TemplateData {
+	switch data := data.(type) {
+	case nil:
+		return nil
+	case *TemplateData:
+		return data
+	case TemplateData:
+		return &data
+	case map[string]interface{}:
+		return &TemplateData{Data: data}
+	}
+
+	v := reflect.ValueOf(data)
+	if v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String {
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		return &TemplateData{Data: m}
+	}
+
+	return &TemplateData{Data: map[string]interface{}{"Data": data}}
+}
+
+// Set guarda un valor en Data, creando el mapa si hace falta, y devuelve td
+// para encadenar llamadas.
+//
+// Ejemplo:
+//
+//	td := (&gorender.TemplateData{}).Set("user", u).Set("posts", posts)
+func (td *TemplateData) Set(key string, value interface{}) *TemplateData {
+	if td.Data == nil {
+		td.Data = map[string]interface{}{}
+	}
+	td.Data[key] = value
+
+	return td
+}
+
+// Get devuelve el valor de Data con la clave indicada, o nil si no existe.
+func (td *TemplateData) Get(key string) interface{} {
+	if td == nil {
+		return nil
+	}
+
+	return td.Data[key]
+}
+
+// SetFeedback añade un mensaje del nivel indicado, igual que AddFeedback, y
+// devuelve td para encadenar llamadas.
+func (td *TemplateData) SetFeedback(level, message string) *TemplateData {
+	td.AddFeedback(level, message)
+
+	return td
+}
+
+// Merge copia en td los datos de other, que prevalecen sobre los de td: los
+// mapas se combinan clave a clave, los mensajes de Messages se añaden a los
+// que ya hubiera y el resto de campos sólo se copian si en other no están
+// vacíos. Devuelve td para encadenar llamadas.
+func (td *TemplateData) Merge(other *TemplateData) *TemplateData {
+	if other == nil {
+		return td
+	}
+
+	for k, v := range other.Data {
+		td.Set(k, v)
+	}
+	for k, v := range other.FeedbackData {
+		if td.FeedbackData == nil {
+			td.FeedbackData = map[string]string{}
+		}
+		td.FeedbackData[k] = v
+	}
+	for k, v := range other.Messages {
+		if td.Messages == nil {
+			td.Messages = map[string][]string{}
+		}
+		td.Messages[k] = append(td.Messages[k], v...)
+	}
+	for k, v := range other.FormData.Errors {
+		if td.FormData.Errors == nil {
+			td.FormData.Errors = map[string]string{}
+		}
+		td.FormData.Errors[k] = v
+	}
+	for k, v := range other.FormData.Values {
+		if td.FormData.Values == nil {
+			td.FormData.Values = map[string]string{}
+		}
+		td.FormData.Values[k] = v
+	}
+	td.FormData.HasErrors = td.FormData.HasErrors || other.FormData.HasErrors
+	for k, v := range other.Funcs {
+		if td.Funcs == nil {
+			td.Funcs = template.FuncMap{}
+		}
+		td.Funcs[k] = v
+	}
+
+	if other.Model != nil {
+		td.Model = other.Model
+	}
+	td.Meta.merge(other.Meta, true)
+	if len(other.Breadcrumbs) > 0 {
+		td.Breadcrumbs = other.Breadcrumbs
+	}
+	if other.SessionData != nil {
+		td.SessionData = other.SessionData
+	}
+	if other.CSRFToken != "" {
+		td.CSRFToken = other.CSRFToken
+	}
+	if other.Page != (Pages{}) {
+		td.Page = other.Page
+	}
+	if other.CSPNonce != "" {
+		td.CSPNonce = other.CSPNonce
+	}
+	if other.Locale != "" {
+		td.Locale = other.Locale
+	}
+	if other.URL != nil {
+		td.URL = other.URL
+	}
+	if !other.Request.isZero() {
+		td.Request = other.Request
+	}
+	if other.ContentType != "" {
+		td.ContentType = other.ContentType
+	}
+	if other.Status != 0 {
+		td.Status = other.Status
+	}
+	if other.Error != nil {
+		td.Error = other.Error
+	}
+	td.SkipLastModified = td.SkipLastModified || other.SkipLastModified
+	td.SkipMinify = td.SkipMinify || other.SkipMinify
+
+	return td
+}
@@ -0,0 +1,256 @@
+package gorender
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTemplateDataFactory(t *testing.T) {
+	factory := func(r *http.Request) *TemplateData {
+		return &TemplateData{
+			Data:         map[string]interface{}{"title": "Inicio", "site": "gorender"},
+			FeedbackData: map[string]string{"info": "flash"},
+			Status:       http.StatusAccepted,
+		}
+	}
+	files := map[string]string{
+		"pages/index.html": `{{ .Data.title }}|{{ .Data.site }}|{{ .FeedbackData.info }}|{{ .FeedbackData.error }}`,
+	}
+
+	tests := []struct {
+		name   string
+		td     *TemplateData
+		want   string
+		status int
+	}{
+		{"nil uses the factory", nil, "Inicio|gorender|flash|", http.StatusAccepted},
+		{
+			name: "maps merge per key",
+			td: &TemplateData{
+				Data:         map[string]interface{}{"title": "Mío"},
+				FeedbackData: map[string]string{"error": "mal"},
+			},
+			want:   "Mío|gorender|flash|mal",
+			status: http.StatusAccepted,
+		},
+		{
+			name:   "non-zero scalars win",
+			td:     &TemplateData{Status: http.StatusCreated},
+			want:   "Inicio|gorender|flash|",
+			status: http.StatusCreated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := newTestRender(t, files, WithTemplateDataFactory(factory))
+			rec := httptest.NewRecorder()
+			if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "index.html", tt.td); err != nil {
+				t.Fatalf("Template: %v", err)
+			}
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestTemplateDataFactoryNil(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": `{{ len .Data }}`},
+		WithTemplateDataFactory(func(*http.Request) *TemplateData { return nil }))
+
+	rec := httptest.NewRecorder()
+	if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "index.html", nil); err != nil {
+		t.Fatalf("Template: %v", err)
+	}
+	if got := rec.Body.String(); got != "0" {
+		t.Errorf("body = %q, want %q", got, "0")
+	}
+}
+
+func TestAsTemplateData(t *testing.T) {
+	td := &TemplateData{Status: http.StatusCreated}
+	if got := AsTemplateData(td); got != td {
+		t.Errorf("AsTemplateData(*TemplateData) = %p, want the same pointer %p", got, td)
+	}
+	if got := AsTemplateData(TemplateData{Status: http.StatusCreated}); got.Status != http.StatusCreated {
+		t.Errorf("AsTemplateData(TemplateData).Status = %d", got.Status)
+	}
+	if got := AsTemplateData(nil); got != nil {
+		t.Errorf("AsTemplateData(nil) = %+v, want nil", got)
+	}
+
+	type H map[string]interface{}
+	for _, data := range []interface{}{map[string]interface{}{"a": 1}, H{"a": 1}, map[string]int{"a": 1}} {
+		if got := AsTemplateData(data); got.Data["a"] != 1 {
+			t.Errorf("AsTemplateData(%T).Data = %v, want a=1", data, got.Data)
+		}
+	}
+
+	got := AsTemplateData([]int{1, 2})
+	if v, ok := got.Data["Data"].([]int); !ok || len(v) != 2 {
+		t.Errorf("AsTemplateData([]int).Data = %v, want the slice in Data[\"Data\"]", got.Data)
+	}
+}
+
+func TestTemplateDataHelpers(t *testing.T) {
+	var td TemplateData
+	td.Set("user", "ana").Set("posts", 3).SetFeedback(FeedbackError, "uno").SetFeedback(FeedbackError, "dos")
+
+	if got := td.Get("user"); got != "ana" {
+		t.Errorf("Get(user) = %v, want ana", got)
+	}
+	if got := td.Get("missing"); got != nil {
+		t.Errorf("Get(missing) = %v, want nil", got)
+	}
+	if got := (*TemplateData)(nil).Get("user"); got != nil {
+		t.Errorf("nil Get = %v, want nil", got)
+	}
+	if got := td.FeedbackData[FeedbackError]; got != "dos" {
+		t.Errorf("FeedbackData[error] = %q, want the last message", got)
+	}
+	if got := td.Messages[FeedbackError]; len(got) != 2 {
+		t.Errorf("Messages[error] = %q, want both messages", got)
+	}
+}
+
+func TestTemplateDataMerge(t *testing.T) {
+	td := (&TemplateData{Status: http.StatusCreated, CSRFToken: "mine", ContentType: "text/html"}).
+		Set("title", "Mío").Set("site", "gorender").SetFeedback(FeedbackInfo, "hola")
+	other := (&TemplateData{Status: http.StatusAccepted, ContentType: ""}).
+		Set("title", "Suyo").Set("extra", 1).SetFeedback(FeedbackInfo, "adiós").SetFeedback(FeedbackError, "mal")
+	other.FormData = NewForm()
+	other.FormData.AddError("email", "no válido")
+
+	td.Merge(other).Merge(nil)
+
+	// Los datos de other prevalecen sobre los de td.
+	wantData := map[string]interface{}{"title": "Suyo", "site": "gorender", "extra": 1}
+	for k, v := range wantData {
+		if td.Data[k] != v {
+			t.Errorf("Data[%s] = %v, want %v", k, td.Data[k], v)
+		}
+	}
+	if td.FeedbackData[FeedbackInfo] != "adiós" || td.FeedbackData[FeedbackError] != "mal" {
+		t.Errorf("FeedbackData = %v", td.FeedbackData)
+	}
+	if got := td.Messages[FeedbackInfo]; len(got) != 2 {
+		t.Errorf("Messages[info] = %q, want both messages appended", got)
+	}
+	if !td.FormData.HasErrors || td.FormData.Errors["email"] != "no válido" {
+		t.Errorf("FormData = %+v", td.FormData)
+	}
+
+	// Los campos vacíos de other no borran los de td.
+	if td.Status != http.StatusAccepted {
+		t.Errorf("Status = %d, want other's %d", td.Status, http.StatusAccepted)
+	}
+	if td.CSRFToken != "mine" || td.ContentType != "text/html" {
+		t.Errorf("CSRFToken = %q, ContentType = %q, want td's values kept", td.CSRFToken, td.ContentType)
+	}
+}
+
+func TestGlobalData(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/index.html": `{{ .Data.site }}|{{ .Data.banner }}|{{ .Global.site }}|{{ .Global.banner }}`,
+	}, WithGlobalData(map[string]interface{}{"site": "gorender", "banner": "aviso"}))
+
+	render := func(td *TemplateData) string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "index.html", td); err != nil {
+			t.Fatalf("Template: %v", err)
+		}
+		return rec.Body.String()
+	}
+
+	if got, want := render(nil), "gorender|aviso|gorender|aviso"; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+	// La petición prevalece en Data, pero Global conserva el valor global.
+	if got, want := render(NewData().Set("site", "mío").Build()), "mío|aviso|gorender|aviso"; got != want {
+		t.Errorf("body with request data = %q, want %q", got, want)
+	}
+
+	re.SetGlobal("banner", "nuevo")
+	if got, want := render(nil), "gorender|nuevo|gorender|nuevo"; got != want {
+		t.Errorf("body after SetGlobal = %q, want %q", got, want)
+	}
+	re.DeleteGlobal("banner")
+	if got, want := render(nil), "gorender||gorender|"; got != want {
+		t.Errorf("body after DeleteGlobal = %q, want %q", got, want)
+	}
+}
+
+func TestSetGlobalConcurrent(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": `{{ .Data.counter }}`})
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			re.SetGlobal("counter", i)
+			re.DeleteGlobal("other")
+		}()
+		go func() {
+			defer wg.Done()
+			if err := re.RenderTo(io.Discard, "index.html", nil); err != nil {
+				t.Errorf("RenderTo: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestDefaultDataFuncs(t *testing.T) {
+	var order []string
+	re := newTestRender(t, map[string]string{
+		"pages/index.html": `{{ .Data.user }}|{{ .Data.menu }}|{{ .Data.theme }}|{{ .Meta.Title }}|{{ .Data.path }}`,
+	}, WithDefaultDataFunc(
+		func(td *TemplateData, r *http.Request) {
+			order = append(order, "first")
+			td.Set("user", "invitado").Set("theme", "claro").Set("path", r.URL.Path)
+			td.Meta.Title = "Por defecto"
+		},
+		func(td *TemplateData, r *http.Request) {
+			order = append(order, "second")
+			td.Set("theme", "oscuro").Set("menu", "principal")
+		},
+	), WithDefaultDataFunc(func(td *TemplateData, r *http.Request) {
+		order = append(order, "third")
+	}))
+
+	tests := []struct {
+		name string
+		td   *TemplateData
+		want string
+	}{
+		// Ante la misma clave prevalece la función registrada antes.
+		{"defaults", nil, "invitado|principal|claro|Por defecto|/users"},
+		{"handler wins", &TemplateData{Data: map[string]interface{}{"user": "ana", "theme": "azul"}, Meta: Meta{Title: "Usuarios"}}, "ana|principal|azul|Usuarios|/users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order = nil
+			rec := httptest.NewRecorder()
+			if err := re.Template(rec, httptest.NewRequest("GET", "/users", nil), "index.html", tt.td); err != nil {
+				t.Fatalf("Template: %v", err)
+			}
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+			if got := strings.Join(order, ","); got != "first,second,third" {
+				t.Errorf("order = %q, want first,second,third", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package gorender
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// debugState es lo que muestra DebugHandler.
+type debugState struct {
+	Cached        bool            `json:"cached"`
+	Watch         bool            `json:"watch"`
+	LastBuild     time.Time       `json:"last_build"`
+	BuildDuration time.Duration   `json:"build_duration"`
+	Builds        int64           `json:"builds"`
+	Templates     []debugTemplate `json:"templates"`
+	Functions     []string        `json:"functions"`
+	Error         string          `json:"error,omitempty"`
+	Nonce         string          `json:"-"`
+}
+
+// debugTemplate es una página procesada con su archivo y sus bloques.
+type debugTemplate struct {
+	Name   string   `json:"name"`
+	Text   bool     `json:"text,omitempty"`
+	File   string   `json:"file,omitempty"`
+	Source string   `json:"source,omitempty"`
+	Blocks []string `json:"blocks"`
+}
+
+// DebugHandler devuelve un http.Handler que muestra lo que hay cargado: las
+// páginas con su archivo, su origen y sus bloques, las funciones registradas,
+// si se usa la caché y cuándo se construyó por última vez. Responde en HTML
+// salvo que la petición lleve "Accept: application/json" o "?format=json".
+//
+// No se registra en ninguna ruta por sí solo: es la aplicación la que decide
+// dónde montarlo y cómo protegerlo, porque expone la estructura interna del
+// sitio. Además, con la caché habilitada y sin WithWatch, que es lo normal en
+// producción, responde 404 salvo que se haya activado WithDebug, para que
+// montarlo por descuido no deje el sitio al descubierto.
+//
+// Ejemplo:
+//
+//	mux.Handle("/_templates", requireAdmin(ren.DebugHandler()))
+func (re *Render) DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if re.EnableCache && !re.watch && !re.debug {
+			re.log().Warn("debug handler disabled with the template cache enabled, use WithDebug to show it", "path", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+
+		state := re.debugState()
+		w.Header().Set("Cache-Control", "no-store")
+
+		if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json")
+			err := json.NewEncoder(w).Encode(state)
+			if err != nil {
+				re.log().Error("error writing debug state:", "error", err)
+			}
+			return
+		}
+
+		state.Nonce = re.requestNonce(r)
+		buf := getBuffer()
+		defer putBuffer(buf)
+		if err := debugStateTemplate.Execute(buf, state); err != nil {
+			re.log().Error("error rendering debug state:", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		re.setCSPHeader(w, &TemplateData{CSPNonce: state.Nonce})
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = buf.WriteTo(w)
+	})
+}
+
+// debugState reúne el estado que muestra DebugHandler.
+func (re *Render) debugState() debugState {
+	// Las plantillas se procesan antes de leer Stats para que, sin caché, la
+	// última construcción sea esta.
+	set, setErr := re.currentSet()
+
+	stats := re.Stats()
+	state := debugState{
+		Cached:        stats.Cached,
+		Watch:         re.watch,
+		LastBuild:     stats.LastBuild,
+		BuildDuration: stats.BuildDuration,
+		Builds:        stats.Builds,
+		Functions:     make([]string, 0, len(re.Functions)),
+	}
+	for name := range re.Functions {
+		state.Functions = append(state.Functions, name)
+	}
+	sort.Strings(state.Functions)
+
+	if setErr != nil {
+		state.Error = setErr.Error()
+		return state
+	}
+
+	for _, key := range sortedKeys(set.templates) {
+		var blocks []string
+		for _, t := range set.templates[key].Templates() {
+			if t.Tree != nil {
+				blocks = append(blocks, t.Name())
+			}
+		}
+		state.Templates = append(state.Templates, set.debugTemplate(key, false, blocks))
+	}
+	for _, key := range sortedKeys(set.texts) {
+		var blocks []string
+		for _, t := range set.texts[key].Templates() {
+			if t.Tree != nil {
+				blocks = append(blocks, t.Name())
+			}
+		}
+		state.Templates = append(state.Templates, set.debugTemplate(key, true, blocks))
+	}
+
+	return state
+}
+
+func (s *templateSet) debugTemplate(key string, text bool, blocks []string) debugTemplate {
+	sort.Strings(blocks)
+	t := debugTemplate{Name: key, Text: text, File: s.pageFiles[key], Blocks: blocks}
+	if t.File != "" {
+		t.Source = s.sources[t.File]
+	} else {
+		t.Source = s.sources[key]
+	}
+
+	return t
+}
+
+var debugStateTemplate = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Templates</title>
+<style nonce="{{ .Nonce }}">
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+.warning { background: #fdecea; border-left: 4px solid #b00020; padding: 1rem; }
+table { border-collapse: collapse; font-size: .9rem; }
+th, td { text-align: left; padding: .25rem .75rem; border-bottom: 1px solid #eee; vertical-align: top; }
+code { font-size: .85rem; }
+</style>
+</head>
+<body>
+<h1>Templates</h1>
+{{ if not .Cached }}<p class="warning">Template cache is disabled: every request parses the templates again.</p>{{ end }}
+{{ with .Error }}<p class="warning">{{ . }}</p>{{ end }}
+<p>Cache: {{ if .Cached }}enabled{{ else }}disabled{{ end }}{{ if .Watch }}, watching for changes{{ end }}.
+{{ if not .LastBuild.IsZero }}Last build: {{ .LastBuild.Format "2006-01-02 15:04:05" }} ({{ .BuildDuration }}), {{ .Builds }} builds.{{ end }}</p>
+<h2>Pages ({{ len .Templates }})</h2>
+<table>
+<tr><th>Name</th><th>File</th><th>Source</th><th>Templates</th></tr>
+{{ range .Templates }}<tr><td><code>{{ .Name }}</code>{{ if .Text }} (text){{ end }}</td><td><code>{{ .File }}</code></td><td>{{ .Source }}</td><td>{{ range $i, $b := .Blocks }}{{ if $i }}, {{ end }}<code>{{ $b }}</code>{{ end }}</td></tr>
+{{ end }}</table>
+<h2>Functions ({{ len .Functions }})</h2>
+<p>{{ range $i, $f := .Functions }}{{ if $i }}, {{ end }}<code>{{ $f }}</code>{{ end }}</p>
+</body>
+</html>
+`))
@@ -0,0 +1,88 @@
+package gorender
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugHandler(t *testing.T) {
+	files := map[string]string{
+		"pages/index.html":   `{{ template "footer" . }}{{ define "title" }}Inicio{{ end }}`,
+		"shared/footer.html": `{{ define "footer" }}pie{{ end }}`,
+	}
+	re := newTestRender(t, files, WithCache(false))
+
+	rec := httptest.NewRecorder()
+	re.DebugHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/_templates", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", got)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"Template cache is disabled", "<code>index.html</code>", "<code>footer</code>", "<code>title</code>", "<code>dict</code>"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("page does not contain %q", want)
+		}
+	}
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", "/_templates?format=json", nil),
+		func() *http.Request {
+			r := httptest.NewRequest("GET", "/_templates", nil)
+			r.Header.Set("Accept", "application/json")
+			return r
+		}(),
+	} {
+		rec := httptest.NewRecorder()
+		re.DebugHandler().ServeHTTP(rec, req)
+		if got := rec.Header().Get("Content-Type"); got != "application/json" {
+			t.Errorf("%s: Content-Type = %q, want application/json", req.URL, got)
+		}
+
+		var state debugState
+		if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+			t.Fatalf("%s: Unmarshal: %v", req.URL, err)
+		}
+		if state.Cached || len(state.Templates) != 1 || state.Templates[0].Name != "index.html" {
+			t.Errorf("%s: state = %+v, want the uncached index.html", req.URL, state)
+		}
+		if blocks := strings.Join(state.Templates[0].Blocks, ","); blocks != "footer,footer.html,index.html,title" {
+			t.Errorf("%s: blocks = %s", req.URL, blocks)
+		}
+	}
+}
+
+func TestDebugHandlerGuard(t *testing.T) {
+	files := map[string]string{"pages/index.html": `hola`}
+
+	tests := []struct {
+		name string
+		opts []OptionFunc
+		code int
+	}{
+		{"cache", nil, http.StatusNotFound},
+		{"cache and debug", []OptionFunc{WithDebug(true)}, http.StatusOK},
+		{"no cache", []OptionFunc{WithCache(false)}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := newTestRender(t, files, tt.opts...)
+			rec := httptest.NewRecorder()
+			re.DebugHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/_templates?format=json", nil))
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d", rec.Code, tt.code)
+			}
+			if tt.code == http.StatusNotFound && strings.Contains(rec.Body.String(), "index.html") {
+				t.Errorf("guarded handler leaked %q", rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package gorender
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// debugContextLines son las líneas que se muestran antes y después de la
+	// que ha fallado.
+	debugContextLines = 5
+	// debugMaxKeys y debugMaxValue limitan lo que se muestra de .Data.
+	debugMaxKeys  = 30
+	debugMaxValue = 300
+)
+
+// templateErrorPattern reconoce la posición de los errores de text/template y
+// html/template: "template: page.html:12: ..." al procesar y
+// "template: page.html:12:5: executing ..." al ejecutar.
+var templateErrorPattern = regexp.MustCompile(`(?:html/)?template: ?([^:\s]+):(\d+)(?::(\d+))?:`)
+
+// templateLocation es la posición de un error dentro de una plantilla.
+type templateLocation struct {
+	Name   string
+	Line   int
+	Column int
+}
+
+// parseTemplateError busca en msg la posición del error con el formato de los
+// paquetes de plantillas. Si no la encuentra devuelve false.
+func parseTemplateError(msg string) (templateLocation, bool) {
+	m := templateErrorPattern.FindStringSubmatch(msg)
+	if m == nil {
+		return templateLocation{}, false
+	}
+
+	loc := templateLocation{Name: m[1]}
+	loc.Line, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		loc.Column, _ = strconv.Atoi(m[3])
+	}
+
+	return loc, loc.Line > 0
+}
+
+// sourceLine es una línea del código mostrado en la página de depuración.
+type sourceLine struct {
+	Number  int
+	Text    string
+	Current bool
+}
+
+// debugDataItem es una clave de .Data con su valor ya formateado.
+type debugDataItem struct {
+	Key   string
+	Value string
+}
+
+type debugPage struct {
+	Status   int
+	Title    string
+	Message  string
+	Template string
+	File     string
+	Line     int
+	Column   int
+	Source   []sourceLine
+	Data     []debugDataItem
+	Nonce    string
+}
+
+// writeDebugPage responde con la página de desarrollo de WithDebug: el error,
+// el archivo y las líneas de alrededor de la que ha fallado, y el principio de
+// td.Data.
+func (re *Render) writeDebugPage(w http.ResponseWriter, r *http.Request, status int, tmpl string, err error, td *TemplateData) {
+	page := debugPage{
+		Status:   status,
+		Title:    http.StatusText(status),
+		Template: tmpl,
+		Nonce:    re.requestNonce(r),
+	}
+	if err != nil {
+		page.Message = err.Error()
+	}
+
+	if loc, ok := parseTemplateError(page.Message); ok {
+		page.Line, page.Column = loc.Line, loc.Column
+		page.File = re.templateFileFor(loc.Name, tmpl)
+		if page.File != "" {
+			if src, err := re.readTemplate(page.File); err == nil {
+				page.Source = sourceContext(string(src), loc.Line)
+			}
+		}
+	}
+
+	if td != nil {
+		page.Data = debugData(td.Data)
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := debugPageTemplate.Execute(buf, page); err != nil {
+		re.log().Error("error rendering debug page:", logAttrs(r, tmpl, "error", err)...)
+		http.Error(w, page.Message, status)
+		return
+	}
+
+	re.setCSPHeader(w, &TemplateData{CSPNonce: page.Nonce})
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_, _ = buf.WriteTo(w)
+}
+
+// templateFileFor busca el archivo del que viene la plantilla name, que es el
+// nombre de archivo con el que se ha procesado. Si varias plantillas se llaman
+// igual se prefiere la página tmpl.
+func (re *Render) templateFileFor(name, tmpl string) string {
+	var candidates []string
+	for _, root := range []string{re.PageTemplatesPath, re.TemplatesPath, re.layoutsDir()} {
+		files, err := re.findFiles(root, append(append([]string{}, re.extensions...), re.textExtensions...))
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			if path.Base(filepath.ToSlash(file)) == name {
+				candidates = append(candidates, file)
+			}
+		}
+	}
+
+	for _, file := range candidates {
+		if re.pageKey(file) == tmpl {
+			return file
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0]
+	}
+
+	return ""
+}
+
+// sourceContext devuelve las líneas de src alrededor de line.
+func sourceContext(src string, line int) []sourceLine {
+	lines := strings.Split(strings.TrimSuffix(src, "\n"), "\n")
+	if line > len(lines) {
+		return nil
+	}
+
+	from := max(1, line-debugContextLines)
+	to := min(len(lines), line+debugContextLines)
+
+	out := make([]sourceLine, 0, to-from+1)
+	for n := from; n <= to; n++ {
+		out = append(out, sourceLine{
+			Number:  n,
+			Text:    strings.TrimRight(lines[n-1], "\r"),
+			Current: n == line,
+		})
+	}
+
+	return out
+}
+
+// debugData formatea las primeras claves de data, ordenadas, acortando los
+// valores largos.
+func debugData(data map[string]interface{}) []debugDataItem {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if len(keys) > debugMaxKeys {
+		keys = keys[:debugMaxKeys]
+	}
+
+	items := make([]debugDataItem, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, debugDataItem{Key: key, Value: truncate(fmt.Sprintf("%+v", data[key]), debugMaxValue)})
+	}
+
+	return items
+}
+
+var debugPageTemplate = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Status }} {{ .Title }}</title>
+<style nonce="{{ .Nonce }}">
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+h1 { color: #b00020; font-size: 1.4rem; }
+.message { background: #fdecea; border-left: 4px solid #b00020; padding: 1rem; white-space: pre-wrap; font-family: monospace; }
+table { border-collapse: collapse; font-family: monospace; font-size: .9rem; }
+td { padding: 0 .75rem; vertical-align: top; white-space: pre; }
+td.n { color: #888; text-align: right; }
+tr.current { background: #fff3b0; font-weight: bold; }
+.data td { white-space: pre-wrap; border-bottom: 1px solid #eee; padding: .25rem .75rem; }
+</style>
+</head>
+<body>
+<h1>{{ .Status }} {{ .Title }}</h1>
+<p class="message">{{ .Message }}</p>
+{{ if .Template }}<p>Template: <code>{{ .Template }}</code></p>{{ end }}
+{{ if .File }}<p>File: <code>{{ .File }}:{{ .Line }}{{ if .Column }}:{{ .Column }}{{ end }}</code></p>{{ end }}
+{{ with .Source }}
+<table>
+{{ range . }}<tr{{ if .Current }} class="current"{{ end }}><td class="n">{{ .Number }}</td><td>{{ .Text }}</td></tr>
+{{ end }}</table>
+{{ end }}
+{{ with .Data }}
+<h2>Data</h2>
+<table class="data">
+{{ range . }}<tr><td>{{ .Key }}</td><td>{{ .Value }}</td></tr>
+{{ end }}</table>
+{{ end }}
+</body>
+</html>
+`))
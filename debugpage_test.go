@@ -0,0 +1,118 @@
+package gorender
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	texttemplate "text/template"
+)
+
+func TestParseTemplateError(t *testing.T) {
+	_, parseErr := texttemplate.New("page.html").Parse("a\nb\n{{ if }}")
+	_, htmlParseErr := htmltemplate.New("page.html").Parse("a\n{{ end }}")
+	execErr := htmltemplate.Must(htmltemplate.New("home.html").Parse("a\n\n  {{ .Missing.Field }}")).
+		Execute(&bytes.Buffer{}, map[string]interface{}{"Missing": 42})
+
+	tests := []struct {
+		name string
+		msg  string
+		want templateLocation
+		ok   bool
+	}{
+		{"text parse", parseErr.Error(), templateLocation{"page.html", 3, 0}, true},
+		{"html parse", htmlParseErr.Error(), templateLocation{"page.html", 2, 0}, true},
+		{"execute", execErr.Error(), templateLocation{"home.html", 3, 13}, true},
+		{"wrapped", "gorender: error executing template: home.html: template: home.html:37:15: executing \"home.html\" at <.x>: boom",
+			templateLocation{"home.html", 37, 15}, true},
+		{"subdirectory", `template: blog_post.html:4:2: executing "post" at <x>: boom`, templateLocation{"blog_post.html", 4, 2}, true},
+		{"no location", "something else failed", templateLocation{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseTemplateError(tt.msg)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("parseTemplateError(%q) = %+v, %v, want %+v, %v", tt.msg, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSourceContext(t *testing.T) {
+	src := strings.Repeat("line\n", 20)
+
+	got := sourceContext(src, 10)
+	if len(got) != 2*debugContextLines+1 || got[0].Number != 5 || got[len(got)-1].Number != 15 {
+		t.Fatalf("sourceContext around 10 = %+v", got)
+	}
+	for _, l := range got {
+		if l.Current != (l.Number == 10) {
+			t.Errorf("line %d Current = %v", l.Number, l.Current)
+		}
+	}
+
+	if got := sourceContext(src, 1); got[0].Number != 1 || !got[0].Current {
+		t.Errorf("sourceContext at the first line = %+v", got)
+	}
+	if got := sourceContext(src, 30); got != nil {
+		t.Errorf("sourceContext past the end = %+v, want nil", got)
+	}
+}
+
+func TestDebugPage(t *testing.T) {
+	td := func() *TemplateData {
+		return &TemplateData{Data: map[string]interface{}{"title": "<Hola>", "user": 42}}
+	}
+
+	tests := []struct {
+		name string
+		page string
+		src  string
+		want []string
+	}{
+		{"execute error", "home.html", "<h1>{{ .Data.title }}</h1>\n<p>\n{{ .Data.user.Name }}\n</p>", []string{
+			"500 Internal Server Error",
+			"pages/home.html:3:",
+			`<tr class="current"><td class="n">3</td><td>{{ .Data.user.Name }}</td></tr>`,
+			"<td>title</td><td>&lt;Hola&gt;</td>",
+		}},
+		{"parse error", "broken.html", "<h1>\n{{ if }}\n</h1>", []string{
+			"pages/broken.html:2",
+			`<tr class="current"><td class="n">2</td><td>{{ if }}</td></tr>`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := newTestRender(t, map[string]string{"pages/" + tt.page: tt.src}, WithCache(false), WithDebug(true))
+			rec := httptest.NewRecorder()
+			if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), tt.page, td()); err == nil {
+				t.Fatal("Template returned no error")
+			}
+			if rec.Code != http.StatusInternalServerError {
+				t.Errorf("status = %d, want 500", rec.Code)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(rec.Body.String(), w) {
+					t.Errorf("debug page does not contain %q:\n%s", w, rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestDebugPageOffByDefault(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/home.html": "{{ .Data.user.Name }}"}, WithCache(false))
+
+	req := httptest.NewRequest("GET", "/?debug=1", nil)
+	req.Header.Set("X-Debug", "1")
+	rec := httptest.NewRecorder()
+	td := &TemplateData{Data: map[string]interface{}{"user": 42}}
+	if err := re.Template(rec, req, "home.html", td); err == nil {
+		t.Fatal("Template returned no error")
+	}
+	if strings.Contains(rec.Body.String(), "user.Name") {
+		t.Errorf("template source leaked without WithDebug: %q", rec.Body.String())
+	}
+}
@@ -0,0 +1,95 @@
+package gorender
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Download envía content como un archivo que el navegador descarga con el
+// nombre filename, escapado en Content-Disposition según la RFC 6266 y con
+// filename* si no es ASCII. Si el manejador ya ha puesto un Content-Type se
+// respeta; si no, se deduce de la extensión de filename o del propio
+// contenido. Las peticiones con Range, If-Modified-Since y demás cabeceras
+// condicionales las resuelve http.ServeContent según modTime, que puede ser
+// cero si no se conoce.
+//
+// Ejemplo:
+//
+//	ren.Download(w, r, bytes.NewReader(pdf), "factura-2024-001.pdf", invoice.UpdatedAt)
+func (re *Render) Download(w http.ResponseWriter, r *http.Request, content io.ReadSeeker, filename string, modTime time.Time) error {
+	return re.serveFile(w, r, content, "attachment", filename, modTime)
+}
+
+// Inline es igual que Download pero pide al navegador que muestre el archivo,
+// como un PDF, en lugar de descargarlo. Si el usuario lo guarda se propone el
+// nombre filename.
+func (re *Render) Inline(w http.ResponseWriter, r *http.Request, content io.ReadSeeker, filename string, modTime time.Time) error {
+	return re.serveFile(w, r, content, "inline", filename, modTime)
+}
+
+func (re *Render) serveFile(w http.ResponseWriter, r *http.Request, content io.ReadSeeker, disposition, filename string, modTime time.Time) error {
+	if content == nil {
+		return errors.New("download content is nil")
+	}
+	if err := requestGone(r); err != nil {
+		re.log().Debug("request canceled before writing response", logAttrs(r, filename, "error", err)...)
+		return err
+	}
+	if err := re.checkStarted(w, filename); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Disposition", contentDisposition(disposition, filename))
+	http.ServeContent(w, r, filename, modTime, content)
+
+	return nil
+}
+
+// contentDisposition devuelve la cabecera Content-Disposition de tipo kind
+// con filename entrecomillado y escapado según la RFC 6266. Si no es ASCII se
+// añade en la forma filename* de la RFC 5987, con una versión ASCII en
+// filename para los clientes antiguos.
+func contentDisposition(kind, filename string) string {
+	if filename == "" {
+		return kind
+	}
+
+	ascii := make([]rune, 0, len(filename))
+	for _, r := range filename {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			r = '_'
+		}
+		ascii = append(ascii, r)
+	}
+	v := mime.FormatMediaType(kind, map[string]string{"filename": string(ascii)})
+	if v == "" {
+		return kind
+	}
+	if string(ascii) == filename {
+		return v
+	}
+
+	return v + "; filename*=UTF-8''" + extValue(filename)
+}
+
+// extValue codifica s para un parámetro extendido de la RFC 5987: sólo los
+// attr-char se dejan tal cual.
+func extValue(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("!#$&+-.^_`|~", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
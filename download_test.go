@@ -0,0 +1,77 @@
+package gorender
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInline(t *testing.T) {
+	re := newTestRender(t, nil)
+	pdf := "%PDF-1.4 contenido"
+
+	tests := []struct {
+		name        string
+		filename    string
+		header      http.Header
+		code        int
+		disposition string
+		contentType string
+		body        string
+	}{
+		{"pdf", "factura.pdf", nil, http.StatusOK, `inline; filename=factura.pdf`, "application/pdf", pdf},
+		{"utf-8 name", "factura ñ.pdf", nil, http.StatusOK, `inline; filename="factura _.pdf"; filename*=UTF-8''factura%20%C3%B1.pdf`, "application/pdf", pdf},
+		{"without name", "", nil, http.StatusOK, "inline", "application/pdf", pdf},
+		{"range", "factura.pdf", http.Header{"Range": {"bytes=0-3"}}, http.StatusPartialContent, `inline; filename=factura.pdf`, "application/pdf", "%PDF"},
+		{"not modified", "factura.pdf", http.Header{"If-Modified-Since": {testTime.Format(http.TimeFormat)}}, http.StatusNotModified, `inline; filename=factura.pdf`, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			for k, v := range tt.header {
+				req.Header[k] = v
+			}
+			rec := httptest.NewRecorder()
+			if err := re.Inline(rec, req, strings.NewReader(pdf), tt.filename, testTime); err != nil {
+				t.Fatalf("Inline: %v", err)
+			}
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d", rec.Code, tt.code)
+			}
+			if got := rec.Header().Get("Content-Disposition"); got != tt.disposition {
+				t.Errorf("Content-Disposition = %q, want %q", got, tt.disposition)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			if got := rec.Body.String(); got != tt.body {
+				t.Errorf("body = %q, want %q", got, tt.body)
+			}
+		})
+	}
+}
+
+func TestDownload(t *testing.T) {
+	re := newTestRender(t, nil)
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	if err := re.Download(rec, httptest.NewRequest("GET", "/", nil), strings.NewReader("a,b\n"), `copia "final".bin`, testTime); err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if got, want := rec.Header().Get("Content-Disposition"), `attachment; filename="copia \"final\".bin"`; got != want {
+		t.Errorf("Content-Disposition = %q, want %q", got, want)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/csv; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want the handler's", got)
+	}
+	if got := rec.Header().Get("Last-Modified"); got != testTime.Format(http.TimeFormat) {
+		t.Errorf("Last-Modified = %q", got)
+	}
+
+	if err := re.Download(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil, "x.bin", testTime); err == nil {
+		t.Error("Download with nil content: got nil error")
+	}
+}
@@ -0,0 +1,49 @@
+// Package echorender permite usar gorender como Renderer de Echo. Va en un
+// módulo aparte para que Echo sólo sea una dependencia de quien lo use.
+//
+// Ejemplo:
+//
+//	e := echo.New()
+//	e.Renderer = echorender.New(ren)
+//
+//	e.GET("/", func(c echo.Context) error {
+//	    return c.Render(http.StatusOK, "index.html", echo.Map{"title": "Inicio"})
+//	})
+package echorender
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/zepyrshut/gorender"
+)
+
+// Renderer implementa echo.Renderer sobre un gorender.Render.
+type Renderer struct {
+	render *gorender.Render
+}
+
+var _ echo.Renderer = (*Renderer)(nil)
+
+// New devuelve un Renderer que procesa las páginas con re.
+func New(re *gorender.Render) *Renderer {
+	return &Renderer{render: re}
+}
+
+// Render procesa la página name y la escribe en w. data se convierte con
+// gorender.AsTemplateData: un *gorender.TemplateData se usa tal cual, un
+// echo.Map pasa a ser .Data y cualquier otro valor queda en .Data.Data. Los
+// datos por defecto, como el token CSRF, se obtienen de la petición de c.
+func (e *Renderer) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
+	return e.renderRequest(w, name, data, c)
+}
+
+// requester es la parte de echo.Context que necesita Render.
+type requester interface {
+	Request() *http.Request
+}
+
+func (e *Renderer) renderRequest(w io.Writer, name string, data interface{}, c requester) error {
+	return e.render.RenderRequest(w, c.Request(), name, gorender.AsTemplateData(data))
+}
@@ -0,0 +1,65 @@
+package echorender
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/zepyrshut/gorender"
+)
+
+// fakeContext cumple requester sin necesitar un echo.Context completo.
+type fakeContext struct {
+	r *http.Request
+}
+
+func (c fakeContext) Request() *http.Request { return c.r }
+
+func TestRender(t *testing.T) {
+	re, err := gorender.NewE(
+		gorender.WithFS(fstest.MapFS{
+			"pages/index.html": &fstest.MapFile{Data: []byte(`{{ .CSRFToken }}|{{ .Data.title }}|{{ .Data.Data }}`)},
+			"shared":           &fstest.MapFile{Mode: fs.ModeDir},
+		}),
+		gorender.WithTemplatesPath("shared"),
+		gorender.WithPageTemplatesPath("pages"),
+		gorender.WithCache(true),
+		gorender.WithCSRFTokenFunc(func(r *http.Request) string { return r.Header.Get("X-Token") }),
+		gorender.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	if err != nil {
+		t.Fatalf("NewE: %v", err)
+	}
+	renderer := New(re)
+
+	tests := []struct {
+		name string
+		data interface{}
+		want string
+	}{
+		{"nil", nil, "abc||"},
+		{"template data", &gorender.TemplateData{Data: map[string]interface{}{"title": "Inicio"}}, "abc|Inicio|"},
+		{"map", map[string]interface{}{"title": "Inicio"}, "abc|Inicio|"},
+		{"named map", map[string]string{"title": "Inicio"}, "abc|Inicio|"},
+		{"other value", 42, "abc||42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("X-Token", "abc")
+
+			var buf bytes.Buffer
+			if err := renderer.renderRequest(&buf, "index.html", tt.data, fakeContext{req}); err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
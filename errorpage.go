@@ -0,0 +1,159 @@
+package gorender
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrorData describe el error que muestra una página de WithErrorTemplates.
+type ErrorData struct {
+	// Status es el código de estado HTTP de la respuesta.
+	Status int
+	// Title es el texto estándar del código, como "Not Found".
+	Title string
+	// Detail es el mensaje del error. Sólo se rellena con WithDebug, para no
+	// enseñar detalles internos a los usuarios.
+	Detail string
+}
+
+// WithErrorTemplates indica la página de cada código de estado que muestra
+// Error, con el mismo nombre que se usa en Template. Los errores 5xx sin
+// página propia usan la del 500.
+//
+// Ejemplo:
+//
+//	gorender.WithErrorTemplates(map[int]string{
+//		404: "errors/404.html",
+//		500: "errors/500.html",
+//	})
+func WithErrorTemplates(templates map[int]string) OptionFunc {
+	return func(re *Render) {
+		if re.errorTemplates == nil {
+			re.errorTemplates = map[int]string{}
+		}
+		for status, tmpl := range templates {
+			re.errorTemplates[status] = tmpl
+		}
+	}
+}
+
+// WithAutoErrorPages hace que Template, Block, Fragments, TemplateWithLayout y
+// Text respondan con Error cuando fallan antes de escribir nada: un 503 si se
+// agota WithRenderTimeout y un 500 en el resto de casos. El error se sigue
+// devolviendo para que el manejador pueda registrarlo, pero ya no debe
+// escribir en la respuesta.
+func WithAutoErrorPages(enabled bool) OptionFunc {
+	return func(re *Render) {
+		re.autoErrorPages = enabled
+	}
+}
+
+// WithDebug activa el modo de desarrollo: cuando un renderizado falla se
+// responde con una página que muestra el error, el archivo de la plantilla con
+// las líneas de alrededor de la que ha fallado y el principio de .Data, y las
+// páginas de Error reciben también el mensaje en .Error.Detail. Sólo se puede
+// activar con esta opción, nunca desde la petición, y no debe usarse en
+// producción porque enseña el código de las plantillas.
+func WithDebug(enabled bool) OptionFunc {
+	return func(re *Render) {
+		re.debug = enabled
+	}
+}
+
+// Error responde con la página de WithErrorTemplates para status, que recibe
+// el error en .Error. Si no hay página para ese código, o si la propia página
+// falla, responde con http.Error y el texto estándar del código. Con
+// WithProblemErrors, a las peticiones que prefieren JSON se les responde con
+// un Problem. Si se sabe que la respuesta ya ha empezado, ver TrackResponses,
+// no se escribe nada.
+//
+// Ejemplo:
+//
+//	user, err := store.User(id)
+//	if err != nil {
+//		ren.Error(w, r, http.StatusNotFound, err)
+//		return
+//	}
+func (re *Render) Error(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if responseStarted(w) {
+		_ = re.startedError(r, "error page")
+		return
+	}
+
+	data := &ErrorData{Status: status, Title: http.StatusText(status)}
+	if re.debug && err != nil {
+		data.Detail = err.Error()
+	}
+
+	if re.wantsProblem(r) {
+		w.Header().Add("Vary", "Accept")
+		_ = re.Problem(w, Problem{Status: status, Title: data.Title, Detail: data.Detail, Instance: r.URL.Path})
+		return
+	}
+
+	tmpl, ok := re.errorTemplate(status)
+	if !ok {
+		http.Error(w, data.Title, status)
+		return
+	}
+
+	td := re.addDefaultData(&TemplateData{Status: status, Error: data}, r)
+	buf := getBuffer()
+	renderErr := re.execute(buf, r, tmpl, td)
+	if renderErr == nil {
+		// Si falla al escribir, la respuesta puede estar a medias y no se
+		// escribe nada más.
+		_ = re.respond(w, r, tmpl, buf, td)
+		return
+	}
+	putBuffer(buf)
+
+	re.log().Error("error rendering error page:", logAttrs(r, tmpl, "status", status, "error", renderErr)...)
+	http.Error(w, data.Title, status)
+}
+
+// errorTemplate devuelve la página de error de status.
+func (re *Render) errorTemplate(status int) (string, bool) {
+	if tmpl, ok := re.errorTemplates[status]; ok {
+		return tmpl, true
+	}
+	if status >= 500 {
+		tmpl, ok := re.errorTemplates[http.StatusInternalServerError]
+		return tmpl, ok
+	}
+
+	return "", false
+}
+
+// finish se llama al terminar cada renderizado: avisa a WithMetricsHook y, si
+// ha fallado, responde con la página de desarrollo de WithDebug o con la de
+// error de WithAutoErrorPages.
+func (re *Render) finish(w http.ResponseWriter, r *http.Request, start time.Time, tmpl string, td *TemplateData, err error) {
+	re.observe(start, tmpl, td, err)
+
+	if err == nil || (!re.autoErrorPages && !re.debug) {
+		return
+	}
+	re.renderFailed(w, r, tmpl, td, err)
+}
+
+// renderFailed responde a un renderizado de tmpl que ha fallado con err: con
+// la página de desarrollo si está activo WithDebug y con Error en otro caso.
+func (re *Render) renderFailed(w http.ResponseWriter, r *http.Request, tmpl string, td *TemplateData, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrClientGone), errors.Is(err, ErrWrite), errors.Is(err, ErrResponseStarted):
+		// No hay a quién responder o la respuesta ya se ha empezado a
+		// escribir.
+		return
+	case errors.Is(err, ErrRenderTimeout):
+		status = http.StatusServiceUnavailable
+	}
+
+	if re.debug {
+		re.writeDebugPage(w, r, status, tmpl, err, td)
+		return
+	}
+	re.Error(w, r, status, err)
+}
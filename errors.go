@@ -0,0 +1,104 @@
+package gorender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrTemplateNotFound indica que la plantilla solicitada no está en la
+	// caché, normalmente por un nombre mal escrito.
+	ErrTemplateNotFound = errors.New("template not found")
+	// ErrExecute indica que la plantilla existe pero ha fallado al ejecutarse.
+	ErrExecute = errors.New("template execution failed")
+	// ErrWrite indica que la plantilla se ha ejecutado pero no se ha podido
+	// escribir en la respuesta, normalmente porque el cliente se ha ido.
+	ErrWrite = errors.New("writing response failed")
+	// ErrClientGone indica que la petición se ha cancelado, normalmente porque
+	// el cliente ha cerrado la conexión, y no se ha escrito la respuesta.
+	ErrClientGone = errors.New("client disconnected")
+	// ErrRenderTimeout indica que la plantilla ha superado el tiempo de
+	// WithRenderTimeout o el plazo del contexto de la petición.
+	ErrRenderTimeout = errors.New("render timeout exceeded")
+	// ErrAmbiguousTemplate indica que se ha pedido una página por su nombre de
+	// archivo y hay varias con ese nombre en distintos directorios. Hay que
+	// usar su ruta relativa a PageTemplatesPath.
+	ErrAmbiguousTemplate = errors.New("ambiguous template name")
+	// ErrFunctionCollision indica que, con WithStrictFunctions, se ha
+	// registrado una función con el nombre de otra que ya existía.
+	ErrFunctionCollision = errors.New("template function already defined")
+	// ErrSitemapTooLarge indica que las direcciones no caben en un solo
+	// sitemap y no se ha configurado WithSitemapPages.
+	ErrSitemapTooLarge = errors.New("sitemap exceeds size limits")
+	// ErrSitemapPageNotFound indica que se ha pedido a SitemapPage una parte
+	// que no existe.
+	ErrSitemapPageNotFound = errors.New("sitemap page not found")
+	// ErrUnsafeRedirect indica que Redirect se ha negado a enviar al usuario
+	// a otro sitio que no está en WithRedirectHosts.
+	ErrUnsafeRedirect = errors.New("unsafe redirect")
+	// ErrNotAcceptable indica que la petición no acepta ninguno de los tipos
+	// que ofrece Negotiate.
+	ErrNotAcceptable = errors.New("not acceptable")
+	// ErrResponseStarted indica que el manejador, u otro middleware, ya había
+	// empezado a escribir la respuesta y no se ha escrito nada más. Sólo se
+	// detecta con TrackResponses o un ResponseWriter con Written() bool.
+	ErrResponseStarted = errors.New("response already started")
+	// ErrRenderTooLarge indica que una página ha superado el tamaño de
+	// WithMaxRenderSize.
+	ErrRenderTooLarge = errors.New("rendered output too large")
+)
+
+// notFoundError devuelve un error que envuelve ErrTemplateNotFound con el
+// nombre solicitado y las plantillas disponibles.
+func notFoundError(name string, available []string) error {
+	sorted := append([]string{}, available...)
+	sort.Strings(sorted)
+
+	return fmt.Errorf("%w: %q, available templates: %s", ErrTemplateNotFound, name, strings.Join(sorted, ", "))
+}
+
+// ambiguousError devuelve un error que envuelve ErrAmbiguousTemplate con el
+// nombre solicitado y los archivos de las páginas que lo comparten.
+func ambiguousError(name string, files []string) error {
+	return fmt.Errorf("%w: %q matches %s, use the relative path", ErrAmbiguousTemplate, name, strings.Join(files, ", "))
+}
+
+// PanicError es el error de una página cuya ejecución ha provocado un panic
+// que html/template no ha recogido. Los de las funciones del FuncMap y los
+// métodos que se llaman desde la plantilla ya los convierte html/template en
+// errores normales, con el nombre de la función.
+type PanicError struct {
+	Template string
+	Value    any
+	// Stack es la pila en el momento del panic.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: panic: %v", e.Template, e.Value)
+}
+
+// Unwrap devuelve el valor del panic si es un error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// executeError envuelve un error de ejecución con ErrExecute y el nombre de la
+// plantilla.
+func executeError(name string, err error) error {
+	return fmt.Errorf("%w: %s: %w", ErrExecute, name, err)
+}
+
+// contextError convierte el error de un contexto en ErrRenderTimeout si ha
+// vencido su plazo o en ErrClientGone si se ha cancelado.
+func contextError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrRenderTimeout, err)
+	}
+
+	return fmt.Errorf("%w: %w", ErrClientGone, err)
+}
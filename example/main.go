@@ -1,9 +1,11 @@
 package main
 
 import (
+	"expvar"
 	"fmt"
 	"net/http"
 	"text/template"
+	"time"
 
 	"github.com/zepyrshut/gorender"
 )
@@ -12,6 +14,23 @@ func dummyFunc() string {
 	return "dummy function"
 }
 
+// Contadores publicados por expvar en /debug/vars.
+var (
+	renders      = expvar.NewMap("gorender_renders")
+	renderErrors = expvar.NewMap("gorender_errors")
+	renderNanos  = expvar.NewMap("gorender_render_ns")
+)
+
+// renderMetrics cuenta los renderizados de cada página, el tiempo que han
+// tardado y los errores de cada tipo.
+func renderMetrics(tmpl string, dur time.Duration, status string, err error) {
+	renders.Add(tmpl, 1)
+	renderNanos.Add(tmpl, dur.Nanoseconds())
+	if err != nil {
+		renderErrors.Add(status, 1)
+	}
+}
+
 func main() {
 	newFuncs := template.FuncMap{
 		"dummyFunc": dummyFunc,
@@ -24,7 +43,10 @@ func main() {
 		Functions:         newFuncs,
 	}
 
-	ren := gorender.New(gorender.WithRenderOptions(renderOpts))
+	ren := gorender.New(
+		gorender.WithRenderOptions(renderOpts),
+		gorender.WithMetricsHook(renderMetrics),
+	)
 
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		td := &gorender.TemplateData{}
@@ -32,6 +54,24 @@ func main() {
 		ren.Template(w, r, "page.html", td)
 	})
 
+	http.HandleFunc("/form", func(w http.ResponseWriter, r *http.Request) {
+		td := &gorender.TemplateData{}
+
+		if r.Method == http.MethodPost {
+			td.FormData = gorender.NewFormData(r)
+			td.FormData.Required("email")
+			td.FormData.IsEmail("email")
+			if td.FormData.Valid() {
+				ren.Flash(w, r, gorender.FeedbackSuccess, "Sent!")
+				http.Redirect(w, r, "/form", http.StatusSeeOther)
+				return
+			}
+			td.Status = http.StatusUnprocessableEntity
+		}
+
+		ren.Template(w, r, "form.html", td)
+	})
+
 	fmt.Println("Server running on port 8080")
 	http.ListenAndServe(":8080", nil)
 }
@@ -0,0 +1,123 @@
+package gorender
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// WithExclude indica patrones de archivos y directorios que no se buscan como
+// plantillas, como maquetas, node_modules o páginas retiradas que ya no se
+// pueden procesar. Los patrones usan la sintaxis de path.Match con barras
+// normales y se comparan con la ruta relativa al directorio que se recorre,
+// TemplatesPath o PageTemplatesPath. "**" equivale a cualquier cantidad de
+// directorios, incluida ninguna.
+//
+// Ejemplo:
+//
+//	gorender.WithExclude("**/node_modules/**", "archive/*", "**/*.draft.html")
+//
+// Los directorios que coinciden con algún patrón no se llegan a recorrer.
+func WithExclude(patterns ...string) OptionFunc {
+	return func(re *Render) {
+		for _, pattern := range patterns {
+			if pattern == "" {
+				continue
+			}
+			re.exclude = append(re.exclude, strings.Trim(filepath.ToSlash(pattern), "/"))
+		}
+	}
+}
+
+// skipExcluded indica si p se salta al recorrer root por coincidir con alguno
+// de los patrones de WithExclude. Si p es un directorio devuelve además
+// fs.SkipDir para no recorrerlo. Las páginas que están dentro de TemplatesPath
+// se comparan también con su ruta relativa a PageTemplatesPath, para que un
+// mismo patrón sirva en los dos recorridos.
+func (re *Render) skipExcluded(root, p string, d fs.DirEntry) (bool, error) {
+	if len(re.exclude) == 0 {
+		return false, nil
+	}
+
+	var names []string
+	for _, base := range []string{root, re.root(re.PageTemplatesPath)} {
+		rel, err := filepath.Rel(base, p)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		names = append(names, filepath.ToSlash(rel))
+	}
+
+	for _, pattern := range re.exclude {
+		if !matchesAny(pattern, names, d.IsDir()) {
+			continue
+		}
+
+		if d.IsDir() {
+			re.log().Debug("template directory excluded", "path", p, "pattern", pattern)
+			return true, fs.SkipDir
+		}
+		re.log().Debug("template excluded", "template", p, "pattern", pattern)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// matchesAny indica si alguno de names coincide con pattern. Un directorio
+// también coincide si pattern excluye todo su contenido.
+func matchesAny(pattern string, names []string, dir bool) bool {
+	for _, name := range names {
+		if matchGlob(pattern, name) || (dir && matchGlob(excludedDir(pattern), name)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// excludedDir devuelve el directorio cuyo contenido excluye por completo
+// pattern, como "archive" para "archive/**", o pattern tal cual si no termina
+// así.
+func excludedDir(pattern string) string {
+	for _, suffix := range []string{"/**", "/*"} {
+		if dir, ok := strings.CutSuffix(pattern, suffix); ok {
+			return dir
+		}
+	}
+
+	return pattern
+}
+
+// matchGlob indica si name coincide con pattern. Los dos usan barras normales
+// y cada elemento se compara con path.Match, salvo "**", que equivale a
+// cualquier cantidad de elementos.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		ok, err := path.Match(pattern[0], name[0])
+		if err != nil || !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
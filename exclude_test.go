@@ -0,0 +1,65 @@
+package gorender
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"archive/*", "archive/old.html", true},
+		{"archive/*", "archive/2020/old.html", false},
+		{"archive/**", "archive/2020/old.html", true},
+		{"**/node_modules/**", "node_modules/pkg/index.html", true},
+		{"**/node_modules/**", "admin/node_modules/pkg/index.html", true},
+		{"**/node_modules/**", "admin/modules/index.html", false},
+		{"**/*.draft.html", "post.draft.html", true},
+		{"**/*.draft.html", "blog/2024/post.draft.html", true},
+		{"**/*.draft.html", "blog/post.html", false},
+		{"blog/**/draft.html", "blog/draft.html", true},
+		{"blog/**/draft.html", "blog/a/b/draft.html", true},
+		{"blog/**/draft.html", "news/a/draft.html", false},
+		{"*.html", "blog/index.html", false},
+		{"[", "[", false},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWithExclude(t *testing.T) {
+	files := map[string]string{
+		"pages/index.html":                  `inicio {{ template "footer" . }}`,
+		"pages/archive/2020/old.html":       `{{ .Broken`,
+		"pages/blog/post.draft.html":        `{{ end }}`,
+		"pages/blog/post.html":              `post`,
+		"shared/footer.html":                `{{ define "footer" }}pie{{ end }}`,
+		"shared/mocks/footer.html":          `{{ define "footer" }}maqueta{{ end }}`,
+		"shared/node_modules/pkg/page.html": `{{ if }}`,
+	}
+	re := newTestRender(t, files, WithExclude("archive/**", "**/*.draft.html", "mocks/*", "**/node_modules/**"))
+
+	if got, err := re.TemplateString("index.html", nil); err != nil || got != "inicio pie" {
+		t.Errorf("index.html = %q, %v, want the footer from the partial that is not excluded", got, err)
+	}
+	if got, err := re.TemplateString("blog/post.html", nil); err != nil || got != "post" {
+		t.Errorf("blog/post.html = %q, %v", got, err)
+	}
+
+	for _, page := range []string{"archive/2020/old.html", "blog/post.draft.html", "post.draft.html"} {
+		if _, err := re.TemplateString(page, nil); !errors.Is(err, ErrTemplateNotFound) {
+			t.Errorf("%s error = %v, want ErrTemplateNotFound", page, err)
+		}
+	}
+	for _, key := range re.Templates() {
+		if strings.Contains(key, "archive") || strings.Contains(key, "draft") {
+			t.Errorf("excluded page %s is in the cache", key)
+		}
+	}
+}
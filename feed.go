@@ -0,0 +1,283 @@
+package gorender
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// FeedFormat es el formato en el que Feed escribe la fuente.
+type FeedFormat int
+
+const (
+	// FeedRSS es RSS 2.0, con fechas RFC 822.
+	FeedRSS FeedFormat = iota
+	// FeedAtom es Atom 1.0, con fechas RFC 3339.
+	FeedAtom
+)
+
+// Feed es una fuente de contenidos, como las entradas de un blog.
+type Feed struct {
+	Title       string
+	Link        string
+	Description string
+	// Author es el autor de la fuente, que Atom exige si alguna entrada no
+	// tiene el suyo.
+	Author string
+	// Updated es la fecha de la última actualización. Si es cero se usa la
+	// más reciente de las entradas.
+	Updated time.Time
+	Items   []FeedItem
+}
+
+// FeedItem es una entrada de la fuente. Los campos vacíos se omiten.
+type FeedItem struct {
+	Title string
+	Link  string
+	// ID identifica la entrada de forma permanente. Si está vacío se usa
+	// Link.
+	ID          string
+	Description string
+	// Content es el contenido completo en HTML, que se escapa al escribirlo.
+	Content string
+	// Author es el autor de la entrada; RSS espera una dirección de correo.
+	Author    string
+	Published time.Time
+	Updated   time.Time
+}
+
+// Feed escribe feed en el formato indicado, con el Content-Type de ese
+// formato. La fuente necesita Title y Link, y cada entrada Link o ID. Igual
+// que XML, se codifica sobre un búfer para no dejar respuestas a medias si
+// falla.
+//
+// Ejemplo:
+//
+//	ren.Feed(w, gorender.Feed{Title: "Blog", Link: "https://example.com/blog", Items: items}, gorender.FeedAtom)
+func (re *Render) Feed(w http.ResponseWriter, feed Feed, format FeedFormat) error {
+	if feed.Title == "" || feed.Link == "" {
+		return errors.New("feed title and link are required")
+	}
+	for i, item := range feed.Items {
+		if item.Link == "" && item.ID == "" {
+			return fmt.Errorf("feed item %d: link or id is required", i)
+		}
+	}
+
+	var v any
+	var contentType string
+	switch format {
+	case FeedRSS:
+		v, contentType = rssFeed(feed), "application/rss+xml; charset=utf-8"
+	case FeedAtom:
+		v, contentType = atomFeed(feed), "application/atom+xml; charset=utf-8"
+	default:
+		return fmt.Errorf("unknown feed format %d", format)
+	}
+	if err := re.checkStarted(w, "feed"); err != nil {
+		return err
+	}
+
+	buf := getBuffer()
+	buf.WriteString(xml.Header)
+
+	err := xml.NewEncoder(buf).Encode(v)
+	if err != nil {
+		re.log().Error("error encoding feed:", "error", err)
+		putBuffer(buf)
+		return err
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	setContentLength(w, buf, http.StatusOK)
+	w.WriteHeader(http.StatusOK)
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		re.log().Error("error writing feed to browser:", "error", err)
+		return fmt.Errorf("%w: %w", ErrWrite, err)
+	}
+
+	putBuffer(buf)
+	return nil
+}
+
+// updated devuelve la fecha de la fuente o, si no tiene, la más reciente de
+// sus entradas.
+func (f Feed) updated() time.Time {
+	updated := f.Updated
+	if updated.IsZero() {
+		for _, item := range f.Items {
+			if t := item.updated(); t.After(updated) {
+				updated = t
+			}
+		}
+	}
+
+	return updated
+}
+
+func (item FeedItem) updated() time.Time {
+	if item.Updated.IsZero() {
+		return item.Published
+	}
+	return item.Updated
+}
+
+func (item FeedItem) id() string {
+	if item.ID == "" {
+		return item.Link
+	}
+	return item.ID
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Content string     `xml:"xmlns:content,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title,omitempty"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Content     string   `xml:"content:encoded,omitempty"`
+	Author      string   `xml:"author,omitempty"`
+	GUID        *rssGUID `xml:"guid"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func rssFeed(f Feed) rss {
+	channel := rssChannel{
+		Title:         f.Title,
+		Link:          f.Link,
+		Description:   f.Description,
+		LastBuildDate: formatFeedTime(f.updated(), time.RFC1123Z),
+	}
+	for _, item := range f.Items {
+		channel.Items = append(channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Description,
+			Content:     item.Content,
+			Author:      item.Author,
+			// Sin ID propio el GUID es la dirección de la entrada.
+			GUID:    &rssGUID{IsPermaLink: item.ID == "", Value: item.id()},
+			PubDate: formatFeedTime(item.Published, time.RFC1123Z),
+		})
+	}
+
+	return rss{Version: "2.0", Content: "http://purl.org/rss/1.0/modules/content/", Channel: channel}
+}
+
+type atom struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Link     atomLink    `xml:"link"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Author   *atomAuthor `xml:"author"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published,omitempty"`
+	Link      *atomLink   `xml:"link"`
+	Author    *atomAuthor `xml:"author"`
+	Summary   *atomText   `xml:"summary"`
+	Content   *atomText   `xml:"content"`
+}
+
+func atomFeed(f Feed) atom {
+	// Atom exige la fecha de actualización en la fuente y en cada entrada.
+	updated := f.updated()
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	feed := atom{
+		Title:    f.Title,
+		ID:       f.Link,
+		Updated:  formatFeedTime(updated, time.RFC3339),
+		Link:     atomLink{Href: f.Link, Rel: "alternate"},
+		Subtitle: f.Description,
+		Author:   newAtomAuthor(f.Author),
+	}
+	for _, item := range f.Items {
+		entryUpdated := item.updated()
+		if entryUpdated.IsZero() {
+			entryUpdated = updated
+		}
+
+		entry := atomEntry{
+			Title:     item.Title,
+			ID:        item.id(),
+			Updated:   formatFeedTime(entryUpdated, time.RFC3339),
+			Published: formatFeedTime(item.Published, time.RFC3339),
+			Author:    newAtomAuthor(item.Author),
+		}
+		if item.Link != "" {
+			entry.Link = &atomLink{Href: item.Link, Rel: "alternate"}
+		}
+		if item.Description != "" {
+			entry.Summary = &atomText{Type: "html", Value: item.Description}
+		}
+		if item.Content != "" {
+			entry.Content = &atomText{Type: "html", Value: item.Content}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return feed
+}
+
+func newAtomAuthor(name string) *atomAuthor {
+	if name == "" {
+		return nil
+	}
+	return &atomAuthor{Name: name}
+}
+
+// formatFeedTime da formato a t en UTC, o devuelve una cadena vacía si es
+// cero.
+func formatFeedTime(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(layout)
+}
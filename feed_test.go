@@ -0,0 +1,198 @@
+package gorender
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() Feed {
+	return Feed{
+		Title:       "Blog",
+		Link:        "https://example.com/blog",
+		Description: "Entradas",
+		Author:      "Ana",
+		Items: []FeedItem{
+			{
+				Title:       "Primera",
+				Link:        "https://example.com/blog/primera",
+				Description: "Resumen <b>uno</b>",
+				Content:     "<p>Hola & adiós</p>",
+				Author:      "ana@example.com (Ana)",
+				Published:   testTime,
+			},
+			{
+				Title:     "Segunda",
+				ID:        "urn:post:2",
+				Published: testTime.Add(-24 * time.Hour),
+				Updated:   testTime.Add(time.Hour),
+			},
+		},
+	}
+}
+
+func TestFeedRSS(t *testing.T) {
+	re := newTestRender(t, nil)
+	rec := httptest.NewRecorder()
+	if err := re.Feed(rec, testFeed(), FeedRSS); err != nil {
+		t.Fatalf("Feed: %v", err)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/rss+xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	var got struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title         string `xml:"title"`
+			Link          string `xml:"link"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Title       string `xml:"title"`
+				Link        string `xml:"link"`
+				Description string `xml:"description"`
+				Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+				GUID        struct {
+					IsPermaLink string `xml:"isPermaLink,attr"`
+					Value       string `xml:",chardata"`
+				} `xml:"guid"`
+				PubDate string `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decoding RSS: %v\n%s", err, rec.Body.String())
+	}
+
+	if got.Version != "2.0" || got.Channel.Title != "Blog" || got.Channel.Link != "https://example.com/blog" {
+		t.Errorf("channel = %+v", got.Channel)
+	}
+	if want := "Tue, 05 Mar 2024 15:30:00 +0000"; got.Channel.LastBuildDate != want {
+		t.Errorf("lastBuildDate = %q, want the newest item date %q", got.Channel.LastBuildDate, want)
+	}
+	if len(got.Channel.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(got.Channel.Items))
+	}
+
+	first, second := got.Channel.Items[0], got.Channel.Items[1]
+	if first.PubDate != "Tue, 05 Mar 2024 14:30:00 +0000" {
+		t.Errorf("pubDate = %q, want RFC 822", first.PubDate)
+	}
+	if first.GUID.Value != first.Link || first.GUID.IsPermaLink != "true" {
+		t.Errorf("guid without ID = %+v, want the permalink", first.GUID)
+	}
+	if first.Content != "<p>Hola & adiós</p>" || first.Description != "Resumen <b>uno</b>" {
+		t.Errorf("content = %q, description = %q", first.Content, first.Description)
+	}
+	if second.GUID.Value != "urn:post:2" || second.GUID.IsPermaLink != "false" {
+		t.Errorf("guid with ID = %+v", second.GUID)
+	}
+
+	// Los campos vacíos no aparecen.
+	body := rec.Body.String()
+	secondItem := body[strings.LastIndex(body, "<item>"):]
+	for _, tag := range []string{"<link>", "<description>", "<content:encoded>", "<author>"} {
+		if strings.Contains(secondItem, tag) {
+			t.Errorf("item without %s has the element: %s", tag, secondItem)
+		}
+	}
+}
+
+func TestFeedAtom(t *testing.T) {
+	re := newTestRender(t, nil)
+	rec := httptest.NewRecorder()
+	if err := re.Feed(rec, testFeed(), FeedAtom); err != nil {
+		t.Fatalf("Feed: %v", err)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/atom+xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	type text struct {
+		Type  string `xml:"type,attr"`
+		Value string `xml:",chardata"`
+	}
+	var got struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Title   string   `xml:"title"`
+		ID      string   `xml:"id"`
+		Updated string   `xml:"updated"`
+		Author  struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+		Entries []struct {
+			ID        string `xml:"id"`
+			Updated   string `xml:"updated"`
+			Published string `xml:"published"`
+			Link      struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+			Summary *text `xml:"summary"`
+			Content *text `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decoding Atom: %v\n%s", err, rec.Body.String())
+	}
+
+	if got.Title != "Blog" || got.ID != "https://example.com/blog" || got.Author.Name != "Ana" {
+		t.Errorf("feed = %+v", got)
+	}
+	if got.Updated != "2024-03-05T15:30:00Z" {
+		t.Errorf("updated = %q, want RFC 3339", got.Updated)
+	}
+	if len(got.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(got.Entries))
+	}
+
+	first, second := got.Entries[0], got.Entries[1]
+	if first.ID != "https://example.com/blog/primera" || first.Link.Href != first.ID {
+		t.Errorf("entry without ID: id = %q, link = %q", first.ID, first.Link.Href)
+	}
+	if first.Updated != "2024-03-05T14:30:00Z" || first.Published != first.Updated {
+		t.Errorf("entry dates: updated = %q, published = %q", first.Updated, first.Published)
+	}
+	if first.Content == nil || first.Content.Type != "html" || first.Content.Value != "<p>Hola & adiós</p>" {
+		t.Errorf("content = %+v", first.Content)
+	}
+	if second.ID != "urn:post:2" || second.Summary != nil || second.Content != nil {
+		t.Errorf("entry with ID and no text = %+v", second)
+	}
+	if strings.Count(rec.Body.String(), "<link") != 2 {
+		t.Errorf("want the feed link and one entry link:\n%s", rec.Body.String())
+	}
+}
+
+func TestFeedErrors(t *testing.T) {
+	re := newTestRender(t, nil)
+
+	tests := []struct {
+		name   string
+		feed   Feed
+		format FeedFormat
+	}{
+		{"no title", Feed{Link: "https://example.com"}, FeedRSS},
+		{"no link", Feed{Title: "Blog"}, FeedAtom},
+		{"item without link or id", Feed{Title: "Blog", Link: "https://example.com", Items: []FeedItem{{Title: "x"}}}, FeedRSS},
+		{"unknown format", Feed{Title: "Blog", Link: "https://example.com"}, FeedFormat(9)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if err := re.Feed(rec, tt.feed, tt.format); err == nil {
+				t.Error("Feed returned no error")
+			}
+			if rec.Body.Len() != 0 {
+				t.Errorf("Feed wrote %q after failing", rec.Body.String())
+			}
+		})
+	}
+
+	err := re.Feed(failingWriter{httptest.NewRecorder()}, testFeed(), FeedRSS)
+	if !errors.Is(err, ErrWrite) || !errors.Is(err, errBrokenPipe) {
+		t.Errorf("Feed error = %v, want ErrWrite wrapping the write error", err)
+	}
+}
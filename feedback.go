@@ -0,0 +1,67 @@
+package gorender
+
+// Niveles de los mensajes de FeedbackData. Coinciden con las clases CSS
+// habituales, así que se pueden usar directamente en las plantillas.
+const (
+	FeedbackSuccess = "success"
+	FeedbackError   = "error"
+	FeedbackWarning = "warning"
+	FeedbackInfo    = "info"
+)
+
+// AddFeedback añade un mensaje del nivel indicado. Los mensajes se acumulan en
+// Messages, de modo que un segundo error no sustituye al primero. FeedbackData
+// guarda el último mensaje de cada nivel para las plantillas que ya lo usan.
+func (td *TemplateData) AddFeedback(level, message string) {
+	if td.Messages == nil {
+		td.Messages = map[string][]string{}
+	}
+	td.Messages[level] = append(td.Messages[level], message)
+
+	if td.FeedbackData == nil {
+		td.FeedbackData = map[string]string{}
+	}
+	td.FeedbackData[level] = message
+}
+
+// AddSuccess añade un mensaje de éxito.
+func (td *TemplateData) AddSuccess(message string) {
+	td.AddFeedback(FeedbackSuccess, message)
+}
+
+// AddError añade un mensaje de error.
+func (td *TemplateData) AddError(message string) {
+	td.AddFeedback(FeedbackError, message)
+}
+
+// AddWarning añade un mensaje de advertencia.
+func (td *TemplateData) AddWarning(message string) {
+	td.AddFeedback(FeedbackWarning, message)
+}
+
+// AddInfo añade un mensaje informativo.
+func (td *TemplateData) AddInfo(message string) {
+	td.AddFeedback(FeedbackInfo, message)
+}
+
+// HasFeedback indica si hay algún mensaje. Sin argumentos comprueba todos los
+// niveles; con ellos, sólo los indicados.
+//
+// Ejemplo:
+//
+//	{{ if .HasFeedback "error" }}
+//	 {{ range index .Messages "error" }}<p class="error">{{ . }}</p>{{ end }}
+//	{{ end }}
+func (td *TemplateData) HasFeedback(levels ...string) bool {
+	if len(levels) == 0 {
+		return len(td.Messages) > 0 || len(td.FeedbackData) > 0
+	}
+
+	for _, level := range levels {
+		if len(td.Messages[level]) > 0 || td.FeedbackData[level] != "" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,86 @@
+// Package fiberrender permite usar gorender como motor de vistas de Fiber. Va
+// en un módulo aparte para que Fiber sólo sea una dependencia de quien lo use.
+//
+// Ejemplo:
+//
+//	app := fiber.New(fiber.Config{Views: fiberrender.New(ren)})
+//
+//	app.Get("/", func(c fiber.Ctx) error {
+//	    return c.Render("index.html", fiber.Map{"title": "Inicio"}, "admin.html")
+//	})
+package fiberrender
+
+import (
+	"io"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/zepyrshut/gorender"
+)
+
+// Engine implementa fiber.Views sobre un gorender.Render.
+type Engine struct {
+	render    *gorender.Render
+	csrfToken func(binding any) string
+}
+
+var _ fiber.Views = (*Engine)(nil)
+
+// Option configura un Engine.
+type Option func(*Engine)
+
+// WithCSRFToken indica cómo obtener el token CSRF de los datos que recibe
+// Render. Fiber no usa net/http, así que ni nosurf ni la función de
+// gorender.WithCSRFTokenFunc pueden leerlo de la petición; lo normal es
+// pasarlo en los datos desde el middleware csrf de Fiber.
+//
+// Ejemplo:
+//
+//	fiberrender.WithCSRFToken(func(binding any) string {
+//	    m, _ := binding.(fiber.Map)
+//	    token, _ := m["csrf"].(string)
+//	    return token
+//	})
+func WithCSRFToken(fn func(binding any) string) Option {
+	return func(e *Engine) {
+		e.csrfToken = fn
+	}
+}
+
+// New devuelve un Engine que procesa las páginas con re.
+func New(re *gorender.Render, opts ...Option) *Engine {
+	e := &Engine{render: re}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Load vuelve a procesar las plantillas y sustituye la caché. Si alguna falla
+// se mantiene la caché anterior y se devuelve el error.
+func (e *Engine) Load() error {
+	return e.render.Reload()
+}
+
+// Render procesa la página name y la escribe en out. Si se indica layout, la
+// página se procesa dentro de esa base como en TemplateWithLayout. binding se
+// convierte con gorender.AsTemplateData: un *gorender.TemplateData se usa tal
+// cual, un fiber.Map pasa a ser .Data y cualquier otro valor queda en
+// .Data.Data. Si la página falla no se escribe nada en out.
+func (e *Engine) Render(out io.Writer, name string, binding any, layout ...string) error {
+	td := gorender.AsTemplateData(binding)
+	if e.csrfToken != nil {
+		if td == nil {
+			td = &gorender.TemplateData{}
+		}
+		if td.CSRFToken == "" {
+			td.CSRFToken = e.csrfToken(binding)
+		}
+	}
+
+	if len(layout) > 0 && layout[0] != "" {
+		return e.render.RenderLayoutTo(out, layout[0], name, td)
+	}
+
+	return e.render.RenderTo(out, name, td)
+}
@@ -0,0 +1,113 @@
+package fiberrender
+
+import (
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/zepyrshut/gorender"
+)
+
+func TestRender(t *testing.T) {
+	re, err := gorender.NewE(
+		gorender.WithFS(fstest.MapFS{
+			"pages/index.html":          &fstest.MapFile{Data: []byte(`{{ template "base" . }}{{ define "content" }}{{ .CSRFToken }}|{{ .Data.title }}{{ end }}`)},
+			"shared/base.html":          &fstest.MapFile{Data: []byte(`{{ define "base" }}<main>{{ template "content" . }}</main>{{ end }}`)},
+			"shared/layouts/admin.html": &fstest.MapFile{Data: []byte(`{{ define "base" }}<admin>{{ template "content" . }}</admin>{{ end }}`)},
+		}),
+		gorender.WithTemplatesPath("shared"),
+		gorender.WithPageTemplatesPath("pages"),
+		gorender.WithCache(true),
+		gorender.WithCSRFTokenFunc(nil),
+		gorender.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	if err != nil {
+		t.Fatalf("NewE: %v", err)
+	}
+
+	app := fiber.New(fiber.Config{Views: New(re, WithCSRFToken(func(binding any) string {
+		m, _ := binding.(fiber.Map)
+		token, _ := m["csrf"].(string)
+		return token
+	}))})
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.Status(http.StatusCreated).Render("index.html", fiber.Map{"title": "Inicio", "csrf": "abc"})
+	})
+	app.Get("/admin", func(c fiber.Ctx) error {
+		return c.Render("index.html", fiber.Map{"title": "Panel"}, "admin.html")
+	})
+	app.Get("/missing", func(c fiber.Ctx) error {
+		return c.Render("missing.html", nil)
+	})
+
+	tests := []struct {
+		path string
+		code int
+		want string
+	}{
+		{"/", http.StatusCreated, "<main>abc|Inicio</main>"},
+		{"/admin", http.StatusOK, "<admin>|Panel</admin>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+			if err != nil {
+				t.Fatalf("app.Test: %v", err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != tt.code {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.code)
+			}
+			if string(body) != tt.want {
+				t.Errorf("body = %q, want %q", body, tt.want)
+			}
+			if got := resp.Header.Get("Content-Type"); got != "text/html; charset=utf-8" {
+				t.Errorf("Content-Type = %q", got)
+			}
+		})
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/missing", nil))
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("missing page status = %d, want 500", resp.StatusCode)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pages/index.html": &fstest.MapFile{Data: []byte(`uno`)},
+		"shared":           &fstest.MapFile{Mode: fs.ModeDir},
+	}
+	re, err := gorender.NewE(
+		gorender.WithFS(fsys),
+		gorender.WithTemplatesPath("shared"),
+		gorender.WithPageTemplatesPath("pages"),
+		gorender.WithCache(true),
+		gorender.WithCSRFTokenFunc(nil),
+		gorender.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	if err != nil {
+		t.Fatalf("NewE: %v", err)
+	}
+	engine := New(re)
+
+	fsys["pages/index.html"] = &fstest.MapFile{Data: []byte(`dos`)}
+	if err := engine.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	got, err := re.TemplateString("index.html", nil)
+	if err != nil || got != "dos" {
+		t.Errorf("after Load = %q, %v, want the new template", got, err)
+	}
+}
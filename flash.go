@@ -0,0 +1,255 @@
+package gorender
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// FlashMessage es un mensaje pendiente de mostrar en el siguiente
+// renderizado.
+type FlashMessage struct {
+	Level   string `json:"l"`
+	Message string `json:"m"`
+}
+
+// FlashStore guarda los mensajes flash entre peticiones. Add añade un mensaje
+// a los pendientes y Pop devuelve todos los pendientes y los borra. Se puede
+// implementar sobre cualquier almacén de sesiones.
+type FlashStore interface {
+	Add(w http.ResponseWriter, r *http.Request, msg FlashMessage) error
+	Pop(w http.ResponseWriter, r *http.Request) ([]FlashMessage, error)
+}
+
+// WithFlashStore indica dónde se guardan los mensajes flash. Por defecto se
+// usa una CookieFlashStore con una clave aleatoria, que no sirve si hay varias
+// instancias detrás de un balanceador o si se reinicia el servidor entre la
+// redirección y la petición siguiente.
+func WithFlashStore(store FlashStore) OptionFunc {
+	return func(re *Render) {
+		re.flashStore = store
+	}
+}
+
+// Flash guarda un mensaje para el siguiente renderizado, normalmente justo
+// antes de redirigir. Template y el resto de métodos HTML lo añaden a
+// TemplateData con AddFeedback y lo borran.
+func (re *Render) Flash(w http.ResponseWriter, r *http.Request, level, message string) error {
+	return re.flashes().Add(w, r, FlashMessage{Level: level, Message: message})
+}
+
+// flashes devuelve el almacén configurado o crea el de por defecto.
+func (re *Render) flashes() FlashStore {
+	re.flashOnce.Do(func() {
+		if re.flashStore != nil {
+			return
+		}
+		key := make([]byte, 32)
+		_, _ = rand.Read(key)
+		re.flashStore = NewCookieFlashStore(key)
+	})
+
+	return re.flashStore
+}
+
+// drainFlashes pasa los mensajes pendientes a td. Si el almacén falla se
+// registra y se renderiza sin ellos.
+func (re *Render) drainFlashes(w http.ResponseWriter, r *http.Request, td *TemplateData) {
+	msgs, err := re.flashes().Pop(w, r)
+	if err != nil {
+		re.log().Warn("reading flash messages failed", "error", err)
+		return
+	}
+
+	for _, msg := range msgs {
+		td.AddFeedback(msg.Level, msg.Message)
+	}
+}
+
+var (
+	// ErrInvalidFlash indica que la cookie de mensajes flash no tiene una
+	// firma válida.
+	ErrInvalidFlash = errors.New("invalid flash cookie")
+	// ErrFlashTooLarge indica que los mensajes flash no caben en una cookie
+	// y no se han guardado.
+	ErrFlashTooLarge = errors.New("flash cookie too large")
+)
+
+// maxFlashCookie es el tamaño máximo de la cabecera Set-Cookie de los
+// mensajes flash. Los navegadores descartan las cookies de más de 4096 bytes
+// sin avisar.
+const maxFlashCookie = 4096
+
+// CookieFlashStore guarda los mensajes flash en una cookie firmada con HMAC.
+// Los mensajes no van cifrados, así que no deben contener datos privados.
+type CookieFlashStore struct {
+	// Name es el nombre de la cookie, por defecto "flash".
+	Name string
+	// Path es la ruta de la cookie, por defecto "/".
+	Path     string
+	Secure   bool
+	SameSite http.SameSite
+	key      []byte
+}
+
+// NewCookieFlashStore crea un almacén de mensajes flash en cookies firmadas
+// con key.
+func NewCookieFlashStore(key []byte) *CookieFlashStore {
+	return &CookieFlashStore{
+		Name:     "flash",
+		Path:     "/",
+		SameSite: http.SameSiteLaxMode,
+		key:      key,
+	}
+}
+
+// Add añade msg a los mensajes de la cookie. Tiene en cuenta tanto los que
+// llegaron en la petición como los que ya se han añadido a esta respuesta. Si
+// Pop ya había borrado la cookie en esta respuesta, el borrado se sustituye
+// por la cookie nueva con sólo los mensajes añadidos después. Si los mensajes
+// no caben en una cookie devuelve ErrFlashTooLarge y la respuesta se queda
+// como estaba.
+func (s *CookieFlashStore) Add(w http.ResponseWriter, r *http.Request, msg FlashMessage) error {
+	msgs, queued, ok := s.pending(w)
+	if !ok {
+		msgs, _ = s.read(r)
+	}
+	msgs = append(msgs, msg)
+
+	value, err := s.encode(msgs)
+	if err != nil {
+		return err
+	}
+	line := s.cookie(value, 0).String()
+	if len(line) > maxFlashCookie {
+		return fmt.Errorf("%w: %d bytes", ErrFlashTooLarge, len(line))
+	}
+
+	s.dropPending(w, queued)
+	w.Header().Add("Set-Cookie", line)
+
+	return nil
+}
+
+// Pop devuelve los mensajes de la petición y borra la cookie.
+func (s *CookieFlashStore) Pop(w http.ResponseWriter, r *http.Request) ([]FlashMessage, error) {
+	if _, err := r.Cookie(s.Name); err != nil {
+		return nil, nil
+	}
+
+	s.setCookie(w, "", -1)
+
+	return s.read(r)
+}
+
+// pending busca la cookie que ya se haya añadido a la respuesta, para que
+// varias llamadas a Add en la misma petición no se pisen. Devuelve sus
+// mensajes, ninguno si es el borrado de Pop, y la posición de las líneas
+// Set-Cookie con ese nombre.
+func (s *CookieFlashStore) pending(w http.ResponseWriter) ([]FlashMessage, []int, bool) {
+	var (
+		msgs   []FlashMessage
+		queued []int
+	)
+	for i, line := range w.Header().Values("Set-Cookie") {
+		c, err := http.ParseSetCookie(line)
+		if err != nil || c.Name != s.Name {
+			continue
+		}
+
+		queued = append(queued, i)
+		msgs = nil
+		if c.MaxAge >= 0 {
+			msgs, _ = s.decode(c.Value)
+		}
+	}
+
+	return msgs, queued, len(queued) > 0
+}
+
+// dropPending quita de la respuesta las líneas Set-Cookie de las posiciones
+// queued, que ha devuelto pending.
+func (s *CookieFlashStore) dropPending(w http.ResponseWriter, queued []int) {
+	if len(queued) == 0 {
+		return
+	}
+
+	header := w.Header()
+	lines := header.Values("Set-Cookie")
+	header.Del("Set-Cookie")
+	for i, line := range lines {
+		if len(queued) > 0 && queued[0] == i {
+			queued = queued[1:]
+			continue
+		}
+		header.Add("Set-Cookie", line)
+	}
+}
+
+func (s *CookieFlashStore) read(r *http.Request) ([]FlashMessage, error) {
+	c, err := r.Cookie(s.Name)
+	if err != nil {
+		return nil, nil
+	}
+
+	return s.decode(c.Value)
+}
+
+func (s *CookieFlashStore) setCookie(w http.ResponseWriter, value string, maxAge int) {
+	http.SetCookie(w, s.cookie(value, maxAge))
+}
+
+func (s *CookieFlashStore) cookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     s.Name,
+		Value:    value,
+		Path:     s.Path,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   s.Secure,
+		SameSite: s.SameSite,
+	}
+}
+
+func (s *CookieFlashStore) encode(msgs []FlashMessage) (string, error) {
+	payload, err := json.Marshal(msgs)
+	if err != nil {
+		return "", err
+	}
+
+	data := base64.RawURLEncoding.EncodeToString(payload)
+
+	return data + "." + s.sign(data), nil
+}
+
+func (s *CookieFlashStore) decode(value string) ([]FlashMessage, error) {
+	data, sig, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.sign(data))) {
+		return nil, ErrInvalidFlash
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return nil, ErrInvalidFlash
+	}
+
+	var msgs []FlashMessage
+	if err := json.Unmarshal(payload, &msgs); err != nil {
+		return nil, ErrInvalidFlash
+	}
+
+	return msgs, nil
+}
+
+func (s *CookieFlashStore) sign(data string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(data))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,142 @@
+package gorender
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// flashRequest devuelve una petición con las cookies que rec ha puesto.
+func flashRequest(rec *httptest.ResponseRecorder) *http.Request {
+	req := httptest.NewRequest("GET", "/", nil)
+	for _, c := range rec.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	return req
+}
+
+func TestCookieFlashStore(t *testing.T) {
+	store := NewCookieFlashStore([]byte("secret"))
+	first := FlashMessage{Level: FeedbackSuccess, Message: "Guardado"}
+	second := FlashMessage{Level: FeedbackWarning, Message: "Revisa el correo"}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/", nil)
+	for _, msg := range []FlashMessage{first, second} {
+		if err := store.Add(rec, req, msg); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	if n := len(rec.Header().Values("Set-Cookie")); n != 1 {
+		t.Fatalf("Add set %d cookies, want 1", n)
+	}
+
+	next := httptest.NewRecorder()
+	msgs, err := store.Pop(next, flashRequest(rec))
+	if err != nil {
+		t.Fatalf("Pop: %v", err)
+	}
+	if want := []FlashMessage{first, second}; !reflect.DeepEqual(msgs, want) {
+		t.Errorf("Pop = %v, want %v", msgs, want)
+	}
+	cookies := next.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].MaxAge >= 0 {
+		t.Errorf("Pop cookies = %v, want the flash cookie deleted", cookies)
+	}
+
+	if msgs, err := store.Pop(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil)); msgs != nil || err != nil {
+		t.Errorf("Pop without cookie = %v, %v, want nothing", msgs, err)
+	}
+}
+
+func TestCookieFlashStoreTampered(t *testing.T) {
+	store := NewCookieFlashStore([]byte("secret"))
+	rec := httptest.NewRecorder()
+	if err := store.Add(rec, httptest.NewRequest("POST", "/", nil), FlashMessage{Level: FeedbackInfo, Message: "hola"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	value := rec.Result().Cookies()[0].Value
+	data, sig, _ := strings.Cut(value, ".")
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"payload", data + "x." + sig},
+		{"signature", data + "." + sig[1:]},
+		{"no signature", data},
+		{"other key", func() string {
+			rec := httptest.NewRecorder()
+			_ = NewCookieFlashStore([]byte("other")).Add(rec, httptest.NewRequest("POST", "/", nil), FlashMessage{Message: "hola"})
+			return rec.Result().Cookies()[0].Value
+		}()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.AddCookie(&http.Cookie{Name: "flash", Value: tt.value})
+			msgs, err := store.Pop(httptest.NewRecorder(), req)
+			if !errors.Is(err, ErrInvalidFlash) || msgs != nil {
+				t.Errorf("Pop = %v, %v, want ErrInvalidFlash", msgs, err)
+			}
+		})
+	}
+}
+
+func TestCookieFlashStoreTooLarge(t *testing.T) {
+	store := NewCookieFlashStore([]byte("secret"))
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/", nil)
+	if err := store.Add(rec, req, FlashMessage{Level: FeedbackInfo, Message: "hola"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	before := rec.Header().Values("Set-Cookie")
+
+	err := store.Add(rec, req, FlashMessage{Level: FeedbackInfo, Message: strings.Repeat("x", maxFlashCookie)})
+	if !errors.Is(err, ErrFlashTooLarge) {
+		t.Fatalf("Add error = %v, want ErrFlashTooLarge", err)
+	}
+	if got := rec.Header().Values("Set-Cookie"); !reflect.DeepEqual(got, before) {
+		t.Errorf("Set-Cookie = %v, want the previous cookie untouched %v", got, before)
+	}
+}
+
+func TestCookieFlashStorePopThenAdd(t *testing.T) {
+	store := NewCookieFlashStore([]byte("secret"))
+	old := httptest.NewRecorder()
+	if err := store.Add(old, httptest.NewRequest("POST", "/", nil), FlashMessage{Level: FeedbackInfo, Message: "viejo"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	http.SetCookie(rec, &http.Cookie{Name: "session", Value: "abc"})
+	req := flashRequest(old)
+	if _, err := store.Pop(rec, req); err != nil {
+		t.Fatalf("Pop: %v", err)
+	}
+	fresh := FlashMessage{Level: FeedbackSuccess, Message: "nuevo"}
+	if err := store.Add(rec, req, fresh); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	var flashes []*http.Cookie
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == "flash" {
+			flashes = append(flashes, c)
+		}
+	}
+	if len(flashes) != 1 || flashes[0].MaxAge < 0 {
+		t.Fatalf("flash cookies = %v, want only the new flash", flashes)
+	}
+	if n := len(rec.Result().Cookies()); n != 2 {
+		t.Errorf("response has %d cookies, want the session cookie kept", n)
+	}
+
+	msgs, err := store.Pop(httptest.NewRecorder(), flashRequest(rec))
+	if err != nil || !reflect.DeepEqual(msgs, []FlashMessage{fresh}) {
+		t.Errorf("next Pop = %v, %v, want only %v", msgs, err, fresh)
+	}
+}
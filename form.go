@@ -1,6 +1,8 @@
 package gorender
 
 import (
+	"net/http"
+	"net/url"
 	"strings"
 
 	spanish "github.com/go-playground/locales/es"
@@ -13,13 +15,63 @@ type FormData struct {
 	HasErrors bool
 	Errors    map[string]string
 	Values    map[string]string
+	// MultiValues contiene todos los valores de cada campo, para los que se
+	// envían varias veces como las casillas de verificación. Values sólo
+	// guarda el primero.
+	MultiValues map[string][]string
+	// FieldErrors contiene todos los errores de cada campo añadidos por los
+	// validadores, en orden. Errors sólo guarda el primero.
+	FieldErrors map[string][]string
+	// translate traduce los mensajes de los validadores. Ver Localize.
+	translate func(string) string
 }
 
 func NewForm() FormData {
 	return FormData{
-		HasErrors: false,
-		Errors:    map[string]string{},
-		Values:    map[string]string{},
+		HasErrors:   false,
+		Errors:      map[string]string{},
+		Values:      map[string]string{},
+		MultiValues: map[string][]string{},
+		FieldErrors: map[string][]string{},
+	}
+}
+
+// maxFormMemory es la memoria máxima que se usa para leer formularios
+// multipart, el resto de archivos van a disco.
+const maxFormMemory = 32 << 20
+
+// NewFormData crea un FormData con los valores enviados en la petición, tanto
+// los del cuerpo como los de la URL, para volver a mostrarlos si la validación
+// falla. En los formularios multipart sólo se copian los campos de texto, no
+// los archivos. Si el cuerpo no se puede leer se devuelve lo que se haya
+// podido obtener.
+func NewFormData(r *http.Request) FormData {
+	fd := NewForm()
+
+	// ParseMultipartForm también procesa los formularios normales antes de
+	// devolver ErrNotMultipart.
+	_ = r.ParseMultipartForm(maxFormMemory)
+	fd.FromValues(r.Form)
+
+	return fd
+}
+
+// FromValues copia v en los valores del formulario. Values guarda el primer
+// valor de cada campo y MultiValues todos.
+func (fd *FormData) FromValues(v url.Values) {
+	if fd.Values == nil {
+		fd.Values = map[string]string{}
+	}
+	if fd.MultiValues == nil {
+		fd.MultiValues = map[string][]string{}
+	}
+
+	for field, values := range v {
+		if len(values) == 0 {
+			continue
+		}
+		fd.Values[field] = values[0]
+		fd.MultiValues[field] = append([]string{}, values...)
 	}
 }
 
@@ -37,6 +89,30 @@ func (fd *FormData) AddValue(field, value string) {
 	fd.Values[field] = value
 }
 
+// Value devuelve el valor enviado en el campo o una cadena vacía. Funciona
+// aunque el formulario no se haya inicializado, así que se puede usar en el
+// primer renderizado.
+//
+// Ejemplo:
+//
+//	<input name="email" value="{{ .FormData.Value "email" }}"
+//	 {{ if .FormData.HasError "email" }}class="is-invalid"{{ end }}>
+//	<small>{{ .FormData.Error "email" }}</small>
+func (fd FormData) Value(field string) string {
+	return fd.Values[field]
+}
+
+// Error devuelve el primer error del campo o una cadena vacía.
+func (fd FormData) Error(field string) string {
+	return fd.Errors[field]
+}
+
+// HasError indica si el campo tiene algún error.
+func (fd FormData) HasError(field string) bool {
+	_, ok := fd.Errors[field]
+	return ok
+}
+
 type ValidationError struct {
 	Field  string
 	Reason string
@@ -0,0 +1,60 @@
+package gorender
+
+import (
+	"bytes"
+	"testing"
+)
+
+// formTemplate es un formulario que muestra el valor anterior del campo y su
+// error, tanto con los métodos de FormData como con las funciones.
+const formTemplate = `<input name="email" value="{{ .FormData.Value "email" }}"` +
+	`{{ if .FormData.HasError "email" }} class="error"{{ end }}>` +
+	`<span>{{ .FormData.Error "email" }}</span>` +
+	`{{ $fd := .FormData }}{{ range .Data.fields }}` +
+	`<input name="{{ . }}" value="{{ fieldValue $fd . }}"{{ if hasError $fd . }} class="error" title="{{ fieldError $fd . }}"{{ end }}>` +
+	`{{ end }}`
+
+func TestFormDataHelpers(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/form.html": formTemplate})
+
+	tests := []struct {
+		name string
+		fd   func() FormData
+		want string
+	}{
+		{
+			name: "first render",
+			fd:   func() FormData { return FormData{} },
+			want: `<input name="email" value=""><span></span><input name="name" value="">`,
+		},
+		{
+			name: "with values and errors",
+			fd: func() FormData {
+				fd := NewForm()
+				fd.AddValue("email", "ana@example")
+				fd.AddValue("name", "Ana")
+				fd.AddError("email", "correo no válido")
+				fd.AddError("name", "demasiado corto")
+				return fd
+			},
+			want: `<input name="email" value="ana@example" class="error"><span>correo no válido</span>` +
+				`<input name="name" value="Ana" class="error" title="demasiado corto">`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			td := &TemplateData{
+				Data:     map[string]interface{}{"fields": []string{"name"}},
+				FormData: tt.fd(),
+			}
+			if err := re.RenderTo(&buf, "form.html", td); err != nil {
+				t.Fatalf("RenderTo: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
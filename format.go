@@ -0,0 +1,240 @@
+package gorender
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// numberFormat describe cómo se escriben los números y las fechas en una
+// lengua.
+type numberFormat struct {
+	group   string
+	decimal string
+	// symbolFirst pone el símbolo de la moneda delante, "$1.00", en lugar de
+	// detrás, "1,00 €".
+	symbolFirst bool
+	// dateShort, dateMedium y dateLong construyen la fecha en cada estilo.
+	dateShort  func(t time.Time) string
+	dateMedium func(t time.Time) string
+	dateLong   func(t time.Time) string
+}
+
+var (
+	monthsEN = []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+	monthsES = []string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"}
+)
+
+// numberFormats son los formatos de cada lengua. Las que no están usan el de
+// "en".
+var numberFormats = map[string]numberFormat{
+	"en": {
+		group: ",", decimal: ".", symbolFirst: true,
+		dateShort: func(t time.Time) string { return t.Format("01/02/2006") },
+		dateMedium: func(t time.Time) string {
+			return fmt.Sprintf("%s %d, %d", monthsEN[t.Month()-1][:3], t.Day(), t.Year())
+		},
+		dateLong: func(t time.Time) string {
+			return fmt.Sprintf("%s %d, %d", monthsEN[t.Month()-1], t.Day(), t.Year())
+		},
+	},
+	"es": {
+		group: ".", decimal: ",",
+		dateShort: func(t time.Time) string { return t.Format("02/01/2006") },
+		dateMedium: func(t time.Time) string {
+			return fmt.Sprintf("%d %s %d", t.Day(), monthsES[t.Month()-1][:3], t.Year())
+		},
+		dateLong: func(t time.Time) string {
+			return fmt.Sprintf("%d de %s de %d", t.Day(), monthsES[t.Month()-1], t.Year())
+		},
+	},
+}
+
+// currency es el símbolo y la cantidad de decimales de una moneda.
+type currency struct {
+	symbol   string
+	decimals int
+}
+
+// currencies son las monedas conocidas por su código ISO 4217. Las demás se
+// muestran con su código y dos decimales.
+var currencies = map[string]currency{
+	"EUR": {"€", 2},
+	"USD": {"$", 2},
+	"GBP": {"£", 2},
+	"JPY": {"¥", 0},
+	"MXN": {"$", 2},
+	"CHF": {"CHF", 2},
+}
+
+// formatFor devuelve el formato del idioma lang o de su lengua.
+func formatFor(lang string) numberFormat {
+	lang = normalizeLocale(lang)
+	if f, ok := numberFormats[lang]; ok {
+		return f
+	}
+
+	base, _, _ := strings.Cut(lang, "_")
+	if f, ok := numberFormats[base]; ok {
+		return f
+	}
+
+	return numberFormats["en"]
+}
+
+// formatNumber escribe v con los separadores del idioma indicado o del idioma
+// por defecto. Los enteros se escriben sin decimales y los reales con dos.
+//
+// Ejemplo:
+//
+//	{{ formatNumber 1234.5 }} → 1.234,50 en español, 1,234.50 en inglés
+func (re *Render) formatNumber(v interface{}, locale ...string) (string, error) {
+	n, ok := toFloat(v)
+	if !ok {
+		return "", fmt.Errorf("formatNumber: expected a number, got %T", v)
+	}
+
+	decimals := 2
+	if isInteger(v) {
+		decimals = 0
+	}
+
+	return formatDecimal(n, decimals, formatFor(re.localeArg(locale))), nil
+}
+
+// formatCurrency escribe amount, en unidades de la moneda, con el símbolo de
+// code y el formato del idioma.
+//
+// Ejemplo:
+//
+//	{{ formatCurrency .Data.price "EUR" }} → 1.234,56 €
+func (re *Render) formatCurrency(amount interface{}, code string, locale ...string) (string, error) {
+	n, ok := toFloat(amount)
+	if !ok {
+		return "", fmt.Errorf("formatCurrency: expected a number, got %T", amount)
+	}
+
+	return formatMoney(n, code, formatFor(re.localeArg(locale))), nil
+}
+
+// formatCents funciona igual que formatCurrency pero amount va en céntimos,
+// como se suele guardar en la base de datos para evitar redondeos.
+func (re *Render) formatCents(amount interface{}, code string, locale ...string) (string, error) {
+	n, ok := toFloat(amount)
+	if !ok {
+		return "", fmt.Errorf("formatCents: expected a number, got %T", amount)
+	}
+
+	c := currencyFor(code)
+	return formatMoney(n/math.Pow10(c.decimals), code, formatFor(re.localeArg(locale))), nil
+}
+
+// formatDate escribe t en el estilo indicado: "short", "medium" o "long". Con
+// cualquier otro estilo se usa como formato de time.Format. Acepta time.Time y
+// *time.Time; un puntero nil o una fecha cero se escriben vacíos.
+//
+// Ejemplo:
+//
+//	{{ formatDate .Data.created "long" }} → 2 de enero de 2006
+func (re *Render) formatDate(v interface{}, style string, locale ...string) (string, error) {
+	var t time.Time
+	switch v := v.(type) {
+	case time.Time:
+		t = v
+	case *time.Time:
+		if v == nil {
+			return "", nil
+		}
+		t = *v
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("formatDate: expected a time.Time, got %T", v)
+	}
+
+	if t.IsZero() {
+		return "", nil
+	}
+
+	f := formatFor(re.localeArg(locale))
+	switch style {
+	case "short":
+		return f.dateShort(t), nil
+	case "medium", "":
+		return f.dateMedium(t), nil
+	case "long":
+		return f.dateLong(t), nil
+	default:
+		return t.Format(style), nil
+	}
+}
+
+// localeArg devuelve el idioma pasado como argumento opcional o el idioma
+// por defecto.
+func (re *Render) localeArg(locale []string) string {
+	if len(locale) > 0 {
+		return re.resolveLocale(locale[0])
+	}
+	return re.resolveLocale("")
+}
+
+func currencyFor(code string) currency {
+	code = strings.ToUpper(code)
+	if c, ok := currencies[code]; ok {
+		return c
+	}
+	return currency{code, 2}
+}
+
+func formatMoney(n float64, code string, f numberFormat) string {
+	c := currencyFor(code)
+	number := formatDecimal(math.Abs(n), c.decimals, f)
+
+	sign := ""
+	if n < 0 {
+		sign = "-"
+	}
+
+	if f.symbolFirst {
+		return sign + c.symbol + number
+	}
+	return sign + number + " " + c.symbol
+}
+
+// formatDecimal escribe n con decimals decimales y los separadores de f.
+func formatDecimal(n float64, decimals int, f numberFormat) string {
+	s := strconv.FormatFloat(n, 'f', decimals, 64)
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+
+	var b strings.Builder
+	for i, digit := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(f.group)
+		}
+		b.WriteRune(digit)
+	}
+
+	if fracPart != "" {
+		b.WriteString(f.decimal)
+		b.WriteString(fracPart)
+	}
+
+	return sign + b.String()
+}
+
+func isInteger(v interface{}) bool {
+	switch v.(type) {
+	case float32, float64:
+		return false
+	default:
+		return true
+	}
+}
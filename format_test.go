@@ -0,0 +1,157 @@
+package gorender
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFormatNumber(t *testing.T) {
+	re := newTestRender(t, nil, WithLocales("es", "en"))
+
+	tests := []struct {
+		v      interface{}
+		locale string
+		want   string
+	}{
+		{1234.5, "en", "1,234.50"},
+		{1234.5, "es", "1.234,50"},
+		{1234567, "en", "1,234,567"},
+		{int64(1234567), "es", "1.234.567"},
+		{-1234.5, "es", "-1.234,50"},
+		{999, "es", "999"},
+		{0.5, "en", "0.50"},
+	}
+	for _, tt := range tests {
+		got, err := re.formatNumber(tt.v, tt.locale)
+		if err != nil {
+			t.Fatalf("formatNumber(%v, %s): %v", tt.v, tt.locale, err)
+		}
+		if got != tt.want {
+			t.Errorf("formatNumber(%v, %s) = %q, want %q", tt.v, tt.locale, got, tt.want)
+		}
+	}
+
+	if _, err := re.formatNumber("1234"); err == nil {
+		t.Error("formatNumber with a string returned no error")
+	}
+}
+
+func TestFormatCurrency(t *testing.T) {
+	re := newTestRender(t, nil, WithLocales("es", "en"))
+
+	tests := []struct {
+		amount interface{}
+		code   string
+		locale string
+		want   string
+	}{
+		{1234.56, "EUR", "es", "1.234,56 €"},
+		{1234.56, "USD", "en", "$1,234.56"},
+		{-5, "EUR", "es", "-5,00 €"},
+		{1234, "JPY", "en", "¥1,234"},
+		{10, "SEK", "en", "SEK10.00"},
+	}
+	for _, tt := range tests {
+		got, err := re.formatCurrency(tt.amount, tt.code, tt.locale)
+		if err != nil {
+			t.Fatalf("formatCurrency(%v, %s, %s): %v", tt.amount, tt.code, tt.locale, err)
+		}
+		if got != tt.want {
+			t.Errorf("formatCurrency(%v, %s, %s) = %q, want %q", tt.amount, tt.code, tt.locale, got, tt.want)
+		}
+	}
+
+	cents := []struct {
+		amount interface{}
+		code   string
+		locale string
+		want   string
+	}{
+		{int64(123456), "EUR", "es", "1.234,56 €"},
+		{int64(199), "USD", "en", "$1.99"},
+		{500, "JPY", "es", "500 ¥"},
+	}
+	for _, tt := range cents {
+		got, err := re.formatCents(tt.amount, tt.code, tt.locale)
+		if err != nil {
+			t.Fatalf("formatCents(%v, %s, %s): %v", tt.amount, tt.code, tt.locale, err)
+		}
+		if got != tt.want {
+			t.Errorf("formatCents(%v, %s, %s) = %q, want %q", tt.amount, tt.code, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	re := newTestRender(t, nil, WithLocales("es", "en"))
+	var nilTime *time.Time
+
+	tests := []struct {
+		v      interface{}
+		style  string
+		locale string
+		want   string
+	}{
+		{testTime, "short", "en", "03/05/2024"},
+		{testTime, "short", "es", "05/03/2024"},
+		{testTime, "medium", "en", "Mar 5, 2024"},
+		{testTime, "medium", "es", "5 mar 2024"},
+		{testTime, "long", "en", "March 5, 2024"},
+		{&testTime, "long", "es", "5 de marzo de 2024"},
+		{testTime, "2006-01-02", "es", "2024-03-05"},
+		{nilTime, "long", "es", ""},
+		{time.Time{}, "long", "es", ""},
+		{nil, "long", "es", ""},
+	}
+	for _, tt := range tests {
+		got, err := re.formatDate(tt.v, tt.style, tt.locale)
+		if err != nil {
+			t.Fatalf("formatDate(%v, %s, %s): %v", tt.v, tt.style, tt.locale, err)
+		}
+		if got != tt.want {
+			t.Errorf("formatDate(%v, %s, %s) = %q, want %q", tt.v, tt.style, tt.locale, got, tt.want)
+		}
+	}
+
+	if _, err := re.formatDate("2024-03-05", "long"); err == nil {
+		t.Error("formatDate with a string returned no error")
+	}
+}
+
+func TestFormatRequestLocale(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/price.html": `{{ formatCurrency .Data.price "EUR" }} {{ formatDate .Data.date "long" }}`,
+	}, WithLocales("es", "en"))
+	td := func() *TemplateData {
+		return &TemplateData{Data: map[string]interface{}{"price": 1234.5, "date": testTime}}
+	}
+
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"es", "1.234,50 € 5 de marzo de 2024"},
+		{"en-US,en;q=0.9", "€1,234.50 March 5, 2024"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Language", tt.accept)
+		rec := httptest.NewRecorder()
+		if err := re.Template(rec, req, "price.html", td()); err != nil {
+			t.Fatalf("Template: %v", err)
+		}
+		if got := rec.Body.String(); got != tt.want {
+			t.Errorf("Accept-Language %s: body = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := re.RenderTo(&buf, "price.html", td()); err != nil {
+		t.Fatalf("RenderTo: %v", err)
+	}
+	if got := buf.String(); got != "1.234,50 € 5 de marzo de 2024" {
+		t.Errorf("default locale output = %q", got)
+	}
+}
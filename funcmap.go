@@ -0,0 +1,73 @@
+package gorender
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+)
+
+// builtinFuncs son las funciones predefinidas de text/template, que una
+// función registrada con el mismo nombre sustituiría en todas las plantillas.
+var builtinFuncs = map[string]bool{
+	"and": true, "call": true, "html": true, "index": true, "slice": true,
+	"js": true, "len": true, "not": true, "or": true, "print": true,
+	"printf": true, "println": true, "urlquery": true, "eq": true, "ge": true,
+	"gt": true, "le": true, "lt": true, "ne": true,
+}
+
+// WithStrictFunctions hace que New y NewE devuelvan ErrFunctionCollision si
+// WithFunctions o WithRenderOptions registran una función con el nombre de
+// otra que ya existe, en lugar de sólo avisar.
+func WithStrictFunctions(strict bool) OptionFunc {
+	return func(re *Render) {
+		re.strictFuncs = strict
+	}
+}
+
+// WithLegacyOr registra "or" como alias de notEmpty, como hacían las versiones
+// anteriores, para las plantillas que aún lo usan así. Oculta la función or de
+// las plantillas, que devuelve el primer argumento no vacío.
+//
+// Deprecated: usa notEmpty en las plantillas.
+func WithLegacyOr(enabled bool) OptionFunc {
+	return func(re *Render) {
+		if enabled {
+			re.Functions["or"] = notEmpty
+			return
+		}
+		delete(re.Functions, "or")
+	}
+}
+
+// addFunctions añade funcs a las de las plantillas y anota las que sustituyen
+// a otras.
+func (re *Render) addFunctions(funcs template.FuncMap) {
+	for name, fn := range funcs {
+		if _, ok := re.Functions[name]; ok || builtinFuncs[name] {
+			re.funcCollisions = append(re.funcCollisions, name)
+		}
+		re.Functions[name] = fn
+	}
+}
+
+// checkFunctions avisa de las funciones que se han registrado con el nombre de
+// otra o, con WithStrictFunctions, devuelve un error con todas ellas.
+func (re *Render) checkFunctions() error {
+	if len(re.funcCollisions) == 0 {
+		return nil
+	}
+
+	names := append([]string{}, re.funcCollisions...)
+	sort.Strings(names)
+
+	if re.strictFuncs {
+		return fmt.Errorf("%w: %s", ErrFunctionCollision, strings.Join(names, ", "))
+	}
+
+	for _, name := range names {
+		re.log().Warn("template function overrides an existing one", "function", name)
+	}
+
+	return nil
+}
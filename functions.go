@@ -1,19 +1,132 @@
 package gorender
 
 import (
-	"bufio"
 	"fmt"
-	"os"
-	"strings"
+	"net/url"
+	"reflect"
+	"strconv"
 )
 
-func or(a, b string) bool {
+// notEmpty indica si alguna de las dos cadenas no está vacía. Antes se
+// llamaba "or", nombre que ocultaba la función or de las plantillas y que
+// sólo se registra con WithLegacyOr.
+func notEmpty(a, b string) bool {
 	if a == "" && b == "" {
 		return false
 	}
 	return true
 }
 
+// dict crea un mapa a partir de pares clave-valor, para pasar varios datos a
+// una plantilla.
+//
+// Ejemplo:
+//
+//	{{ template "card" dict "Title" .Data.title "Body" .Data.body }}
+func dict(pairs ...interface{}) (map[string]interface{}, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("dict: odd number of arguments: %d", len(pairs))
+	}
+
+	m := make(map[string]interface{}, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict: key at position %d must be a string, got %T", i, pairs[i])
+		}
+		m[key] = pairs[i+1]
+	}
+
+	return m, nil
+}
+
+// list crea una lista con los argumentos.
+func list(items ...interface{}) []interface{} {
+	return items
+}
+
+// defaultValue devuelve el valor si no está vacío y def en caso contrario. Se
+// registra como "default" y el valor va al final para poder encadenarlo.
+//
+// Ejemplo:
+//
+//	{{ .Data.name | default "Anónimo" }}
+func defaultValue(def interface{}, value ...interface{}) interface{} {
+	if len(value) == 0 || isEmpty(value[0]) {
+		return def
+	}
+	return value[0]
+}
+
+// isEmpty indica si v es nil, el valor cero de su tipo o una colección vacía.
+func isEmpty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return rv.IsZero()
+	}
+}
+
+// seq devuelve los números del 1 a n o, con dos argumentos, de start a end,
+// ambos incluidos.
+//
+// Ejemplo:
+//
+//	{{ range seq 1 5 }}<span>{{ . }}</span>{{ end }}
+func seq(bounds ...int) ([]int, error) {
+	var start, end int
+	switch len(bounds) {
+	case 1:
+		start, end = 1, bounds[0]
+	case 2:
+		start, end = bounds[0], bounds[1]
+	default:
+		return nil, fmt.Errorf("seq: expected 1 or 2 arguments, got %d", len(bounds))
+	}
+
+	if end < start {
+		return []int{}, nil
+	}
+
+	nums := make([]int, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		nums = append(nums, i)
+	}
+	return nums, nil
+}
+
+// until devuelve los números de 0 a n-1.
+func until(n int) []int {
+	if n <= 0 {
+		return []int{}
+	}
+
+	nums := make([]int, n)
+	for i := range nums {
+		nums[i] = i
+	}
+	return nums
+}
+
+// hasKey indica si el mapa m tiene la clave key. Acepta cualquier mapa cuyas
+// claves sean cadenas.
+func hasKey(m interface{}, key string) bool {
+	rv := reflect.ValueOf(m)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return false
+	}
+
+	return rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key())).IsValid()
+}
+
 // containsErrors hace una función similar a "{{ with index ... }}" con el
 // añadido de que puede pasarle más de un argumento y comprobar si alguno de
 // ellos está en el mapa de errores.
@@ -33,39 +146,72 @@ func containsErrors(errors map[string]string, names ...string) bool {
 	return false
 }
 
-func loadTranslations(language string) map[string]string {
-	translations := make(map[string]string)
-	filePath := fmt.Sprintf("%s.translate", language)
-	file, err := os.Open(filePath)
-	if err != nil {
-		fmt.Println("Error opening translation file:", err)
-		return translations
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		parts := strings.Split(line, "=")
-		if len(parts) == 2 {
-			key := strings.TrimSpace(parts[0])
-			value := strings.TrimSpace(parts[1])
-			translations[key] = value
-		}
-	}
+// fieldValue, fieldError y hasError son las versiones en función de
+// FormData.Value, FormData.Error y FormData.HasError, para usarlas dentro de
+// bloques donde el punto ya no es el TemplateData.
+//
+// Ejemplo:
+//
+//	{{ $fd := .FormData }}
+//	{{ range .Data.fields }}
+//	 <input name="{{ . }}" value="{{ fieldValue $fd . }}">
+//	{{ end }}
+func fieldValue(fd FormData, field string) string {
+	return fd.Value(field)
+}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Println("Error reading translation file:", err)
-	}
+func fieldError(fd FormData, field string) string {
+	return fd.Error(field)
+}
+
+func hasError(fd FormData, field string) bool {
+	return fd.HasError(field)
+}
+
+// pageURL devuelve la ruta de u con el parámetro "page" cambiado a page,
+// conservando el resto de parámetros.
+//
+// Ejemplo:
+//
+//	{{ range .Page.Window 5 }}<a href="{{ pageURL $.URL . }}">{{ . }}</a>{{ end }}
+func pageURL(u *url.URL, page int) string {
+	return withQuery(u, func(q url.Values) {
+		q.Set("page", strconv.Itoa(page))
+	})
+}
 
-	return translations
+// sortURL devuelve la ruta de u ordenada por column. Si ya se ordenaba por
+// column en orden ascendente pasa a descendente y en cualquier otro caso a
+// ascendente. Vuelve a la primera página porque con otro orden la página
+// actual deja de tener sentido.
+//
+// Ejemplo:
+//
+//	<a href="{{ sortURL .URL "price" }}">Precio</a>
+func sortURL(u *url.URL, column string) string {
+	return withQuery(u, func(q url.Values) {
+		order := "asc"
+		if q.Get("sort") == column && q.Get("order") != "desc" {
+			order = "desc"
+		}
+		q.Set("sort", column)
+		q.Set("order", order)
+		q.Del("page")
+	})
 }
 
-func translateKey(key string) string {
-	translations := loadTranslations("es_ES")
-	translated := translations[key]
-	if translated != "" {
-		return translated
+// withQuery devuelve la ruta de u con los parámetros modificados por fn. Si u
+// es nil parte de una ruta vacía. La ruta se toma escapada para que los
+// segmentos como "a%2Fb" sigan apuntando al mismo recurso.
+func withQuery(u *url.URL, fn func(url.Values)) string {
+	var path string
+	q := url.Values{}
+	if u != nil {
+		path = u.EscapedPath()
+		q = u.Query()
 	}
-	return key
+
+	fn(q)
+
+	return path + "?" + q.Encode()
 }
@@ -0,0 +1,208 @@
+package gorender
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPageURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		page int
+		want string
+	}{
+		{"plain", "/products", 2, "/products?page=2"},
+		{"keeps params", "/products?q=shoes&page=1", 3, "/products?page=3&q=shoes"},
+		{"encoded slash", "/tags/a%2Fb", 2, "/tags/a%2Fb?page=2"},
+		{"encoded question mark", "/q/a%3Fb?page=4", 5, "/q/a%3Fb?page=5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := pageURL(u, tt.page); got != tt.want {
+				t.Errorf("pageURL(%q, %d) = %q, want %q", tt.url, tt.page, got, tt.want)
+			}
+		})
+	}
+
+	if got := pageURL(nil, 2); got != "?page=2" {
+		t.Errorf("pageURL(nil, 2) = %q, want %q", got, "?page=2")
+	}
+}
+
+func TestSortURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		column string
+		want   string
+	}{
+		{"first click", "/products?q=shoes", "price", "/products?order=asc&q=shoes&sort=price"},
+		{"second click", "/products?sort=price&order=asc", "price", "/products?order=desc&sort=price"},
+		{"third click", "/products?sort=price&order=desc", "price", "/products?order=asc&sort=price"},
+		{"other column", "/products?sort=price&order=asc", "name", "/products?order=asc&sort=name"},
+		{"resets page", "/products?sort=price&page=7", "price", "/products?order=desc&sort=price"},
+		{"encoded path", "/tags/a%2Fb", "name", "/tags/a%2Fb?order=asc&sort=name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sortURL(u, tt.column); got != tt.want {
+				t.Errorf("sortURL(%q, %q) = %q, want %q", tt.url, tt.column, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDict(t *testing.T) {
+	got, err := dict("Title", "Hola", "Count", 3)
+	if err != nil {
+		t.Fatalf("dict: %v", err)
+	}
+	if want := map[string]interface{}{"Title": "Hola", "Count": 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("dict = %v, want %v", got, want)
+	}
+
+	if got, err := dict(); err != nil || len(got) != 0 {
+		t.Errorf("dict() = %v, %v, want an empty map", got, err)
+	}
+
+	tests := []struct {
+		name  string
+		pairs []interface{}
+		want  string
+	}{
+		{"odd count", []interface{}{"Title", "Hola", "Body"}, "odd number of arguments: 3"},
+		{"single argument", []interface{}{"Title"}, "odd number of arguments: 1"},
+		{"non-string key", []interface{}{"Title", "Hola", 2, "Body"}, "key at position 2 must be a string, got int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dict(tt.pairs...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("dict error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDictInTemplate(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/ok.html":  `{{ define "card" }}{{ .Title }}{{ end }}{{ template "card" dict "Title" "Hola" }}`,
+		"pages/bad.html": `{{ $d := dict "Title" }}`,
+	})
+
+	if got, err := re.TemplateString("ok.html", nil); err != nil || got != "Hola" {
+		t.Errorf("ok.html = %q, %v", got, err)
+	}
+	if _, err := re.TemplateString("bad.html", nil); err == nil || !strings.Contains(err.Error(), "dict: odd number of arguments") {
+		t.Errorf("bad.html error = %v, want the dict error", err)
+	}
+}
+
+func TestDefaultValue(t *testing.T) {
+	zero := 0
+	var nilPtr *int
+
+	tests := []struct {
+		name  string
+		value []interface{}
+		want  interface{}
+	}{
+		{"no value", nil, "def"},
+		{"nil", []interface{}{nil}, "def"},
+		{"nil pointer", []interface{}{nilPtr}, "def"},
+		{"zero int", []interface{}{0}, "def"},
+		{"false", []interface{}{false}, "def"},
+		{"empty string", []interface{}{""}, "def"},
+		{"empty slice", []interface{}{[]string{}}, "def"},
+		{"empty map", []interface{}{map[string]int{}}, "def"},
+		{"pointer to zero", []interface{}{&zero}, &zero},
+		{"value", []interface{}{"Ana"}, "Ana"},
+		{"number", []interface{}{3}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := defaultValue("def", tt.value...); got != tt.want {
+				t.Errorf("default = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	re := newTestRender(t, map[string]string{"pages/index.html": `{{ .Data.name | default "Anónimo" }}|{{ .Data.missing | default "Nadie" }}`})
+	got, err := re.TemplateString("index.html", &TemplateData{Data: map[string]interface{}{"name": ""}})
+	if err != nil || got != "Anónimo|Nadie" {
+		t.Errorf("template = %q, %v", got, err)
+	}
+}
+
+func TestSeq(t *testing.T) {
+	tests := []struct {
+		name   string
+		bounds []int
+		want   []int
+	}{
+		{"count", []int{3}, []int{1, 2, 3}},
+		{"zero count", []int{0}, []int{}},
+		{"negative count", []int{-2}, []int{}},
+		{"range", []int{2, 4}, []int{2, 3, 4}},
+		{"single value range", []int{4, 4}, []int{4}},
+		{"reversed range", []int{5, 1}, []int{}},
+		{"negative range", []int{-2, 0}, []int{-2, -1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := seq(tt.bounds...)
+			if err != nil {
+				t.Fatalf("seq: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("seq(%v) = %v, want %v", tt.bounds, got, tt.want)
+			}
+		})
+	}
+
+	for _, bounds := range [][]int{nil, {1, 2, 3}} {
+		if _, err := seq(bounds...); err == nil {
+			t.Errorf("seq(%v) returned no error", bounds)
+		}
+	}
+}
+
+func TestHasKey(t *testing.T) {
+	type key string
+
+	tests := []struct {
+		name string
+		m    interface{}
+		key  string
+		want bool
+	}{
+		{"present", map[string]int{"a": 1}, "a", true},
+		{"zero value", map[string]interface{}{"a": nil}, "a", true},
+		{"missing", map[string]int{"a": 1}, "b", false},
+		{"named key type", map[key]bool{"a": true}, "a", true},
+		{"nil map", map[string]int(nil), "a", false},
+		{"non-string keys", map[int]int{1: 1}, "1", false},
+		{"not a map", []string{"a"}, "a", false},
+		{"nil", nil, "a", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasKey(tt.m, tt.key); got != tt.want {
+				t.Errorf("hasKey(%v, %q) = %v, want %v", tt.m, tt.key, got, tt.want)
+			}
+		})
+	}
+}
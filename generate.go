@@ -0,0 +1,124 @@
+package gorender
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Generate procesa las páginas indicadas y escribe el resultado en outDir,
+// respetando la ruta relativa de cada página (por ejemplo "blog/post.html").
+// Los directorios se crean según se necesiten. Se detiene en el primer error,
+// que indica la página que lo ha provocado, y devuelve cuántos archivos se han
+// escrito.
+//
+// Cada página se ejecuta igual que con RenderTo, con los mismos límites,
+// métricas y recuperación de panics, y además pasa por las funciones de
+// WithPostRender, con la petición nil, y por WithMinifyHTML.
+func (re *Render) Generate(outDir string, pages map[string]*TemplateData) (int, error) {
+	set, err := re.currentSet()
+	if err != nil {
+		return 0, err
+	}
+
+	names := make([]string, 0, len(pages))
+	for name := range pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	written := 0
+	for _, name := range names {
+		key, ok := set.resolve(name)
+		re.stats.lookup(name, ok)
+		if !ok {
+			return written, fmt.Errorf("generating %s: %w", name, re.missingError(set, name))
+		}
+
+		body, err := re.generatePage(set, key, pages[name])
+		if err != nil {
+			return written, fmt.Errorf("generating %s: %w", name, err)
+		}
+
+		out, err := generatePath(outDir, key)
+		if err != nil {
+			return written, fmt.Errorf("generating %s: %w", name, err)
+		}
+
+		err = os.MkdirAll(filepath.Dir(out), 0o755)
+		if err != nil {
+			return written, fmt.Errorf("generating %s: %w", name, err)
+		}
+
+		err = os.WriteFile(out, body, 0o644)
+		if err != nil {
+			return written, fmt.Errorf("generating %s: %w", name, err)
+		}
+
+		written++
+	}
+
+	re.log().Info("static pages generated", "dir", outDir, "pages", written)
+
+	return written, nil
+}
+
+// generatePath devuelve el archivo de outDir en el que se escribe la página
+// key. Las claves de AddTemplate o TemplateCache.Set pueden ser cualquier
+// cadena, así que se rechazan las rutas absolutas y las que salen de outDir
+// con "..".
+func generatePath(outDir, key string) (string, error) {
+	rel := filepath.FromSlash(key)
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("output path %q is outside %s", key, outDir)
+	}
+
+	return filepath.Join(outDir, rel), nil
+}
+
+// generatePage ejecuta la página key de set con td y devuelve el resultado ya
+// transformado por WithPostRender y WithMinifyHTML.
+func (re *Render) generatePage(set *templateSet, key string, td *TemplateData) (body []byte, err error) {
+	start := time.Now()
+	td = re.baseData(td)
+	defer func() { re.observe(start, key, td, err) }()
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	err = re.executeSet(buf, nil, set, set.templates[key], key, td)
+	if err != nil {
+		return nil, err
+	}
+
+	err = re.postRender(nil, key, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	if re.minify && !td.SkipMinify {
+		return minifyHTML(buf.Bytes()), nil
+	}
+
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// GenerateAll funciona como Generate pero procesa todas las páginas de la
+// caché con un TemplateData vacío. Sirve como prueba rápida de que todas las
+// páginas se pueden ejecutar.
+func (re *Render) GenerateAll(outDir string) (int, error) {
+	set, err := re.currentSet()
+	if err != nil {
+		return 0, err
+	}
+
+	pages := make(map[string]*TemplateData, len(set.templates))
+	for key := range set.templates {
+		pages[key] = &TemplateData{}
+	}
+
+	return re.Generate(outDir, pages)
+}
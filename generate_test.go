@@ -0,0 +1,109 @@
+package gorender
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerate(t *testing.T) {
+	files := map[string]string{
+		"pages/index.html":     `<p>  {{.Data.title}}  </p>`,
+		"pages/blog/post.html": `<h1>{{.Data.title}}</h1>`,
+	}
+	var observed []string
+	re := newTestRender(t, files,
+		WithMinifyHTML(true),
+		WithPostRender(func(r *http.Request, tmpl string, body []byte) ([]byte, error) {
+			return append(body, "\n\n<footer>"+tmpl+"</footer>"...), nil
+		}),
+		WithMetricsHook(func(tmpl string, _ time.Duration, status string, _ error) {
+			observed = append(observed, tmpl+":"+status)
+		}),
+	)
+
+	dir := t.TempDir()
+	n, err := re.Generate(dir, map[string]*TemplateData{
+		"index.html":     {Data: map[string]interface{}{"title": "Inicio"}},
+		"blog/post.html": {Data: map[string]interface{}{"title": "Post"}},
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Generate wrote %d files, want 2", n)
+	}
+
+	for file, want := range map[string]string{
+		"index.html":     `<p> Inicio </p> <footer>index.html</footer>`,
+		"blog/post.html": `<h1>Post</h1> <footer>blog/post.html</footer>`,
+	} {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", file, got, want)
+		}
+	}
+	if len(observed) != 2 || observed[0] != "blog/post.html:ok" || observed[1] != "index.html:ok" {
+		t.Errorf("metrics hook saw %v", observed)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	files := map[string]string{
+		"pages/big.html": `{{.Data.body}}`,
+	}
+	re := newTestRender(t, files, WithMaxRenderSize(10))
+
+	_, err := re.Generate(t.TempDir(), map[string]*TemplateData{
+		"big.html": {Data: map[string]interface{}{"body": "more than ten bytes"}},
+	})
+	if !errors.Is(err, ErrRenderTooLarge) {
+		t.Errorf("Generate error = %v, want ErrRenderTooLarge", err)
+	}
+
+	_, err = re.Generate(t.TempDir(), map[string]*TemplateData{"missing.html": nil})
+	if !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Generate error = %v, want ErrTemplateNotFound", err)
+	}
+
+	if n, err := re.GenerateAll(t.TempDir()); err != nil || n != 1 {
+		t.Errorf("GenerateAll = %d, %v, want 1 file", n, err)
+	}
+}
+
+func TestGenerateOutsideOutDir(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": `hola`})
+	for _, name := range []string{"../escape.html", "blog/../../escape.html", "/tmp/escape.html"} {
+		if err := re.AddTemplate(name, `fuera`); err != nil {
+			t.Fatalf("AddTemplate(%s): %v", name, err)
+		}
+	}
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	for _, name := range []string{"../escape.html", "blog/../../escape.html", "/tmp/escape.html"} {
+		t.Run(name, func(t *testing.T) {
+			n, err := re.Generate(out, map[string]*TemplateData{name: nil})
+			if err == nil || n != 0 {
+				t.Fatalf("Generate = %d, %v, want an error", n, err)
+			}
+			if !strings.Contains(err.Error(), "outside") {
+				t.Errorf("Generate error = %v, want the path rejected", err)
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape.html")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("a file was written outside the output directory: %v", err)
+	}
+
+	if n, err := re.Generate(out, map[string]*TemplateData{"index.html": nil}); err != nil || n != 1 {
+		t.Errorf("Generate = %d, %v, want the page inside the output directory", n, err)
+	}
+}
@@ -0,0 +1,94 @@
+// Package ginrender permite usar gorender como motor de c.HTML en Gin. Va en
+// un módulo aparte para que Gin sólo sea una dependencia de quien lo use.
+//
+// Ejemplo:
+//
+//	r := gin.Default()
+//	r.HTMLRender = ginrender.New(ren)
+//
+//	r.GET("/", func(c *gin.Context) {
+//	    c.HTML(http.StatusOK, "index.html", ginrender.Data(c, gin.H{"title": "Inicio"}))
+//	})
+package ginrender
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"github.com/zepyrshut/gorender"
+)
+
+// Renderer implementa render.HTMLRender de Gin sobre un gorender.Render. Las
+// páginas salen de su caché, así que con EnableCache desactivado se vuelven a
+// procesar en cada petición igual que con Template.
+type Renderer struct {
+	render *gorender.Render
+}
+
+var _ render.HTMLRender = (*Renderer)(nil)
+
+// New devuelve un Renderer que procesa las páginas con re.
+func New(re *gorender.Render) *Renderer {
+	return &Renderer{render: re}
+}
+
+// requestData lleva junto a los datos la petición de la que se obtienen los
+// datos por defecto.
+type requestData struct {
+	request *http.Request
+	data    any
+}
+
+// Data asocia la petición de c a data para que la página reciba los datos por
+// defecto de gorender, como el token CSRF, el idioma o la ruta. Gin no pasa la
+// petición a los motores de plantillas, así que sin Data esos campos quedan
+// vacíos.
+func Data(c *gin.Context, data any) any {
+	return requestData{request: c.Request, data: data}
+}
+
+// Instance devuelve el render.Render de la página name. data se convierte con
+// gorender.AsTemplateData: un *gorender.TemplateData se usa tal cual, un gin.H
+// u otro mapa con claves de texto pasa a ser .Data y cualquier otro valor,
+// como un struct, queda en .Data.Data.
+func (g *Renderer) Instance(name string, data any) render.Render {
+	inst := &Instance{render: g.render, name: name}
+	if rd, ok := data.(requestData); ok {
+		inst.request = rd.request
+		data = rd.data
+	}
+	inst.data = gorender.AsTemplateData(data)
+
+	return inst
+}
+
+// Instance es una página lista para escribirse en la respuesta.
+type Instance struct {
+	render  *gorender.Render
+	request *http.Request
+	name    string
+	data    *gorender.TemplateData
+}
+
+var _ render.Render = (*Instance)(nil)
+
+// Render procesa la página y la escribe en w. Igual que en Template, si falla
+// no se escribe nada y Gin puede responder con un error.
+func (i *Instance) Render(w http.ResponseWriter) error {
+	i.WriteContentType(w)
+
+	if i.request == nil {
+		return i.render.RenderTo(w, i.name, i.data)
+	}
+
+	return i.render.RenderRequest(w, i.request, i.name, i.data)
+}
+
+// WriteContentType pone la cabecera Content-Type de HTML.
+func (i *Instance) WriteContentType(w http.ResponseWriter) {
+	header := w.Header()
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "text/html; charset=utf-8")
+	}
+}
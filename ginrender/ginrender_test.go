@@ -0,0 +1,90 @@
+package ginrender
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zepyrshut/gorender"
+)
+
+func TestHTML(t *testing.T) {
+	re, err := gorender.NewE(
+		gorender.WithFS(fstest.MapFS{
+			"pages/index.html": &fstest.MapFile{Data: []byte(`{{ .CSRFToken }}|{{ .Data.title }}|{{ .Data.Data }}`)},
+			"shared":           &fstest.MapFile{Mode: fs.ModeDir},
+		}),
+		gorender.WithTemplatesPath("shared"),
+		gorender.WithPageTemplatesPath("pages"),
+		gorender.WithCache(true),
+		gorender.WithCSRFTokenFunc(func(r *http.Request) string { return r.Header.Get("X-Token") }),
+		gorender.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	if err != nil {
+		t.Fatalf("NewE: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.HTMLRender = New(re)
+	var renderErrs []*gin.Error
+	r.Use(func(c *gin.Context) {
+		c.Next()
+		renderErrs = c.Errors
+	})
+	r.GET("/request", func(c *gin.Context) {
+		c.HTML(http.StatusCreated, "index.html", Data(c, gin.H{"title": "Inicio"}))
+	})
+	r.GET("/plain", func(c *gin.Context) {
+		c.HTML(http.StatusOK, "index.html", gin.H{"title": "Inicio"})
+	})
+	r.GET("/struct", func(c *gin.Context) {
+		c.HTML(http.StatusAccepted, "index.html", Data(c, 42))
+	})
+	r.GET("/missing", func(c *gin.Context) {
+		c.HTML(http.StatusOK, "missing.html", nil)
+	})
+
+	tests := []struct {
+		path string
+		code int
+		want string
+	}{
+		{"/request", http.StatusCreated, "abc|Inicio|"},
+		{"/plain", http.StatusOK, "|Inicio|"},
+		{"/struct", http.StatusAccepted, "abc||42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.Header.Set("X-Token", "abc")
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d", rec.Code, tt.code)
+			}
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+				t.Errorf("Content-Type = %q", got)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/missing", nil))
+	if rec.Body.Len() != 0 {
+		t.Errorf("missing page wrote %q", rec.Body.String())
+	}
+	if len(renderErrs) != 1 || !errors.Is(renderErrs[0].Err, gorender.ErrTemplateNotFound) {
+		t.Errorf("c.Errors = %v, want ErrTemplateNotFound", renderErrs)
+	}
+}
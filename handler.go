@@ -0,0 +1,96 @@
+package gorender
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrNotFound es el error que devuelve una función de datos de Handler cuando
+// lo que se pide no existe, para responder con un 404.
+var ErrNotFound = errors.New("not found")
+
+// StatusError asocia un código de estado HTTP a un error devuelto por una
+// función de datos de Handler.
+//
+// Ejemplo:
+//
+//	return nil, &gorender.StatusError{Status: http.StatusForbidden, Err: err}
+type StatusError struct {
+	Status int
+	Err    error
+}
+
+func (e *StatusError) Error() string {
+	if e.Err == nil {
+		return http.StatusText(e.Status)
+	}
+	return fmt.Sprintf("%d %s: %v", e.Status, http.StatusText(e.Status), e.Err)
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// DataFunc obtiene los datos de una página a partir de la petición.
+type DataFunc func(r *http.Request) (*TemplateData, error)
+
+// Handler devuelve un http.Handler que llama a dataFn y procesa tmpl con el
+// resultado. Si dataFn devuelve un error se responde con Error: un 404 si
+// envuelve ErrNotFound, el código de un StatusError o un 500 en el resto de
+// casos. Si falla el renderizado también se responde con la página de error.
+//
+// Ejemplo:
+//
+//	mux.Handle("/users/{id}", ren.Handler("user.html", func(r *http.Request) (*gorender.TemplateData, error) {
+//		user, err := store.User(r.PathValue("id"))
+//		if err != nil {
+//			return nil, err
+//		}
+//		return gorender.NewData().Set("user", user).Build(), nil
+//	}))
+func (re *Render) Handler(tmpl string, dataFn DataFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var td *TemplateData
+		if dataFn != nil {
+			var err error
+			td, err = dataFn(r)
+			if err != nil {
+				status := errorStatus(err)
+				if status >= http.StatusInternalServerError {
+					re.log().Error("error loading page data:", logAttrs(r, tmpl, "error", err)...)
+				}
+				re.Error(w, r, status, err)
+				return
+			}
+		}
+
+		err := re.Template(w, r, tmpl, td)
+		if err != nil && !re.autoErrorPages && !re.debug {
+			re.renderFailed(w, r, tmpl, td, err)
+		}
+	})
+}
+
+// Static devuelve un http.Handler que procesa tmpl sin datos propios, para
+// páginas como "acerca de" o "contacto".
+//
+// Ejemplo:
+//
+//	mux.Handle("/about", ren.Static("about.html"))
+func (re *Render) Static(tmpl string) http.Handler {
+	return re.Handler(tmpl, nil)
+}
+
+// errorStatus devuelve el código de estado que corresponde a err.
+func errorStatus(err error) int {
+	var statusErr *StatusError
+	switch {
+	case errors.As(err, &statusErr) && statusErr.Status != 0:
+		return statusErr.Status
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,90 @@
+package gorender
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/user.html":   `<h1>{{ .Data.name }}</h1>`,
+		"pages/about.html":  `acerca de`,
+		"pages/broken.html": `{{ template "missing" }}`,
+		"pages/404.html":    `no existe: {{ .Error.Title }}`,
+	}, WithErrorTemplates(map[int]string{http.StatusNotFound: "404.html"}))
+
+	calls := 0
+	users := func(r *http.Request) (*TemplateData, error) {
+		calls++
+		switch id := r.URL.Query().Get("id"); id {
+		case "1":
+			return NewData().Set("name", "Ana").Build(), nil
+		case "forbidden":
+			return nil, &StatusError{Status: http.StatusForbidden, Err: errors.New("not yours")}
+		case "broken":
+			return nil, errors.New("database down")
+		default:
+			return nil, fmt.Errorf("user %q: %w", id, ErrNotFound)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		target  string
+		code    int
+		body    string
+	}{
+		{"data func", re.Handler("user.html", users), "/?id=1", http.StatusOK, "<h1>Ana</h1>"},
+		{"not found", re.Handler("user.html", users), "/?id=2", http.StatusNotFound, "no existe: Not Found"},
+		{"status error", re.Handler("user.html", users), "/?id=forbidden", http.StatusForbidden, "Forbidden\n"},
+		{"internal error", re.Handler("user.html", users), "/?id=broken", http.StatusInternalServerError, "Internal Server Error\n"},
+		{"static", re.Static("about.html"), "/", http.StatusOK, "acerca de"},
+		{"render error", re.Static("broken.html"), "/", http.StatusInternalServerError, "Internal Server Error\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, httptest.NewRequest("GET", tt.target, nil))
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d", rec.Code, tt.code)
+			}
+			if got := rec.Body.String(); got != tt.body {
+				t.Errorf("body = %q, want %q", got, tt.body)
+			}
+		})
+	}
+
+	if calls != 4 {
+		t.Errorf("data func called %d times, want 4", calls)
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{ErrNotFound, http.StatusNotFound},
+		{fmt.Errorf("post: %w", ErrNotFound), http.StatusNotFound},
+		{&StatusError{Status: http.StatusConflict}, http.StatusConflict},
+		{&StatusError{Status: http.StatusGone, Err: ErrNotFound}, http.StatusGone},
+		{&StatusError{Err: ErrNotFound}, http.StatusNotFound},
+		{errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		if got := errorStatus(tt.err); got != tt.want {
+			t.Errorf("errorStatus(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+
+	if got := (&StatusError{Status: http.StatusForbidden, Err: errors.New("not yours")}).Error(); !strings.HasPrefix(got, "403 Forbidden: ") {
+		t.Errorf("StatusError.Error() = %q", got)
+	}
+}
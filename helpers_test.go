@@ -0,0 +1,43 @@
+package gorender
+
+import (
+	"io"
+	"io/fs"
+	"log/slog"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// newTestRender crea un Render con la caché habilitada que lee las plantillas
+// de files, nombre de archivo → contenido. Las páginas van en "pages/" y las
+// plantillas compartidas en "shared/".
+func newTestRender(t testing.TB, files map[string]string, opts ...OptionFunc) *Render {
+	t.Helper()
+
+	fsys := fstest.MapFS{
+		"pages":  &fstest.MapFile{Mode: fs.ModeDir},
+		"shared": &fstest.MapFile{Mode: fs.ModeDir},
+	}
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+
+	base := []OptionFunc{
+		WithFS(fsys),
+		WithTemplatesPath("shared"),
+		WithPageTemplatesPath("pages"),
+		WithCache(true),
+		WithCSRFTokenFunc(nil),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	}
+	re, err := NewE(append(base, opts...)...)
+	if err != nil {
+		t.Fatalf("NewE: %v", err)
+	}
+
+	return re
+}
+
+// testTime es una fecha fija para las pruebas que la necesitan.
+var testTime = time.Date(2024, time.March, 5, 14, 30, 0, 0, time.UTC)
@@ -0,0 +1,206 @@
+package gorender
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// relativeWords son los textos de timeAgo en una lengua. Se pueden sustituir
+// desde los catálogos de WithTranslationsDir con las claves "time.now",
+// "time.ago", "time.in" y "time.<unidad>.one" / "time.<unidad>.other", donde
+// la unidad es minute, hour o day. {count} es la cantidad y {time} el
+// texto de la unidad. Las abreviaturas de humanDuration se sustituyen con
+// "duration.day", "duration.hour", "duration.minute" y "duration.second".
+type relativeWords struct {
+	now   string
+	ago   string
+	in    string
+	units map[string][2]string
+	// short son las abreviaturas de humanDuration de días, horas, minutos y
+	// segundos.
+	short [4]string
+}
+
+var relativeTexts = map[string]relativeWords{
+	"en": {
+		now: "just now", ago: "{time} ago", in: "in {time}",
+		units: map[string][2]string{
+			"minute": {"{count} minute", "{count} minutes"},
+			"hour":   {"{count} hour", "{count} hours"},
+			"day":    {"{count} day", "{count} days"},
+		},
+		short: [4]string{"d", "h", "m", "s"},
+	},
+	"es": {
+		now: "ahora mismo", ago: "hace {time}", in: "dentro de {time}",
+		units: map[string][2]string{
+			"minute": {"{count} minuto", "{count} minutos"},
+			"hour":   {"{count} hora", "{count} horas"},
+			"day":    {"{count} día", "{count} días"},
+		},
+		short: [4]string{"d", "h", "min", "s"},
+	},
+}
+
+// timeAgo describe cuánto falta o ha pasado desde t: "hace 3 minutos", "in 2
+// days". Por debajo de 45 segundos escribe "ahora mismo" y a partir de 26
+// días la fecha, porque "hace 40 días" se entiende peor. Acepta time.Time y
+// *time.Time; un puntero nil o una fecha cero se escriben vacíos.
+//
+// Ejemplo:
+//
+//	{{ timeAgo .Data.created }}
+func (re *Render) timeAgo(v interface{}, locale ...string) (string, error) {
+	var t time.Time
+	switch v := v.(type) {
+	case time.Time:
+		t = v
+	case *time.Time:
+		if v == nil {
+			return "", nil
+		}
+		t = *v
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("timeAgo: expected a time.Time, got %T", v)
+	}
+
+	if t.IsZero() {
+		return "", nil
+	}
+
+	lang := re.localeArg(locale)
+	words := relativeTextsFor(lang)
+
+	d := time.Since(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	var unit string
+	var count float64
+	switch {
+	case d < 45*time.Second:
+		return re.relativeText(lang, "time.now", words.now), nil
+	case d < 45*time.Minute:
+		unit, count = "minute", math.Max(1, math.Round(d.Minutes()))
+	case d < 22*time.Hour:
+		unit, count = "hour", math.Max(1, math.Round(d.Hours()))
+	case d < 26*24*time.Hour:
+		unit, count = "day", math.Max(1, math.Round(d.Hours()/24))
+	default:
+		return re.formatDate(t, "medium", lang)
+	}
+
+	form := pluralRule(lang)(count)
+	index := 1
+	if form == "one" {
+		index = 0
+	}
+	amount := re.relativeText(lang, "time."+unit+"."+form, words.units[unit][index])
+	amount = strings.ReplaceAll(amount, "{count}", strconv.FormatFloat(count, 'f', -1, 64))
+
+	pattern := re.relativeText(lang, "time.ago", words.ago)
+	if future {
+		pattern = re.relativeText(lang, "time.in", words.in)
+	}
+
+	return strings.ReplaceAll(pattern, "{time}", amount), nil
+}
+
+// relativeText devuelve la traducción de key en los catálogos o, si no hay,
+// el texto incluido en el paquete.
+func (re *Render) relativeText(lang, key, fallback string) string {
+	if translated, ok := re.lookupCatalog(lang, key); ok {
+		return translated
+	}
+	return fallback
+}
+
+// relativeTextsFor devuelve los textos del idioma lang o de su lengua. Las
+// lenguas que no están usan los de "en".
+func relativeTextsFor(lang string) relativeWords {
+	lang = normalizeLocale(lang)
+	if words, ok := relativeTexts[lang]; ok {
+		return words
+	}
+
+	base, _, _ := strings.Cut(lang, "_")
+	if words, ok := relativeTexts[base]; ok {
+		return words
+	}
+
+	return relativeTexts["en"]
+}
+
+// humanDuration escribe d con sus dos unidades más grandes y las abreviaturas
+// del idioma: "2h 15m", "3m 5s" o "1d 4h" en inglés y "2h 15min" en español.
+// Las duraciones negativas llevan signo y las menores de un segundo se
+// escriben como "0s". Acepta time.Duration y *time.Duration; un puntero nil se
+// escribe vacío.
+//
+// Ejemplo:
+//
+//	{{ humanDuration .Data.elapsed }}
+func (re *Render) humanDuration(v interface{}, locale ...string) (string, error) {
+	var d time.Duration
+	switch v := v.(type) {
+	case time.Duration:
+		d = v
+	case *time.Duration:
+		if v == nil {
+			return "", nil
+		}
+		d = *v
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("humanDuration: expected a time.Duration, got %T", v)
+	}
+
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+
+	lang := re.localeArg(locale)
+	short := relativeTextsFor(lang).short
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{re.relativeText(lang, "duration.day", short[0]), 24 * time.Hour},
+		{re.relativeText(lang, "duration.hour", short[1]), time.Hour},
+		{re.relativeText(lang, "duration.minute", short[2]), time.Minute},
+		{re.relativeText(lang, "duration.second", short[3]), time.Second},
+	}
+
+	// Se escriben como mucho dos unidades seguidas empezando por la mayor, y
+	// un cero en la segunda la omite: "2h", no "2h 0m".
+	var parts []string
+	for _, u := range units {
+		n := d / u.size
+		d -= n * u.size
+		if n == 0 {
+			if len(parts) > 0 {
+				break
+			}
+			continue
+		}
+		parts = append(parts, strconv.FormatInt(int64(n), 10)+u.suffix)
+		if len(parts) == 2 {
+			break
+		}
+	}
+
+	if len(parts) == 0 {
+		return "0" + units[3].suffix, nil
+	}
+
+	return sign + strings.Join(parts, " "), nil
+}
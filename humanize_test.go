@@ -0,0 +1,99 @@
+package gorender
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestTimeAgo(t *testing.T) {
+	re := newTestRender(t, nil, WithLocales("es", "en"))
+	now := time.Now()
+	old := now.Add(-40 * 24 * time.Hour)
+	var nilTime *time.Time
+
+	tests := []struct {
+		v      interface{}
+		locale string
+		want   string
+	}{
+		{now.Add(-10 * time.Second), "es", "ahora mismo"},
+		{now.Add(-10 * time.Second), "en", "just now"},
+		{now.Add(-time.Minute), "es", "hace 1 minuto"},
+		{now.Add(-3 * time.Minute), "es", "hace 3 minutos"},
+		{now.Add(-3 * time.Minute), "en", "3 minutes ago"},
+		{now.Add(-50 * time.Minute), "en", "1 hour ago"},
+		{now.Add(-5 * time.Hour), "es", "hace 5 horas"},
+		{now.Add(-23 * time.Hour), "en", "1 day ago"},
+		{now.Add(-3 * 24 * time.Hour), "es", "hace 3 días"},
+		{now.Add(2*24*time.Hour + time.Minute), "en", "in 2 days"},
+		{now.Add(2*24*time.Hour + time.Minute), "es", "dentro de 2 días"},
+		{old, "en", old.Format("Jan") + " " + old.Format("2, 2006")},
+		{&old, "es", formatFor("es").dateMedium(old)},
+		{time.Time{}, "es", ""},
+		{nilTime, "es", ""},
+		{nil, "es", ""},
+	}
+	for _, tt := range tests {
+		got, err := re.timeAgo(tt.v, tt.locale)
+		if err != nil {
+			t.Fatalf("timeAgo(%v, %s): %v", tt.v, tt.locale, err)
+		}
+		if got != tt.want {
+			t.Errorf("timeAgo(%v, %s) = %q, want %q", tt.v, tt.locale, got, tt.want)
+		}
+	}
+
+	if _, err := re.timeAgo("yesterday"); err == nil {
+		t.Error("timeAgo with a string returned no error")
+	}
+}
+
+func TestHumanDuration(t *testing.T) {
+	re := newTestRender(t, nil, WithLocales("en", "es"))
+	d := 2*time.Hour + 15*time.Minute + 30*time.Second
+	var nilDuration *time.Duration
+
+	tests := []struct {
+		v      interface{}
+		locale string
+		want   string
+	}{
+		{d, "en", "2h 15m"},
+		{d, "es", "2h 15min"},
+		{&d, "en", "2h 15m"},
+		{28 * time.Hour, "en", "1d 4h"},
+		{2 * time.Hour, "en", "2h"},
+		{3*time.Minute + 5*time.Second, "es", "3min 5s"},
+		{-90 * time.Second, "en", "-1m 30s"},
+		{500 * time.Millisecond, "en", "0s"},
+		{nilDuration, "en", ""},
+		{nil, "en", ""},
+	}
+	for _, tt := range tests {
+		got, err := re.humanDuration(tt.v, tt.locale)
+		if err != nil {
+			t.Fatalf("humanDuration(%v, %s): %v", tt.v, tt.locale, err)
+		}
+		if got != tt.want {
+			t.Errorf("humanDuration(%v, %s) = %q, want %q", tt.v, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestHumanizeCatalogs(t *testing.T) {
+	catalogs := fstest.MapFS{
+		"de.json": &fstest.MapFile{Data: []byte(`{
+			"time": {"ago": "vor {time}", "minute": {"one": "{count} Minute", "other": "{count} Minuten"}},
+			"duration": {"day": "T", "hour": "Std", "minute": "Min", "second": "Sek"}
+		}`)},
+	}
+	re := newTestRender(t, nil, WithLocales("de"), WithTranslationsFS(catalogs))
+
+	if got, _ := re.timeAgo(time.Now().Add(-5*time.Minute), "de"); got != "vor 5 Minuten" {
+		t.Errorf("timeAgo in de = %q", got)
+	}
+	if got, _ := re.humanDuration(90*time.Minute, "de"); got != "1Std 30Min" {
+		t.Errorf("humanDuration in de = %q", got)
+	}
+}
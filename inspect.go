@@ -0,0 +1,51 @@
+package gorender
+
+import "sort"
+
+// Templates devuelve, ordenadas, las claves de todas las páginas disponibles,
+// HTML y de texto, por ejemplo para una página de administración que permita
+// previsualizarlas. Con la caché deshabilitada las plantillas se procesan en
+// ese momento y, si fallan, se registra el error y se devuelve nil.
+func (re *Render) Templates() []string {
+	set, err := re.currentSet()
+	if err != nil {
+		re.log().Error("error creating template cache:", "error", err)
+		return nil
+	}
+
+	keys := set.keys()
+	sort.Strings(keys)
+
+	return keys
+}
+
+// DefinedTemplates devuelve, ordenados, los nombres de las plantillas que se
+// pueden usar dentro de la página name: la propia página, las compartidas y
+// los bloques que define. Si la página no existe devuelve un error que
+// envuelve ErrTemplateNotFound.
+func (re *Render) DefinedTemplates(name string) ([]string, error) {
+	set, err := re.currentSet()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if t, ok := set.lookup(name); ok {
+		for _, tmpl := range t.Templates() {
+			if tmpl.Tree != nil {
+				names = append(names, tmpl.Name())
+			}
+		}
+	} else if t, ok := set.lookupText(name); ok {
+		for _, tmpl := range t.Templates() {
+			if tmpl.Tree != nil {
+				names = append(names, tmpl.Name())
+			}
+		}
+	} else {
+		return nil, re.missingError(set, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
@@ -0,0 +1,33 @@
+package gorender
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDefinedTemplates(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/index.html":   `{{ template "base" . }}{{ define "content" }}hola{{ end }}{{ define "title" }}Inicio{{ end }}`,
+		"pages/about.html":   `{{ template "base" . }}{{ define "content" }}sobre{{ end }}`,
+		"shared/base.html":   `{{ define "base" }}<title>{{ block "title" . }}Sitio{{ end }}</title>{{ template "content" . }}{{ template "footer" . }}{{ end }}`,
+		"shared/footer.html": `{{ define "footer" }}pie{{ end }}{{ define "social" }}redes{{ end }}`,
+	})
+
+	got, err := re.DefinedTemplates("index.html")
+	if err != nil {
+		t.Fatalf("DefinedTemplates: %v", err)
+	}
+	want := []string{"base", "base.html", "content", "footer", "footer.html", "index.html", "social", "title"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DefinedTemplates(index.html) = %v, want %v", got, want)
+	}
+
+	if _, err := re.DefinedTemplates("missing.html"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("DefinedTemplates(missing.html) error = %v, want ErrTemplateNotFound", err)
+	}
+
+	if got, want := re.Templates(), []string{"about.html", "index.html"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Templates = %v, want %v", got, want)
+	}
+}
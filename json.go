@@ -0,0 +1,99 @@
+package gorender
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+)
+
+// WithJSONIndent hace que JSON escriba la salida con sangría, útil para
+// depurar. Con una cadena vacía la salida es compacta, que es lo habitual.
+func WithJSONIndent(indent string) OptionFunc {
+	return func(re *Render) {
+		re.jsonIndent = indent
+	}
+}
+
+// WithJSONEscapeHTML indica si JSON escapa los caracteres <, > y & dentro de
+// las cadenas. Por defecto se escapan, igual que en encoding/json.
+func WithJSONEscapeHTML(escape bool) OptionFunc {
+	return func(re *Render) {
+		re.jsonEscapeHTML = escape
+	}
+}
+
+// JSON codifica v como JSON y lo escribe en la respuesta con el código de
+// estado indicado, o con 200 si es cero. La codificación se hace primero sobre
+// un búfer, así que si falla no se escribe nada y se puede responder con un
+// error. Si falla la escritura se devuelve un error que envuelve ErrWrite. Se
+// envía Content-Length, así que a una petición HEAD, cuyo cuerpo descarta
+// net/http, se le indica el tamaño real.
+func (re *Render) JSON(w http.ResponseWriter, status int, v any) error {
+	return re.writeJSON(w, status, "application/json", v)
+}
+
+// writeJSON es JSON con el Content-Type indicado.
+func (re *Render) writeJSON(w http.ResponseWriter, status int, contentType string, v any) error {
+	if err := re.checkStarted(w, "json"); err != nil {
+		return err
+	}
+
+	buf := getBuffer()
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(re.jsonEscapeHTML)
+	if re.jsonIndent != "" {
+		enc.SetIndent("", re.jsonIndent)
+	}
+
+	err := enc.Encode(v)
+	if err != nil {
+		re.log().Error("error encoding json:", "error", err)
+		putBuffer(buf)
+		return err
+	}
+
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.Header().Set("Content-Type", contentType)
+	setContentLength(w, buf, status)
+	w.WriteHeader(status)
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		re.log().Error("error writing json to browser:", "error", err)
+		return fmt.Errorf("%w: %w", ErrWrite, err)
+	}
+
+	putBuffer(buf)
+	return nil
+}
+
+// toJSON codifica v como JSON para incrustarlo en un <script>. Se registra
+// como "json". encoding/json ya escapa <, >, &, U+2028 y U+2029, así que ni
+// "</script>" ni "<!--" pueden cerrar el bloque. Si v no se puede codificar
+// la ejecución de la plantilla falla con el error.
+//
+// Ejemplo:
+//
+//	<script>window.__STATE__ = {{ json .Data.state }};</script>
+func toJSON(v any) (template.JS, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return template.JS(data), nil
+}
+
+// toJSONPretty funciona igual que toJSON pero con sangría, para depurar. Se
+// registra como "jsonPretty".
+func toJSONPretty(v any) (template.JS, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return template.JS(data), nil
+}
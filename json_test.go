@@ -0,0 +1,132 @@
+package gorender
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// failingWriter es un http.ResponseWriter cuyas escrituras fallan, como
+// cuando el cliente ha cerrado la conexión.
+type failingWriter struct {
+	*httptest.ResponseRecorder
+}
+
+var errBrokenPipe = errors.New("broken pipe")
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errBrokenPipe
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []OptionFunc
+		status int
+		want   string
+		code   int
+	}{
+		{"default", nil, http.StatusCreated, `{"a":"\u003cb\u003e"}` + "\n", http.StatusCreated},
+		{"zero status", nil, 0, `{"a":"\u003cb\u003e"}` + "\n", http.StatusOK},
+		{"no escape", []OptionFunc{WithJSONEscapeHTML(false)}, 200, `{"a":"<b>"}` + "\n", http.StatusOK},
+		{"indent", []OptionFunc{WithJSONIndent("  ")}, 200, "{\n  \"a\": \"\\u003cb\\u003e\"\n}\n", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := newTestRender(t, nil, tt.opts...)
+			rec := httptest.NewRecorder()
+			if err := re.JSON(rec, tt.status, map[string]string{"a": "<b>"}); err != nil {
+				t.Fatalf("JSON: %v", err)
+			}
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d", rec.Code, tt.code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONEncodeError(t *testing.T) {
+	re := newTestRender(t, nil)
+	rec := httptest.NewRecorder()
+
+	if err := re.JSON(rec, http.StatusOK, map[string]any{"f": func() {}}); err == nil {
+		t.Fatal("JSON with a func returned no error")
+	}
+	if rec.Body.Len() != 0 || len(rec.Header()) != 0 {
+		t.Errorf("JSON wrote a response after failing: %q %v", rec.Body.String(), rec.Header())
+	}
+}
+
+func TestJSONWriteError(t *testing.T) {
+	re := newTestRender(t, nil)
+
+	err := re.JSON(failingWriter{httptest.NewRecorder()}, http.StatusOK, "ok")
+	if !errors.Is(err, ErrWrite) || !errors.Is(err, errBrokenPipe) {
+		t.Errorf("JSON error = %v, want ErrWrite wrapping the write error", err)
+	}
+}
+
+func TestJSONFunc(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/state.html":  `<script>window.__STATE__ = {{ json .Data.state }};</script>`,
+		"pages/pretty.html": `<script>window.__STATE__ = {{ jsonPretty .Data.state }};</script>`,
+	})
+	state := map[string]string{
+		"close":   "</script><script>alert(1)</script>",
+		"comment": "<!-- x",
+		"lines":   "a\u2028b\u2029c",
+	}
+
+	for _, page := range []string{"state.html", "pretty.html"} {
+		t.Run(page, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			td := &TemplateData{Data: map[string]interface{}{"state": state}}
+			if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), page, td); err != nil {
+				t.Fatalf("Template: %v", err)
+			}
+			body := rec.Body.String()
+
+			if n := strings.Count(body, "</script>"); n != 1 {
+				t.Errorf("body has %d </script>, want only the closing tag: %s", n, body)
+			}
+			for _, s := range []string{"<!--", "\u2028", "\u2029"} {
+				if strings.Contains(body, s) {
+					t.Errorf("body contains %q unescaped: %s", s, body)
+				}
+			}
+
+			raw := strings.TrimSuffix(strings.TrimPrefix(body, "<script>window.__STATE__ = "), ";</script>")
+			var got map[string]string
+			if err := json.Unmarshal([]byte(raw), &got); err != nil {
+				t.Fatalf("decoding %q: %v", raw, err)
+			}
+			for k, v := range state {
+				if got[k] != v {
+					t.Errorf("state[%q] = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONFuncError(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/state.html": `<script>{{ json .Data.state }}</script>`})
+
+	rec := httptest.NewRecorder()
+	td := &TemplateData{Data: map[string]interface{}{"state": func() {}}}
+	if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "state.html", td); err == nil {
+		t.Fatal("Template with an unencodable value returned no error")
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("body = %q, want none", rec.Body.String())
+	}
+}
@@ -0,0 +1,171 @@
+package gorender
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// WithLayoutsPath indica el directorio de las bases (layouts) que se pueden
+// elegir en cada llamada con TemplateWithLayout. Por defecto es el directorio
+// "layouts" dentro de TemplatesPath.
+func WithLayoutsPath(layoutsPath string) OptionFunc {
+	return func(re *Render) {
+		re.layoutsPath = layoutsPath
+	}
+}
+
+// TemplateWithLayout procesa la página dentro de la base indicada, por ejemplo
+// "admin.html" dentro de LayoutsPath. Las definiciones de la base sustituyen a
+// las de las plantillas compartidas, así que varias bases pueden definir el
+// mismo bloque ("base") y cada página elige la suya en cada llamada.
+//
+// Cada combinación de base y página se procesa por separado, de modo que usar
+// la misma página con dos bases distintas no mezcla sus definiciones.
+func (re *Render) TemplateWithLayout(w http.ResponseWriter, r *http.Request, layout, page string, td *TemplateData) (err error) {
+	start := time.Now()
+	defer func() { re.finish(w, r, start, page, td, err) }()
+
+	td = re.addDefaultData(td, r)
+	t, key, err := re.lookupLayout(layout, page, td)
+	if err != nil {
+		return err
+	}
+
+	buf := getBuffer()
+	td.template = key
+	re.drainFlashes(w, r, td)
+	err = re.executeGuarded(buf, r, page, func(w io.Writer) error {
+		return t.Execute(w, td)
+	}, "layout", layout)
+	if err != nil {
+		re.stats.failed(layout + ":" + key)
+		putBuffer(buf)
+		return err
+	}
+	re.stats.rendered(layout + ":" + key)
+
+	return re.respond(w, r, page, buf, td)
+}
+
+// RenderLayoutTo procesa la página dentro de la base indicada, como
+// TemplateWithLayout, y la escribe en w sin necesidad de una petición HTTP.
+// Igual que RenderTo, el token CSRF queda como venga en td y si la página
+// falla no se escribe nada en w.
+func (re *Render) RenderLayoutTo(w io.Writer, layout, page string, td *TemplateData) (err error) {
+	start := time.Now()
+	defer func() { re.observe(start, page, td, err) }()
+
+	td = re.baseData(td)
+	t, key, err := re.lookupLayout(layout, page, td)
+	if err != nil {
+		return err
+	}
+
+	td.template = key
+	buf := getBuffer()
+	err = re.executeGuarded(buf, nil, page, func(w io.Writer) error {
+		return t.Execute(w, td)
+	}, "layout", layout)
+	if err != nil {
+		re.stats.failed(layout + ":" + key)
+		putBuffer(buf)
+		return err
+	}
+	re.stats.rendered(layout + ":" + key)
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		return err
+	}
+
+	putBuffer(buf)
+	return nil
+}
+
+// lookupLayout devuelve la página procesada dentro de la base indicada y su
+// nombre resuelto. Con la caché habilitada cada combinación se procesa una
+// sola vez. Si td trae funciones propias se devuelve una copia con ellas.
+func (re *Render) lookupLayout(layout, page string, td *TemplateData) (*template.Template, string, error) {
+	set, err := re.setFor(page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key, ok := set.resolve(page)
+	re.stats.lookup(page, ok)
+	if !ok {
+		return nil, "", re.missingError(set, page)
+	}
+
+	set.layoutsMu.Lock()
+	defer set.layoutsMu.Unlock()
+
+	lk := layoutKey{layout, key}
+	if t, ok := set.layouts[lk]; ok {
+		t, err = re.withFuncs(set, t, set.layoutsPristine[lk], page, td)
+		return t, key, err
+	}
+
+	layoutFile := filepath.Join(re.layoutsDir(), filepath.FromSlash(layout))
+	if re.fs != nil {
+		layoutFile = path.Join(fsPath(re.layoutsDir()), layout)
+	}
+
+	shared := make([]string, 0, len(set.sharedFiles)+1)
+	shared = append(shared, set.sharedFiles...)
+	shared = append(shared, layoutFile)
+
+	var t *template.Template
+	if content, ok := set.memory[key]; ok {
+		t, err = re.parseMemoryPage(key, content, shared...)
+	} else {
+		t, err = re.parsePage(set.pageFiles[key], shared...)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("parsing page template %s with layout %s: %w", page, layout, err)
+	}
+
+	set.layouts[lk] = t
+	if re.keepsPristine() {
+		if c, err := t.Clone(); err == nil {
+			set.layoutsPristine[lk] = c
+		}
+	}
+
+	t, err = re.withFuncs(set, t, set.layoutsPristine[lk], page, td)
+	return t, key, err
+}
+
+func (re *Render) layoutsDir() string {
+	if re.layoutsPath != "" {
+		return re.layoutsPath
+	}
+
+	return filepath.Join(re.TemplatesPath, "layouts")
+}
+
+// withoutLayouts quita de files las bases que estén dentro de LayoutsPath, para
+// que no sustituyan a las definiciones de las plantillas compartidas salvo
+// cuando se eligen con TemplateWithLayout.
+func (re *Render) withoutLayouts(files []string) []string {
+	dir := re.layoutsDir()
+	if re.fs != nil {
+		dir = fsPath(dir)
+	}
+	dir = filepath.Clean(dir) + string(filepath.Separator)
+
+	kept := make([]string, 0, len(files))
+	for _, file := range files {
+		if !strings.HasPrefix(filepath.Clean(file), dir) {
+			kept = append(kept, file)
+		}
+	}
+
+	return kept
+}
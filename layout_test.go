@@ -0,0 +1,54 @@
+package gorender
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTemplateWithLayout(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"shared/layouts/admin.html":  `{{ define "base" }}<admin>{{ block "sidebar" . }}admin sidebar{{ end }}|{{ template "content" . }}</admin>{{ end }}`,
+		"shared/layouts/public.html": `{{ define "base" }}<public>{{ block "sidebar" . }}public sidebar{{ end }}|{{ template "content" . }}</public>{{ end }}`,
+		"pages/users.html":           `{{ template "base" . }}{{ define "content" }}usuarios{{ end }}{{ define "sidebar" }}filtros{{ end }}`,
+		"pages/home.html":            `{{ template "base" . }}{{ define "content" }}inicio{{ end }}`,
+	})
+
+	tests := []struct {
+		layout string
+		page   string
+		want   string
+	}{
+		{"admin.html", "users.html", "<admin>filtros|usuarios</admin>"},
+		{"public.html", "home.html", "<public>public sidebar|inicio</public>"},
+		{"admin.html", "home.html", "<admin>admin sidebar|inicio</admin>"},
+		{"public.html", "users.html", "<public>filtros|usuarios</public>"},
+		// Otra vez, ya desde la caché de combinaciones.
+		{"public.html", "home.html", "<public>public sidebar|inicio</public>"},
+		{"admin.html", "home.html", "<admin>admin sidebar|inicio</admin>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout+" "+tt.page, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if err := re.TemplateWithLayout(rec, httptest.NewRequest("GET", "/", nil), tt.layout, tt.page, nil); err != nil {
+				t.Fatalf("TemplateWithLayout: %v", err)
+			}
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+
+			var buf bytes.Buffer
+			if err := re.RenderLayoutTo(&buf, tt.layout, tt.page, nil); err != nil {
+				t.Fatalf("RenderLayoutTo: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("RenderLayoutTo = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := re.RenderLayoutTo(&bytes.Buffer{}, "missing.html", "home.html", nil); err == nil {
+		t.Error("RenderLayoutTo with a missing layout: got nil error")
+	}
+}
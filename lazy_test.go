@@ -0,0 +1,141 @@
+package gorender
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+)
+
+// countingFS cuenta las veces que se abre cada archivo de fsys.
+type countingFS struct {
+	fsys  fs.FS
+	mu    sync.Mutex
+	opens map[string]int
+}
+
+func (c *countingFS) Open(name string) (fs.File, error) {
+	c.mu.Lock()
+	c.opens[name]++
+	c.mu.Unlock()
+
+	return c.fsys.Open(name)
+}
+
+func (c *countingFS) count(name string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.opens[name]
+}
+
+func newLazyRender(t *testing.T, opts ...OptionFunc) (*Render, *countingFS) {
+	t.Helper()
+	fsys := &countingFS{
+		fsys: fstest.MapFS{
+			"shared/base.html":  &fstest.MapFile{Data: []byte(`{{ define "base" }}<main>{{ template "content" . }}</main>{{ end }}`)},
+			"pages/index.html":  &fstest.MapFile{Data: []byte(`{{ template "base" . }}{{ define "content" }}inicio{{ end }}`)},
+			"pages/broken.html": &fstest.MapFile{Data: []byte(`{{ if }}`)},
+		},
+		opens: map[string]int{},
+	}
+	re := newTestRender(t, nil, append([]OptionFunc{WithFS(fsys), WithLazyParse(true)}, opts...)...)
+
+	return re, fsys
+}
+
+func TestLazyParse(t *testing.T) {
+	re, fsys := newLazyRender(t)
+	if n := fsys.count("pages/index.html"); n != 0 {
+		t.Fatalf("index.html opened %d times by New, want 0", n)
+	}
+	if keys := re.Templates(); len(keys) != 0 {
+		t.Errorf("Templates() = %v before any render, want none", keys)
+	}
+
+	var buf bytes.Buffer
+	if err := re.RenderTo(&buf, "index.html", nil); err != nil {
+		t.Fatalf("RenderTo: %v", err)
+	}
+	if buf.String() != "<main>inicio</main>" {
+		t.Errorf("index.html = %q", buf.String())
+	}
+	parsed := fsys.count("pages/index.html")
+	if parsed == 0 {
+		t.Fatal("index.html was not read on the first render")
+	}
+	if n := fsys.count("pages/broken.html"); n != 0 {
+		t.Errorf("broken.html opened %d times rendering index.html, want 0", n)
+	}
+
+	for range 3 {
+		if err := re.RenderTo(io.Discard, "index.html", nil); err != nil {
+			t.Fatalf("RenderTo: %v", err)
+		}
+	}
+	if n := fsys.count("pages/index.html"); n != parsed {
+		t.Errorf("index.html opened %d times after more renders, want %d", n, parsed)
+	}
+	if keys := re.Templates(); len(keys) != 1 || keys[0] != "index.html" {
+		t.Errorf("Templates() = %v, want [index.html]", keys)
+	}
+
+	if err := re.RenderTo(io.Discard, "broken.html", nil); err == nil {
+		t.Error("RenderTo of a page with a syntax error: got nil error")
+	}
+	if err := re.RenderTo(io.Discard, "missing.html", nil); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("RenderTo of a missing page: err = %v, want ErrTemplateNotFound", err)
+	}
+	if err := re.RenderTo(io.Discard, "index.html", nil); err != nil {
+		t.Errorf("index.html after the failed renders: %v", err)
+	}
+}
+
+func TestLazyParseConcurrent(t *testing.T) {
+	first, fsys := newLazyRender(t)
+	if err := first.RenderTo(io.Discard, "index.html", nil); err != nil {
+		t.Fatalf("RenderTo: %v", err)
+	}
+	once := fsys.count("pages/index.html")
+
+	re, fsys := newLazyRender(t)
+	var (
+		wg     sync.WaitGroup
+		failed atomic.Int32
+	)
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var buf bytes.Buffer
+			if err := re.RenderTo(&buf, "index.html", nil); err != nil || buf.String() != "<main>inicio</main>" {
+				failed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := failed.Load(); n != 0 {
+		t.Errorf("%d concurrent renders failed", n)
+	}
+	if n := fsys.count("pages/index.html"); n != once {
+		t.Errorf("index.html opened %d times by concurrent first renders, want %d as a single parse", n, once)
+	}
+}
+
+func TestLazyParseWithoutCache(t *testing.T) {
+	re, fsys := newLazyRender(t, WithCache(false))
+
+	for range 2 {
+		if err := re.RenderTo(io.Discard, "index.html", nil); err != nil {
+			t.Fatalf("RenderTo: %v", err)
+		}
+		if n := fsys.count("pages/broken.html"); n != 0 {
+			t.Errorf("broken.html opened %d times rendering index.html, want 0", n)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package gorender
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// liveReloadPing es cada cuánto se envía un comentario a los navegadores
+// conectados para que los proxies no cierren la conexión.
+const liveReloadPing = 30 * time.Second
+
+// WithLiveReload hace que, cada vez que se reconstruye la caché, por ejemplo
+// con WithWatch, los navegadores abiertos recarguen la página. Se añade a cada
+// respuesta HTML con </body> un pequeño script que se conecta a endpoint, la
+// ruta en la que la aplicación monta LiveReloadHandler. Pensado sólo para
+// desarrollo: con endpoint vacío, lo normal en producción, no se inyecta nada.
+//
+// Ejemplo:
+//
+//	ren := gorender.New(gorender.WithWatch(true), gorender.WithLiveReload("/_livereload"))
+//	mux.Handle("/_livereload", ren.LiveReloadHandler())
+func WithLiveReload(endpoint string) OptionFunc {
+	return func(re *Render) {
+		re.liveReload.endpoint = endpoint
+	}
+}
+
+// liveReload guarda los navegadores conectados a LiveReloadHandler.
+type liveReload struct {
+	endpoint string
+	mu       sync.Mutex
+	clients  map[chan struct{}]struct{}
+}
+
+// LiveReloadHandler devuelve el http.Handler de WithLiveReload, que mantiene
+// abierta una conexión de Server-Sent Events con cada navegador y le envía un
+// evento "reload" cuando se reconstruye la caché. Sin WithLiveReload responde
+// con un 404.
+func (re *Render) LiveReloadHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if re.liveReload.endpoint == "" || !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		events := re.liveReload.subscribe()
+		defer re.liveReload.unsubscribe(events)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		ping := time.NewTicker(liveReloadPing)
+		defer ping.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ping.C:
+				_, err := fmt.Fprint(w, ": ping\n\n")
+				if err != nil {
+					return
+				}
+			case <-events:
+				_, err := fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+				if err != nil {
+					return
+				}
+			}
+			flusher.Flush()
+		}
+	})
+}
+
+func (lr *liveReload) subscribe() chan struct{} {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	if lr.clients == nil {
+		lr.clients = map[chan struct{}]struct{}{}
+	}
+	ch := make(chan struct{}, 1)
+	lr.clients[ch] = struct{}{}
+
+	return ch
+}
+
+func (lr *liveReload) unsubscribe(ch chan struct{}) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	delete(lr.clients, ch)
+}
+
+// broadcast avisa a todos los navegadores conectados. Si uno todavía no ha
+// recibido el aviso anterior no se le envía otro.
+func (lr *liveReload) broadcast() {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	for ch := range lr.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// injectLiveReload añade el script de WithLiveReload antes del último </body>
+// de buf. Las respuestas que no son HTML y los fragmentos sin </body> se
+// dejan como están.
+func (re *Render) injectLiveReload(w http.ResponseWriter, buf *bytes.Buffer, td *TemplateData) {
+	if re.liveReload.endpoint == "" {
+		return
+	}
+
+	contentType := w.Header().Get("Content-Type")
+	if contentType == "" {
+		contentType = td.ContentType
+	}
+	if contentType == "" {
+		contentType = re.contentType
+	}
+	if !strings.Contains(contentType, "html") {
+		return
+	}
+
+	body := buf.Bytes()
+	i := bytes.LastIndex(bytes.ToLower(body), []byte("</body>"))
+	if i < 0 {
+		return
+	}
+
+	endpoint, _ := json.Marshal(re.liveReload.endpoint)
+	script := fmt.Sprintf(liveReloadScript, nonceAttr(td.CSPNonce), endpoint)
+
+	out := make([]byte, 0, len(body)+len(script))
+	out = append(out, body[:i]...)
+	out = append(out, script...)
+	out = append(out, body[i:]...)
+	buf.Reset()
+	buf.Write(out)
+}
+
+// liveReloadScript recarga la página al recibir el evento y también al volver
+// a conectar tras perder la conexión, que es lo que pasa al reiniciar el
+// servidor.
+const liveReloadScript = `<script%s>(function(){var lost=false,es=new EventSource(%s);` +
+	`es.addEventListener("reload",function(){location.reload()});` +
+	`es.onerror=function(){lost=true};es.onopen=function(){if(lost)location.reload()};})();</script>`
@@ -0,0 +1,102 @@
+package gorender
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLiveReloadInjection(t *testing.T) {
+	files := map[string]string{
+		"pages/index.html":    `<html><body><p>hola</p></BODY></html>`,
+		"pages/fragment.html": `<p>fragmento</p>`,
+		"pages/data.html":     `{"body": "</body>"}`,
+	}
+	script := `<script>(function(){var lost=false,es=new EventSource("/_livereload");`
+
+	tests := []struct {
+		name        string
+		opts        []OptionFunc
+		page        string
+		contentType string
+		tdType      string
+		injected    bool
+	}{
+		{"html page", []OptionFunc{WithLiveReload("/_livereload")}, "index.html", "", "", true},
+		{"fragment", []OptionFunc{WithLiveReload("/_livereload")}, "fragment.html", "", "", false},
+		{"json from the handler", []OptionFunc{WithLiveReload("/_livereload")}, "data.html", "application/json", "", false},
+		{"json from TemplateData", []OptionFunc{WithLiveReload("/_livereload")}, "data.html", "", "application/json", false},
+		{"disabled", nil, "index.html", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := newTestRender(t, files, tt.opts...)
+			rec := httptest.NewRecorder()
+			if tt.contentType != "" {
+				rec.Header().Set("Content-Type", tt.contentType)
+			}
+			td := &TemplateData{ContentType: tt.tdType}
+			if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), tt.page, td); err != nil {
+				t.Fatalf("Template: %v", err)
+			}
+
+			body := rec.Body.String()
+			if got := strings.Contains(body, script); got != tt.injected {
+				t.Fatalf("script injected = %v, want %v: %s", got, tt.injected, body)
+			}
+			if tt.injected && !strings.HasSuffix(body, "})();</script></BODY></html>") {
+				t.Errorf("script is not right before </body>: %s", body)
+			}
+			if tt.injected && rec.Header().Get("Content-Length") != "" && rec.Header().Get("Content-Length") != strconv.Itoa(len(body)) {
+				t.Errorf("Content-Length = %s, body has %d bytes", rec.Header().Get("Content-Length"), len(body))
+			}
+		})
+	}
+}
+
+func TestLiveReloadNonce(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": `<body></body>`},
+		WithLiveReload("/_livereload"), WithCSP("script-src 'nonce-{nonce}'"))
+
+	rec := httptest.NewRecorder()
+	if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "index.html", nil); err != nil {
+		t.Fatalf("Template: %v", err)
+	}
+	if !strings.Contains(rec.Body.String(), `<script nonce="`) {
+		t.Errorf("live reload script has no nonce: %s", rec.Body.String())
+	}
+}
+
+func TestLiveReloadHandler(t *testing.T) {
+	files := map[string]string{"pages/index.html": `hola`}
+
+	rec := httptest.NewRecorder()
+	newTestRender(t, files).LiveReloadHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/_livereload", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status without WithLiveReload = %d, want 404", rec.Code)
+	}
+
+	re := newTestRender(t, files, WithLiveReload("/_livereload"))
+	srv := httptest.NewServer(re.LiveReloadHandler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+
+	if err := re.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || line != "event: reload\n" {
+		t.Errorf("first event = %q, %v, want the reload event", line, err)
+	}
+}
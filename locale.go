@@ -0,0 +1,286 @@
+package gorender
+
+import (
+	"html/template"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// localeParam es el nombre del parámetro y de la cookie con los que el usuario
+// puede elegir el idioma.
+const localeParam = "lang"
+
+// defaultLocale es el idioma de translateKey cuando no se configura otro.
+const defaultLocale = "es_ES"
+
+// WithLocales activa la detección del idioma de cada petición. El idioma se
+// toma, por este orden, del parámetro "lang" de la URL, de la cookie "lang" y
+// de la cabecera Accept-Language, y debe ser uno de supported. Si ninguno
+// coincide se usa defaultLocale. El resultado se guarda en
+// TemplateData.Locale.
+//
+// Los idiomas se pueden indicar como "es_ES" o "es-ES"; una petición en "es"
+// o "es-MX" se resuelve al primer idioma soportado de la misma lengua.
+func WithLocales(defaultLocale string, supported ...string) OptionFunc {
+	return func(re *Render) {
+		re.defaultLocale = defaultLocale
+		re.locales = append([]string{defaultLocale}, supported...)
+	}
+}
+
+// Locale devuelve el idioma que le corresponde a r según WithLocales. Sin
+// idiomas configurados devuelve una cadena vacía.
+func (re *Render) Locale(r *http.Request) string {
+	if len(re.locales) == 0 {
+		return ""
+	}
+
+	if lang := r.URL.Query().Get(localeParam); lang != "" {
+		if locale, ok := re.matchLocale(lang); ok {
+			return locale
+		}
+	}
+
+	if c, err := r.Cookie(localeParam); err == nil {
+		if locale, ok := re.matchLocale(c.Value); ok {
+			return locale
+		}
+	}
+
+	for _, lang := range acceptLanguages(r.Header.Get("Accept-Language")) {
+		if locale, ok := re.matchLocale(lang); ok {
+			return locale
+		}
+	}
+
+	return re.defaultLocale
+}
+
+// matchLocale busca lang entre los idiomas soportados, primero de forma exacta
+// y después sólo por la lengua.
+func (re *Render) matchLocale(lang string) (string, bool) {
+	lang = normalizeLocale(lang)
+	for _, locale := range re.locales {
+		if normalizeLocale(locale) == lang {
+			return locale, true
+		}
+	}
+
+	base, _, _ := strings.Cut(lang, "_")
+	for _, locale := range re.locales {
+		localeBase, _, _ := strings.Cut(normalizeLocale(locale), "_")
+		if localeBase == base {
+			return locale, true
+		}
+	}
+
+	return "", false
+}
+
+// translateKey traduce key al idioma indicado o, si no se indica, al idioma
+// por defecto. En las plantillas, con WithLocales, el idioma por defecto es el
+// de la petición; ver localeFuncNames.
+//
+// Ejemplo:
+//
+//	{{ translateKey "welcome" }}
+func (re *Render) translateKey(key string, locale ...string) string {
+	lang := re.defaultLocale
+	if len(locale) > 0 {
+		lang = locale[0]
+	}
+
+	if translated, ok := re.translation(lang, key); ok {
+		return translated
+	}
+	return key
+}
+
+// translation busca key en las traducciones del idioma lang, o del idioma por
+// defecto si lang está vacío. Las traducciones se leen al procesar las
+// plantillas, así que aquí nunca se lee ningún archivo.
+func (re *Render) translation(lang, key string) (string, bool) {
+	return re.translateCatalog(re.resolveLocale(lang), key)
+}
+
+// resolveLocale devuelve lang o, si está vacío, el idioma por defecto.
+func (re *Render) resolveLocale(lang string) string {
+	if lang == "" {
+		lang = re.defaultLocale
+	}
+	if lang == "" {
+		lang = defaultLocale
+	}
+	return lang
+}
+
+// normalizeLocale pasa "es-ES" y "ES_es" a "es_es" para poder compararlos.
+func normalizeLocale(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "-", "_"))
+}
+
+// acceptLanguages devuelve los idiomas de una cabecera Accept-Language
+// ordenados por preferencia.
+func acceptLanguages(header string) []string {
+	type weighted struct {
+		lang string
+		q    float64
+	}
+
+	var langs []weighted
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if lang == "" || lang == "*" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		langs = append(langs, weighted{lang, q})
+	}
+
+	sort.SliceStable(langs, func(i, j int) bool {
+		return langs[i].q > langs[j].q
+	})
+
+	result := make([]string, len(langs))
+	for i, l := range langs {
+		result[i] = l.lang
+	}
+	return result
+}
+
+// localeFuncNames son las funciones de las plantillas que dependen del idioma.
+// Con WithLocales, las que no se han sustituido con WithFunctions se atan en
+// cada renderizado al idioma de TemplateData.Locale, así que las plantillas no
+// necesitan pasárselo.
+var localeFuncNames = []string{
+	"translateKey", "plural", "formatNumber", "formatCurrency", "formatCents",
+	"formatDate", "timeAgo", "humanDuration", "renderBreadcrumbs",
+	"breadcrumbsJSONLD",
+}
+
+// localized son las funciones de localeFuncNames atadas al idioma lang, que se
+// usa cuando la plantilla no indica otro.
+type localized struct {
+	re   *Render
+	lang string
+}
+
+// locale devuelve locale si la plantilla ha indicado un idioma o lang si no.
+func (l localized) locale(locale []string) []string {
+	if len(locale) > 0 {
+		return locale
+	}
+	return []string{l.lang}
+}
+
+func (l localized) translateKey(key string, locale ...string) string {
+	return l.re.translateKey(key, l.locale(locale)...)
+}
+
+func (l localized) plural(key string, count interface{}, args ...interface{}) (string, error) {
+	if len(args)%2 == 0 {
+		args = append([]interface{}{l.lang}, args...)
+	}
+	return l.re.plural(key, count, args...)
+}
+
+func (l localized) formatNumber(v interface{}, locale ...string) (string, error) {
+	return l.re.formatNumber(v, l.locale(locale)...)
+}
+
+func (l localized) formatCurrency(amount interface{}, code string, locale ...string) (string, error) {
+	return l.re.formatCurrency(amount, code, l.locale(locale)...)
+}
+
+func (l localized) formatCents(amount interface{}, code string, locale ...string) (string, error) {
+	return l.re.formatCents(amount, code, l.locale(locale)...)
+}
+
+func (l localized) formatDate(v interface{}, style string, locale ...string) (string, error) {
+	return l.re.formatDate(v, style, l.locale(locale)...)
+}
+
+func (l localized) timeAgo(v interface{}, locale ...string) (string, error) {
+	return l.re.timeAgo(v, l.locale(locale)...)
+}
+
+func (l localized) humanDuration(v interface{}, locale ...string) (string, error) {
+	return l.re.humanDuration(v, l.locale(locale)...)
+}
+
+func (l localized) renderBreadcrumbs(crumbs []Breadcrumb, locale ...string) (template.HTML, error) {
+	return l.re.renderBreadcrumbs(crumbs, l.locale(locale)...)
+}
+
+func (l localized) breadcrumbsJSONLD(crumbs []Breadcrumb, locale ...string) (template.HTML, error) {
+	return l.re.breadcrumbsJSONLD(crumbs, l.locale(locale)...)
+}
+
+// localeFuncs devuelve las funciones de localeFuncNames atadas a lang, sin las
+// que se han sustituido con WithFunctions.
+func (re *Render) localeFuncs(lang string) template.FuncMap {
+	l := localized{re, lang}
+	all := template.FuncMap{
+		"translateKey":      l.translateKey,
+		"plural":            l.plural,
+		"formatNumber":      l.formatNumber,
+		"formatCurrency":    l.formatCurrency,
+		"formatCents":       l.formatCents,
+		"formatDate":        l.formatDate,
+		"timeAgo":           l.timeAgo,
+		"humanDuration":     l.humanDuration,
+		"renderBreadcrumbs": l.renderBreadcrumbs,
+		"breadcrumbsJSONLD": l.breadcrumbsJSONLD,
+	}
+
+	funcs := template.FuncMap{}
+	for _, name := range re.boundFuncs {
+		funcs[name] = all[name]
+	}
+
+	return funcs
+}
+
+// boundLocale devuelve el idioma al que hay que atar las funciones en el
+// renderizado de td, o una cadena vacía si no hace falta porque no se usa
+// WithLocales o el idioma es el de por defecto, con el que ya se procesan.
+func (re *Render) boundLocale(td *TemplateData) string {
+	if len(re.locales) == 0 || len(re.boundFuncs) == 0 || td == nil || td.Locale == "" {
+		return ""
+	}
+	if normalizeLocale(td.Locale) == normalizeLocale(re.resolveLocale("")) {
+		return ""
+	}
+
+	return td.Locale
+}
+
+// setBoundFuncs anota las funciones de localeFuncNames que no se han
+// sustituido con WithFunctions, que son las que se atan al idioma.
+func (re *Render) setBoundFuncs() {
+	replaced := map[string]bool{}
+	for _, name := range re.funcCollisions {
+		replaced[name] = true
+	}
+
+	re.boundFuncs = nil
+	for _, name := range localeFuncNames {
+		if !replaced[name] && re.Functions[name] != nil {
+			re.boundFuncs = append(re.boundFuncs, name)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package gorender
+
+import (
+	"bytes"
+	"html/template"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func localeCatalogs() fstest.MapFS {
+	return fstest.MapFS{
+		"es.json": &fstest.MapFile{Data: []byte(`{"hello": "Hola", "items": {"one": "{count} elemento", "other": "{count} elementos"}}`)},
+		"en.json": &fstest.MapFile{Data: []byte(`{"hello": "Hello", "items": {"one": "{count} item", "other": "{count} items"}}`)},
+	}
+}
+
+func TestLocaleDetection(t *testing.T) {
+	re := newTestRender(t, nil, WithLocales("es", "en"))
+
+	tests := []struct {
+		target string
+		cookie string
+		header string
+		want   string
+	}{
+		{"/", "", "", "es"},
+		{"/", "", "en-US,en;q=0.9", "en"},
+		{"/", "", "fr, en;q=0.5", "en"},
+		{"/", "en", "es", "en"},
+		{"/?lang=es", "en", "en", "es"},
+		{"/?lang=de", "", "", "es"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", tt.target, nil)
+		if tt.cookie != "" {
+			r.Header.Set("Cookie", localeParam+"="+tt.cookie)
+		}
+		if tt.header != "" {
+			r.Header.Set("Accept-Language", tt.header)
+		}
+		if got := re.Locale(r); got != tt.want {
+			t.Errorf("Locale(%s, cookie %q, %q) = %q, want %q", tt.target, tt.cookie, tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestLocaleBoundFuncs(t *testing.T) {
+	files := map[string]string{
+		"pages/home.html": `{{define "main"}}{{translateKey "hello"}} {{plural "items" 2}} {{formatNumber 1234.5}} {{translateKey "hello" "es"}}{{end}}{{template "main" .}}`,
+	}
+	re := newTestRender(t, files, WithLocales("es", "en"), WithTranslationsFS(localeCatalogs()))
+
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{"en", "Hello 2 items 1,234.50 Hola"},
+		{"es", "Hola 2 elementos 1.234,50 Hola"},
+		{"en", "Hello 2 items 1,234.50 Hola"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Language", tt.lang)
+		if err := re.Template(rec, r, "home.html", nil); err != nil {
+			t.Fatalf("Template(%s): %v", tt.lang, err)
+		}
+		if got := rec.Body.String(); got != tt.want {
+			t.Errorf("Template(%s) = %q, want %q", tt.lang, got, tt.want)
+		}
+
+		rec = httptest.NewRecorder()
+		if err := re.Block(rec, r, "home.html", "main", nil); err != nil {
+			t.Fatalf("Block(%s): %v", tt.lang, err)
+		}
+		if got := rec.Body.String(); got != tt.want {
+			t.Errorf("Block(%s) = %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestLocaleBoundFuncsWithRequestFuncs(t *testing.T) {
+	files := map[string]string{"pages/home.html": `{{translateKey "hello"}} {{user}}`}
+	re := newTestRender(t, files, WithLocales("es", "en"), WithTranslationsFS(localeCatalogs()), WithRequestFuncs("user"))
+
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "en")
+	td := &TemplateData{Funcs: template.FuncMap{"user": func() string { return "ana" }}}
+	if err := re.Template(rec, r, "home.html", td); err != nil {
+		t.Fatalf("Template: %v", err)
+	}
+	if got, want := rec.Body.String(), "Hello ana"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestLocaleKeepsReplacedFuncs(t *testing.T) {
+	files := map[string]string{"pages/home.html": `{{translateKey "hello"}}`}
+	custom := template.FuncMap{"translateKey": func(key string, _ ...string) string { return strings.ToUpper(key) }}
+	re := newTestRender(t, files, WithLocales("es", "en"), WithTranslationsFS(localeCatalogs()), WithFunctions(custom))
+
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "en")
+	if err := re.Template(rec, r, "home.html", nil); err != nil {
+		t.Fatalf("Template: %v", err)
+	}
+	if got, want := rec.Body.String(), "HELLO"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestLegacyTranslations(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"es_ES.translate": "welcome = Bienvenido\nbroken line\n"})
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	var logs bytes.Buffer
+	re := newTestRender(t, map[string]string{"pages/index.html": `{{ translateKey "welcome" }} {{ translateKey "missing" }}`},
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	got, err := re.TemplateString("index.html", nil)
+	if err != nil {
+		t.Fatalf("TemplateString: %v", err)
+	}
+	if got != "Bienvenido missing" {
+		t.Errorf("output = %q, want the legacy translation", got)
+	}
+	if !strings.Contains(logs.String(), "legacy .translate") {
+		t.Errorf("no deprecation warning logged, logs: %s", logs.String())
+	}
+
+	// Con catálogos JSON los archivos antiguos se ignoran.
+	re = newTestRender(t, map[string]string{"pages/index.html": `{{ translateKey "welcome" }}`},
+		WithTranslationsFS(localeCatalogs()))
+	if got, _ := re.TemplateString("index.html", nil); got != "welcome" {
+		t.Errorf("output with JSON catalogs = %q, want the key", got)
+	}
+}
@@ -0,0 +1,23 @@
+module github.com/zepyrshut/gorender/markdown
+
+go 1.23.0
+
+replace github.com/zepyrshut/gorender => ../
+
+require (
+	github.com/yuin/goldmark v1.8.6
+	github.com/zepyrshut/gorender v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/gabriel-vasile/mimetype v1.4.3 // indirect
+	github.com/go-playground/locales v0.14.1 // indirect
+	github.com/go-playground/universal-translator v0.18.1 // indirect
+	github.com/go-playground/validator/v10 v10.22.0 // indirect
+	github.com/justinas/nosurf v1.1.1 // indirect
+	github.com/leodido/go-urn v1.4.0 // indirect
+	golang.org/x/crypto v0.19.0 // indirect
+	golang.org/x/net v0.21.0 // indirect
+	golang.org/x/sys v0.17.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
@@ -0,0 +1,115 @@
+// Package markdown añade a gorender la función "markdown", que convierte texto
+// en Markdown a HTML. Va en un módulo aparte para que goldmark sólo sea una
+// dependencia de quien lo use.
+//
+// Ejemplo:
+//
+//	ren := gorender.New(
+//	    gorender.WithRenderOptions(renderOpts),
+//	    markdown.WithMarkdown(),
+//	)
+//
+//	{{ markdown .Data.description }}
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sync"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/zepyrshut/gorender"
+)
+
+// maxCached es la cantidad de textos convertidos que se guardan. Al llenarse
+// la caché se vacía entera.
+const maxCached = 512
+
+// Option configura la conversión.
+type Option func(*converter)
+
+// AllowHTML deja pasar el HTML que venga dentro del Markdown, incluidos los
+// <script> y los enlaces javascript:. Por defecto se eliminan, así que sólo se
+// debe usar con contenido de confianza.
+func AllowHTML() Option {
+	return func(c *converter) {
+		c.unsafe = true
+	}
+}
+
+// WithMarkdown registra la función "markdown" en las plantillas. Se puede
+// pasar también un texto vacío o nil, que se convierte en una cadena vacía.
+func WithMarkdown(opts ...Option) gorender.OptionFunc {
+	c := &converter{cache: map[string]template.HTML{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	rendererOpts := []goldmark.Option{goldmark.WithExtensions(extension.GFM)}
+	if c.unsafe {
+		rendererOpts = append(rendererOpts, goldmark.WithRendererOptions(html.WithUnsafe()))
+	}
+	c.md = goldmark.New(rendererOpts...)
+
+	return func(re *gorender.Render) {
+		if re.Functions == nil {
+			re.Functions = template.FuncMap{}
+		}
+		re.Functions["markdown"] = c.convert
+	}
+}
+
+type converter struct {
+	md     goldmark.Markdown
+	unsafe bool
+
+	mu    sync.RWMutex
+	cache map[string]template.HTML
+}
+
+// convert convierte v a HTML. Los textos ya convertidos se sirven desde la
+// caché, porque en un listado se suelen repetir en cada petición.
+func (c *converter) convert(v interface{}) (template.HTML, error) {
+	var src string
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		src = v
+	case []byte:
+		src = string(v)
+	case fmt.Stringer:
+		src = v.String()
+	default:
+		return "", fmt.Errorf("markdown: expected a string, got %T", v)
+	}
+
+	if src == "" {
+		return "", nil
+	}
+
+	c.mu.RLock()
+	out, ok := c.cache[src]
+	c.mu.RUnlock()
+	if ok {
+		return out, nil
+	}
+
+	var buf bytes.Buffer
+	if err := c.md.Convert([]byte(src), &buf); err != nil {
+		return "", err
+	}
+	out = template.HTML(buf.String())
+
+	c.mu.Lock()
+	if len(c.cache) >= maxCached {
+		c.cache = map[string]template.HTML{}
+	}
+	c.cache[src] = out
+	c.mu.Unlock()
+
+	return out, nil
+}
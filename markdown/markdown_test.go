@@ -0,0 +1,109 @@
+package markdown
+
+import (
+	"html/template"
+	"io"
+	"io/fs"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/yuin/goldmark"
+	"github.com/zepyrshut/gorender"
+)
+
+func newRender(t *testing.T, page string, opts ...Option) *gorender.Render {
+	t.Helper()
+	re, err := gorender.NewE(
+		gorender.WithFS(fstest.MapFS{
+			"pages/index.html": &fstest.MapFile{Data: []byte(page)},
+			"shared":           &fstest.MapFile{Mode: fs.ModeDir},
+		}),
+		gorender.WithTemplatesPath("shared"),
+		gorender.WithPageTemplatesPath("pages"),
+		gorender.WithCache(true),
+		gorender.WithCSRFTokenFunc(nil),
+		gorender.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithMarkdown(opts...),
+	)
+	if err != nil {
+		t.Fatalf("NewE: %v", err)
+	}
+	return re
+}
+
+func TestMarkdown(t *testing.T) {
+	re := newRender(t, `{{ markdown .Data.text }}`)
+
+	tests := []struct {
+		name string
+		text interface{}
+		want string
+	}{
+		{"emphasis", "**hola**", "<p><strong>hola</strong></p>\n"},
+		{"table", "| a |\n|---|\n| b |", "<table>\n<thead>\n<tr>\n<th>a</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>b</td>\n</tr>\n</tbody>\n</table>\n"},
+		{"bytes", []byte("*x*"), "<p><em>x</em></p>\n"},
+		{"empty", "", ""},
+		{"nil", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := re.TemplateString("index.html", &gorender.TemplateData{Data: map[string]interface{}{"text": tt.text}})
+			if err != nil {
+				t.Fatalf("TemplateString: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	_, err := re.TemplateString("index.html", &gorender.TemplateData{Data: map[string]interface{}{"text": 42}})
+	if err == nil || !strings.Contains(err.Error(), "expected a string") {
+		t.Errorf("TemplateString with an int error = %v, want a type error", err)
+	}
+}
+
+func TestMarkdownRawHTML(t *testing.T) {
+	text := "<script>alert(1)</script>\n\n[x](javascript:alert(1)) <b onclick=\"x()\">b</b>"
+
+	got, err := newRender(t, `{{ markdown .Data.text }}`).TemplateString("index.html",
+		&gorender.TemplateData{Data: map[string]interface{}{"text": text}})
+	if err != nil {
+		t.Fatalf("TemplateString: %v", err)
+	}
+	for _, unsafe := range []string{"<script", "javascript:", "onclick", "<b"} {
+		if strings.Contains(got, unsafe) {
+			t.Errorf("output %q contains %s by default", got, unsafe)
+		}
+	}
+
+	got, err = newRender(t, `{{ markdown .Data.text }}`, AllowHTML()).TemplateString("index.html",
+		&gorender.TemplateData{Data: map[string]interface{}{"text": text}})
+	if err != nil {
+		t.Fatalf("TemplateString: %v", err)
+	}
+	if !strings.Contains(got, "<script>alert(1)</script>") {
+		t.Errorf("output %q lost the raw HTML with AllowHTML", got)
+	}
+}
+
+func TestConvertCache(t *testing.T) {
+	c := &converter{md: goldmark.New(), cache: map[string]template.HTML{}}
+
+	for i := 0; i < maxCached+10; i++ {
+		if _, err := c.convert(strconv.Itoa(i)); err != nil {
+			t.Fatalf("convert: %v", err)
+		}
+		if len(c.cache) > maxCached {
+			t.Fatalf("cache has %d entries, want at most %d", len(c.cache), maxCached)
+		}
+	}
+
+	c.cache["cached"] = "<p>from cache</p>"
+	if got, _ := c.convert("cached"); got != "<p>from cache</p>" {
+		t.Errorf("convert = %q, want the cached value", got)
+	}
+}
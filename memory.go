@@ -0,0 +1,238 @@
+package gorender
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"sync"
+	"time"
+)
+
+// memoryTemplate es una plantilla registrada con AddTemplate o AddPartial.
+type memoryTemplate struct {
+	name    string
+	content string
+}
+
+// memoryTemplates guarda las plantillas registradas en tiempo de ejecución,
+// que no vienen de ningún archivo y se conservan al reconstruir la caché.
+type memoryTemplates struct {
+	mu    sync.RWMutex
+	pages map[string]string
+	// partials mantiene el orden en que se registraron los fragmentos.
+	partials []memoryTemplate
+}
+
+// AddTemplate registra una página a partir de su contenido, por ejemplo una
+// plantilla de correo guardada en la base de datos. Se procesa con las mismas
+// funciones y plantillas compartidas que las páginas del disco y se renderiza
+// con Template y similares usando name tal cual, sin alias por nombre de
+// archivo. Si ya existe una página con ese nombre, registrada así o leída del
+// disco, se sustituye.
+//
+// Si el contenido no se puede procesar se devuelve el error y se mantiene la
+// versión anterior. Las páginas registradas se conservan al llamar a Reload.
+func (re *Render) AddTemplate(name, content string) error {
+	if name == "" {
+		return errors.New("template name is required")
+	}
+
+	re.reloadMu.Lock()
+	defer re.reloadMu.Unlock()
+
+	cached := re.EnableCache || re.watch
+
+	var shared []string
+	if cached {
+		shared = re.TemplateCache.current().sharedFiles
+	} else {
+		var err error
+		shared, err = re.sharedTemplateFiles()
+		if err != nil {
+			return err
+		}
+	}
+
+	t, err := re.parseMemoryPage(name, content, shared...)
+	if err != nil {
+		return fmt.Errorf("parsing template %s: %w", name, err)
+	}
+
+	re.memory.mu.Lock()
+	if re.memory.pages == nil {
+		re.memory.pages = map[string]string{}
+	}
+	re.memory.pages[name] = content
+	re.memory.mu.Unlock()
+
+	if cached {
+		set := re.TemplateCache.current().clone()
+		set.addMemoryPage(name, content, t)
+		re.keepPristine(set, name, t)
+		re.TemplateCache.swap(set)
+	}
+	re.log().Debug("template added", "template", name)
+
+	return nil
+}
+
+// AddPartial registra una plantilla compartida a partir de su contenido, que
+// se añade a todas las páginas después de las de TemplatesPath, de modo que
+// se puede usar con {{ template "name" . }}. Si ya existe un fragmento con ese
+// nombre se sustituye.
+//
+// Con la caché habilitada se reconstruyen todas las páginas; si alguna falla
+// se devuelve el error y se mantienen tanto la caché como el fragmento
+// anterior. Los fragmentos registrados se conservan al llamar a Reload.
+func (re *Render) AddPartial(name, content string) error {
+	if name == "" {
+		return errors.New("partial name is required")
+	}
+
+	_, err := re.newPage(name).Parse(content)
+	if err != nil {
+		return fmt.Errorf("parsing partial %s: %w", name, err)
+	}
+
+	re.reloadMu.Lock()
+	defer re.reloadMu.Unlock()
+
+	previous := re.setPartial(name, content)
+
+	if re.EnableCache || re.watch {
+		set, err := re.createTemplateCache()
+		if err != nil {
+			re.restorePartials(previous)
+			return err
+		}
+		re.TemplateCache.swap(set)
+	}
+	re.log().Debug("partial added", "partial", name)
+
+	return nil
+}
+
+// setPartial guarda el fragmento name y devuelve la lista anterior.
+func (re *Render) setPartial(name, content string) []memoryTemplate {
+	re.memory.mu.Lock()
+	defer re.memory.mu.Unlock()
+
+	previous := re.memory.partials
+	partials := make([]memoryTemplate, 0, len(previous)+1)
+	replaced := false
+	for _, p := range previous {
+		if p.name == name {
+			p.content = content
+			replaced = true
+		}
+		partials = append(partials, p)
+	}
+	if !replaced {
+		partials = append(partials, memoryTemplate{name: name, content: content})
+	}
+	re.memory.partials = partials
+
+	return previous
+}
+
+func (re *Render) restorePartials(partials []memoryTemplate) {
+	re.memory.mu.Lock()
+	re.memory.partials = partials
+	re.memory.mu.Unlock()
+}
+
+// memoryPartials devuelve los fragmentos registrados. La lista no se modifica
+// nunca una vez guardada, así que puede recorrerse sin el bloqueo.
+func (re *Render) memoryPartials() []memoryTemplate {
+	re.memory.mu.RLock()
+	defer re.memory.mu.RUnlock()
+
+	return re.memory.partials
+}
+
+// memoryPages devuelve una copia de las páginas registradas.
+func (re *Render) memoryPages() map[string]string {
+	re.memory.mu.RLock()
+	defer re.memory.mu.RUnlock()
+
+	pages := make(map[string]string, len(re.memory.pages))
+	for name, content := range re.memory.pages {
+		pages[name] = content
+	}
+
+	return pages
+}
+
+// addPartials añade a ts los fragmentos registrados con AddPartial.
+func (re *Render) addPartials(ts *template.Template) (*template.Template, error) {
+	for _, p := range re.memoryPartials() {
+		_, err := ts.New(p.name).Parse(p.content)
+		if err != nil {
+			return ts, fmt.Errorf("parsing partial %s: %w", p.name, err)
+		}
+	}
+
+	return ts, nil
+}
+
+// parseMemoryPage procesa una página registrada con AddTemplate junto con las
+// plantillas compartidas y los fragmentos registrados.
+func (re *Render) parseMemoryPage(name, content string, shared ...string) (*template.Template, error) {
+	ts, err := parseFiles(re, re.newPage(name), shared...)
+	if err != nil {
+		return nil, err
+	}
+
+	ts, err = re.addPartials(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	return ts.Parse(content)
+}
+
+// sharedTemplateFiles devuelve las plantillas compartidas de TemplatesPath,
+// sin las bases de WithLayoutsPath.
+func (re *Render) sharedTemplateFiles() ([]string, error) {
+	files, err := re.findTemplateFiles(re.TemplatesPath)
+	if err != nil {
+		return nil, fmt.Errorf("finding templates in %s: %w", re.TemplatesPath, err)
+	}
+
+	return re.withoutLayouts(files), nil
+}
+
+// addMemoryPages procesa las páginas registradas con AddTemplate y las añade
+// a set. Si only no está vacío sólo se procesa la página con ese nombre.
+func (re *Render) addMemoryPages(set *templateSet, only string) error {
+	var errs []error
+	for name, content := range re.memoryPages() {
+		if only != "" && name != only {
+			continue
+		}
+
+		t, err := re.parseMemoryPage(name, content, set.sharedFiles...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("parsing template %s: %w", name, err))
+			continue
+		}
+		set.addMemoryPage(name, content, t)
+		re.keepPristine(set, name, t)
+	}
+
+	for _, p := range re.memoryPartials() {
+		set.sources[p.name] = SourceMemory
+	}
+
+	return errors.Join(errs...)
+}
+
+// addMemoryPage guarda en el conjunto una página registrada con AddTemplate.
+func (s *templateSet) addMemoryPage(name, content string, t *template.Template) {
+	s.templates[name] = t
+	s.memory[name] = content
+	s.modTimes[name] = time.Now()
+	s.sources[name] = SourceMemory
+	delete(s.pageFiles, name)
+	delete(s.pristine, name)
+}
@@ -0,0 +1,56 @@
+package gorender
+
+import (
+	"testing"
+)
+
+func TestMemoryTemplatesSurviveRebuilds(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": `inicio {{ template "footer" . }}`})
+	if err := re.AddPartial("footer", `pie`); err != nil {
+		t.Fatalf("AddPartial: %v", err)
+	}
+	if err := re.AddTemplate("email.html", `correo {{ template "footer" . }}`); err != nil {
+		t.Fatalf("AddTemplate: %v", err)
+	}
+
+	check := func(step string) {
+		t.Helper()
+		for page, want := range map[string]string{"index.html": "inicio pie", "email.html": "correo pie"} {
+			got, err := re.TemplateString(page, nil)
+			if err != nil {
+				t.Errorf("%s: %s: %v", step, page, err)
+				continue
+			}
+			if got != want {
+				t.Errorf("%s: %s = %q, want %q", step, page, got, want)
+			}
+		}
+	}
+
+	check("added")
+
+	if err := re.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	check("after Reload")
+
+	for _, page := range []string{"email.html", "index.html"} {
+		if err := re.Rebuild(page); err != nil {
+			t.Fatalf("Rebuild(%s): %v", page, err)
+		}
+	}
+	check("after Rebuild")
+
+	re.Invalidate("email.html")
+	check("after Invalidate")
+
+	if err := re.AddPartial("footer", `pie nuevo`); err != nil {
+		t.Fatalf("AddPartial: %v", err)
+	}
+	if err := re.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got, err := re.TemplateString("email.html", nil); err != nil || got != "correo pie nuevo" {
+		t.Errorf("email.html after replacing the partial = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,99 @@
+package gorender
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// Meta son los datos de la cabecera de la página para buscadores y redes
+// sociales, que se escriben con {{ renderMeta .Meta }}. Los campos vacíos no
+// generan ninguna etiqueta.
+type Meta struct {
+	// Title es el título de la página, al que se aplica el patrón de
+	// WithTitlePattern.
+	Title       string
+	Description string
+	// Canonical es la dirección canónica de la página.
+	Canonical string
+	// Robots es el contenido de <meta name="robots">, como "noindex".
+	Robots string
+	// OGTitle, OGImage y OGType son las etiquetas de Open Graph.
+	OGTitle string
+	OGImage string
+	OGType  string
+	// TwitterCard es el tipo de tarjeta de Twitter, como "summary".
+	TwitterCard string
+}
+
+// WithTitlePattern indica el patrón con el que renderMeta escribe el título de
+// las páginas que lo tienen, donde %s es Meta.Title.
+//
+// Ejemplo:
+//
+//	gorender.WithTitlePattern("%s — MySite")
+func WithTitlePattern(pattern string) OptionFunc {
+	return func(re *Render) {
+		re.titlePattern = pattern
+	}
+}
+
+// renderMeta escribe las etiquetas de meta, escapadas y en el orden habitual,
+// para incluirlas en el <head> de la base.
+//
+// Ejemplo:
+//
+//	<head>{{ renderMeta .Meta }}</head>
+func (re *Render) renderMeta(meta Meta) template.HTML {
+	var b strings.Builder
+	if meta.Title != "" {
+		title := meta.Title
+		if re.titlePattern != "" {
+			title = strings.Replace(re.titlePattern, "%s", title, 1)
+		}
+		fmt.Fprintf(&b, "<title>%s</title>\n", template.HTMLEscapeString(title))
+	}
+	metaTag(&b, "name", "description", meta.Description)
+	metaTag(&b, "name", "robots", meta.Robots)
+	if meta.Canonical != "" {
+		fmt.Fprintf(&b, "<link rel=\"canonical\" href=\"%s\">\n", template.HTMLEscapeString(meta.Canonical))
+	}
+	metaTag(&b, "property", "og:title", meta.OGTitle)
+	metaTag(&b, "property", "og:type", meta.OGType)
+	metaTag(&b, "property", "og:image", meta.OGImage)
+	metaTag(&b, "name", "twitter:card", meta.TwitterCard)
+
+	return template.HTML(b.String())
+}
+
+// metaTag escribe <meta attr="name" content="content"> si content no está
+// vacío.
+func metaTag(b *strings.Builder, attr, name, content string) {
+	if content == "" {
+		return
+	}
+	fmt.Fprintf(b, "<meta %s=\"%s\" content=\"%s\">\n", attr, name, template.HTMLEscapeString(content))
+}
+
+// merge copia en m los campos de other que no están vacíos. Con override a
+// false sólo se rellenan los que m tiene vacíos.
+func (m *Meta) merge(other Meta, override bool) {
+	fields := []struct {
+		dst *string
+		src string
+	}{
+		{&m.Title, other.Title},
+		{&m.Description, other.Description},
+		{&m.Canonical, other.Canonical},
+		{&m.Robots, other.Robots},
+		{&m.OGTitle, other.OGTitle},
+		{&m.OGImage, other.OGImage},
+		{&m.OGType, other.OGType},
+		{&m.TwitterCard, other.TwitterCard},
+	}
+	for _, f := range fields {
+		if f.src != "" && (override || *f.dst == "") {
+			*f.dst = f.src
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package gorender
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRenderMeta(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		meta    Meta
+		want    string
+	}{
+		{"empty", "", Meta{}, ""},
+		{"title", "", Meta{Title: "Inicio"}, "<title>Inicio</title>\n"},
+		{"pattern", "%s — MySite", Meta{Title: "Inicio"}, "<title>Inicio — MySite</title>\n"},
+		{"pattern without title", "%s — MySite", Meta{Description: "d"}, "<meta name=\"description\" content=\"d\">\n"},
+		{
+			"escaped title",
+			"%s | <Site>",
+			Meta{Title: `Tom & "Jerry"`},
+			"<title>Tom &amp; &#34;Jerry&#34; | &lt;Site&gt;</title>\n",
+		},
+		{
+			"escaped description",
+			"",
+			Meta{Description: `"><script>alert(1)</script>`},
+			"<meta name=\"description\" content=\"&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;\">\n",
+		},
+		{
+			"open graph",
+			"",
+			Meta{OGTitle: `A "B"`, OGType: "article", OGImage: "/img.png?a=1&b=2"},
+			"<meta property=\"og:title\" content=\"A &#34;B&#34;\">\n" +
+				"<meta property=\"og:type\" content=\"article\">\n" +
+				"<meta property=\"og:image\" content=\"/img.png?a=1&amp;b=2\">\n",
+		},
+		{
+			"order",
+			"",
+			Meta{TwitterCard: "summary", Canonical: "https://example.com/", Robots: "noindex", Description: "d", Title: "t"},
+			"<title>t</title>\n" +
+				"<meta name=\"description\" content=\"d\">\n" +
+				"<meta name=\"robots\" content=\"noindex\">\n" +
+				"<link rel=\"canonical\" href=\"https://example.com/\">\n" +
+				"<meta name=\"twitter:card\" content=\"summary\">\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := &Render{titlePattern: tt.pattern}
+			if got := string(re.renderMeta(tt.meta)); got != tt.want {
+				t.Errorf("renderMeta = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetaFactoryMerge(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/index.html": `{{ renderMeta .Meta }}`,
+	},
+		WithTitlePattern("%s · Site"),
+		WithTemplateDataFactory(func(r *http.Request) *TemplateData {
+			return &TemplateData{Meta: Meta{
+				Title:       "Por defecto",
+				Description: "Descripción por defecto",
+				OGType:      "website",
+			}}
+		}),
+	)
+
+	tests := []struct {
+		name string
+		td   *TemplateData
+		want string
+	}{
+		{
+			"without handler data",
+			nil,
+			"<title>Por defecto · Site</title>\n" +
+				"<meta name=\"description\" content=\"Descripción por defecto\">\n" +
+				"<meta property=\"og:type\" content=\"website\">\n",
+		},
+		{
+			"handler wins",
+			&TemplateData{Meta: Meta{Title: "Artículo", OGType: "article", Robots: "noindex"}},
+			"<title>Artículo · Site</title>\n" +
+				"<meta name=\"description\" content=\"Descripción por defecto\">\n" +
+				"<meta name=\"robots\" content=\"noindex\">\n" +
+				"<meta property=\"og:type\" content=\"article\">\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "index.html", tt.td); err != nil {
+				t.Fatalf("Template: %v", err)
+			}
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetaMerge(t *testing.T) {
+	m := Meta{Title: "a", Description: "b"}
+	m.merge(Meta{Title: "x", Robots: "noindex"}, false)
+	if m.Title != "a" || m.Robots != "noindex" {
+		t.Errorf("merge without override = %+v", m)
+	}
+
+	m.merge(Meta{Title: "x", Description: ""}, true)
+	if m.Title != "x" || m.Description != "b" {
+		t.Errorf("merge with override = %+v", m)
+	}
+}
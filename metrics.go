@@ -0,0 +1,73 @@
+package gorender
+
+import (
+	"errors"
+	"time"
+)
+
+// Estados que recibe la función de WithMetricsHook.
+const (
+	MetricOK           = "ok"
+	MetricNotFound     = "not_found"
+	MetricExecuteError = "execute_error"
+	MetricWriteError   = "write_error"
+	MetricClientGone   = "client_gone"
+	MetricTimeout      = "timeout"
+	MetricError        = "error"
+)
+
+// MetricsHook recibe el resultado de cada renderizado: el nombre de la página
+// ya resuelto (su ruta relativa a PageTemplatesPath), lo que ha tardado, uno
+// de los estados Metric* y el error, si lo hay.
+type MetricsHook func(tmpl string, dur time.Duration, status string, err error)
+
+// WithMetricsHook llama a fn al terminar cada Template, Block, Fragments,
+// TemplateWithLayout y Text, tanto si la página se ha escrito como si no se
+// ha encontrado, no se ha podido procesar o ha fallado al ejecutarse. Se llama
+// sin mantener ningún bloqueo de la caché, así que puede tardar lo que
+// necesite, aunque lo hará dentro de la petición.
+//
+// Ejemplo:
+//
+//	gorender.WithMetricsHook(func(tmpl string, dur time.Duration, status string, err error) {
+//		renderDuration.WithLabelValues(tmpl, status).Observe(dur.Seconds())
+//	})
+func WithMetricsHook(fn MetricsHook) OptionFunc {
+	return func(re *Render) {
+		re.metricsHook = fn
+	}
+}
+
+// observe llama a la función de WithMetricsHook con el resultado de un
+// renderizado de tmpl que empezó en start.
+func (re *Render) observe(start time.Time, tmpl string, td *TemplateData, err error) {
+	if re.metricsHook == nil {
+		return
+	}
+
+	if td != nil && td.template != "" {
+		tmpl = td.template
+	}
+
+	re.metricsHook(tmpl, time.Since(start), metricStatus(err), err)
+}
+
+// metricStatus clasifica err en uno de los estados Metric*.
+func metricStatus(err error) string {
+	switch {
+	case err == nil:
+		return MetricOK
+	case errors.Is(err, ErrClientGone):
+		return MetricClientGone
+	case errors.Is(err, ErrRenderTimeout):
+		return MetricTimeout
+	case errors.Is(err, ErrTemplateNotFound):
+		return MetricNotFound
+	case errors.Is(err, ErrExecute):
+		return MetricExecuteError
+	case errors.Is(err, ErrWrite):
+		return MetricWriteError
+	default:
+		return MetricError
+	}
+}
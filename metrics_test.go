@@ -0,0 +1,113 @@
+package gorender
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestMetricsHook(t *testing.T) {
+	type call struct {
+		tmpl   string
+		status string
+		err    bool
+	}
+	var calls []call
+	var re *Render
+	re = newTestRender(t, map[string]string{
+		"pages/blog/post.html": `{{ .Data.title }}`,
+		"pages/broken.html":    `{{ .Data.title.Missing }}`,
+	}, WithMetricsHook(func(tmpl string, dur time.Duration, status string, err error) {
+		// La caché no puede estar bloqueada mientras se llama.
+		if _, ok := re.TemplateCache.Get("post.html"); !ok {
+			t.Error("post.html missing from the cache inside the hook")
+		}
+		if err := re.Reload(); err != nil {
+			t.Errorf("Reload inside the hook: %v", err)
+		}
+		calls = append(calls, call{tmpl, status, err != nil})
+	}))
+
+	td := func() *TemplateData { return &TemplateData{Data: map[string]interface{}{"title": "hola"}} }
+	for _, page := range []string{"post.html", "missing.html", "broken.html"} {
+		_ = re.Template(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), page, td())
+	}
+
+	want := []call{
+		{"blog/post.html", MetricOK, false},
+		{"missing.html", MetricNotFound, true},
+		{"broken.html", MetricExecuteError, true},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("hook called %d times, want %d: %v", len(calls), len(want), calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("call %d = %+v, want %+v", i, calls[i], want[i])
+		}
+	}
+}
+
+func TestMetricStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, MetricOK},
+		{fmt.Errorf("x: %w", ErrTemplateNotFound), MetricNotFound},
+		{fmt.Errorf("x: %w", ErrExecute), MetricExecuteError},
+		{fmt.Errorf("x: %w", ErrWrite), MetricWriteError},
+		{fmt.Errorf("x: %w", ErrClientGone), MetricClientGone},
+		{fmt.Errorf("x: %w", ErrRenderTimeout), MetricTimeout},
+		{errors.New("x"), MetricError},
+	}
+	for _, tt := range tests {
+		if got := metricStatus(tt.err); got != tt.want {
+			t.Errorf("metricStatus(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+// Este ejemplo publica con expvar, en /debug/vars, cuántas veces se renderiza
+// cada página y cuántas fallan, agrupadas por estado.
+func ExampleWithMetricsHook() {
+	renders := expvar.NewMap("example_renders")
+	failures := expvar.NewMap("example_render_errors")
+
+	re, err := NewE(
+		WithFS(fstest.MapFS{
+			"pages/index.html": &fstest.MapFile{Data: []byte(`hola`)},
+			"shared":           &fstest.MapFile{Mode: fs.ModeDir},
+		}),
+		WithTemplatesPath("shared"),
+		WithPageTemplatesPath("pages"),
+		WithCache(true),
+		WithCSRFTokenFunc(nil),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithMetricsHook(func(tmpl string, dur time.Duration, status string, err error) {
+			renders.Add(tmpl, 1)
+			if err != nil {
+				failures.Add(status, 1)
+			}
+		}),
+	)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	for _, page := range []string{"index.html", "index.html", "missing.html"} {
+		_ = re.Template(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), page, nil)
+	}
+
+	fmt.Println(renders.Get("index.html"), failures.Get(MetricNotFound))
+	// Output: 2 1
+}
@@ -0,0 +1,125 @@
+package gorender
+
+import (
+	"bytes"
+)
+
+// rawElements son los elementos cuyo contenido se copia sin tocar.
+var rawElements = [][]byte{[]byte("pre"), []byte("textarea"), []byte("script"), []byte("style")}
+
+// WithMinifyHTML reduce cada secuencia de espacios, tabuladores y saltos de
+// línea del texto a un solo espacio antes de escribir la respuesta. Las
+// etiquetas, los comentarios y el contenido de <pre>, <textarea>, <script> y
+// <style> se dejan igual. Se aplica después de las funciones de
+// WithPostRender y antes del ETag y la compresión, y se puede evitar en una
+// página con TemplateData.SkipMinify.
+func WithMinifyHTML(enabled bool) OptionFunc {
+	return func(re *Render) {
+		re.minify = enabled
+	}
+}
+
+// minifyHTML devuelve body sin los espacios sobrantes.
+func minifyHTML(body []byte) []byte {
+	out := make([]byte, 0, len(body))
+
+	for i := 0; i < len(body); {
+		c := body[i]
+
+		switch {
+		case isHTMLSpace(c):
+			j := i
+			for j < len(body) && isHTMLSpace(body[j]) {
+				j++
+			}
+			out = append(out, ' ')
+			i = j
+
+		case c == '<' && bytes.HasPrefix(body[i:], []byte("<!--")):
+			end := bytes.Index(body[i+4:], []byte("-->"))
+			if end < 0 {
+				return append(out, body[i:]...)
+			}
+			end += i + 4 + 3
+			out = append(out, body[i:end]...)
+			i = end
+
+		case c == '<':
+			end := tagEnd(body, i)
+			if end < 0 {
+				return append(out, body[i:]...)
+			}
+			out = append(out, body[i:end]...)
+
+			if name := rawElement(body[i+1 : end]); name != nil {
+				closing := indexCloseTag(body[end:], name)
+				if closing < 0 {
+					return append(out, body[end:]...)
+				}
+				out = append(out, body[end:end+closing]...)
+				end += closing
+			}
+			i = end
+
+		default:
+			out = append(out, c)
+			i++
+		}
+	}
+
+	return out
+}
+
+// tagEnd devuelve la posición siguiente al '>' de la etiqueta que empieza en
+// start, teniendo en cuenta los atributos entre comillas.
+func tagEnd(body []byte, start int) int {
+	var quote byte
+	for i := start + 1; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return i + 1
+		}
+	}
+
+	return -1
+}
+
+// rawElement devuelve el nombre del elemento si tag, sin el '<' inicial, abre
+// alguno de rawElements.
+func rawElement(tag []byte) []byte {
+	for _, name := range rawElements {
+		if len(tag) <= len(name) || !bytes.EqualFold(tag[:len(name)], name) {
+			continue
+		}
+		next := tag[len(name)]
+		if next == '>' || next == '/' || isHTMLSpace(next) {
+			return name
+		}
+	}
+
+	return nil
+}
+
+// indexCloseTag busca la etiqueta de cierre de name en body sin distinguir
+// mayúsculas.
+func indexCloseTag(body, name []byte) int {
+	closing := append([]byte("</"), name...)
+	for i := 0; i+len(closing) <= len(body); i++ {
+		if bytes.EqualFold(body[i:i+len(closing)], closing) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,72 @@
+package gorender
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMinifyHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"collapse", "<ul>\n\t<li>a</li>\n\t<li>b</li>\n</ul>\n", "<ul> <li>a</li> <li>b</li> </ul> "},
+		{"text", "hola    \n  mundo", "hola mundo"},
+		{"pre", "<pre>\n  a\n    b\n</pre>  x", "<pre>\n  a\n    b\n</pre> x"},
+		{"textarea", "<textarea name=\"t\">  a\n  b</textarea>", "<textarea name=\"t\">  a\n  b</textarea>"},
+		{"script", "<script>\n  if (a  <  b) {}\n</script>", "<script>\n  if (a  <  b) {}\n</script>"},
+		{"style", "<style>\n a  { b: c }\n</style>", "<style>\n a  { b: c }\n</style>"},
+		{"uppercase raw element", "<PRE>  a  </PRE>", "<PRE>  a  </PRE>"},
+		{"attributes", "<a  title=\"a  >  b\"\n href=\"/\">x</a>", "<a  title=\"a  >  b\"\n href=\"/\">x</a>"},
+		{"comment", "<!--  a\n  b -->  x", "<!--  a\n  b --> x"},
+		{"unclosed pre", "<pre>  a", "<pre>  a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(minifyHTML([]byte(tt.in))); got != tt.want {
+				t.Errorf("minifyHTML(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinifySkip(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": "<p>\n  hola\n</p>"}, WithMinifyHTML(true))
+
+	tests := []struct {
+		name string
+		td   *TemplateData
+		want string
+	}{
+		{"minified", nil, "<p> hola </p>"},
+		{"skipped", &TemplateData{SkipMinify: true}, "<p>\n  hola\n</p>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "index.html", tt.td); err != nil {
+				t.Fatalf("Template: %v", err)
+			}
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// BenchmarkMinifyHTML mide el coste de minificar una página de unos 40 KB con
+// la sangría habitual de las plantillas.
+func BenchmarkMinifyHTML(b *testing.B) {
+	row := "    <tr>\n      <td class=\"name\">Nombre</td>\n      <td>  valor  </td>\n    </tr>\n"
+	page := []byte("<html>\n  <body>\n  <table>\n" + strings.Repeat(row, 500) +
+		"  </table>\n  <script>\n    var a  =  1;\n  </script>\n  </body>\n</html>\n")
+
+	b.SetBytes(int64(len(page)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		minifyHTML(page)
+	}
+}
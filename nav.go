@@ -0,0 +1,79 @@
+package gorender
+
+import "strings"
+
+// IsActive indica si path es la ruta de la petición, sin tener en cuenta la
+// barra final ni los parámetros.
+//
+// Ejemplo:
+//
+//	<a href="/users" class="{{ .ActiveClass "/users" "active" }}">Usuarios</a>
+//	{{ range .Data.links }}<a {{ if $.IsActivePrefix .URL }}class="active"{{ end }}>{{ end }}
+func (td *TemplateData) IsActive(path string) bool {
+	return isActive(td.Request.Path, path)
+}
+
+// IsActivePrefix indica si la ruta de la petición es path o está dentro de
+// él, de modo que "/admin/users/42" está dentro de "/admin/users" pero no de
+// "/admin/use".
+func (td *TemplateData) IsActivePrefix(path string) bool {
+	return isActivePrefix(td.Request.Path, path)
+}
+
+// ActiveClass devuelve class si path es la ruta de la petición.
+func (td *TemplateData) ActiveClass(path, class string) string {
+	return activeClass(td.Request.Path, path, class)
+}
+
+// ActiveClassPrefix devuelve class si la ruta de la petición está dentro de
+// path.
+func (td *TemplateData) ActiveClassPrefix(path, class string) string {
+	return activeClassPrefix(td.Request.Path, path, class)
+}
+
+// isActive, isActivePrefix, activeClass y activeClassPrefix son las versiones
+// en función de los métodos de TemplateData, con la ruta actual como primer
+// argumento: {{ isActive .Request.Path "/users" }}.
+func isActive(current, path string) bool {
+	return cleanNavPath(current) == cleanNavPath(path)
+}
+
+func isActivePrefix(current, path string) bool {
+	current, path = cleanNavPath(current), cleanNavPath(path)
+	if path == "/" {
+		return current == "/"
+	}
+
+	return current == path || strings.HasPrefix(current, path+"/")
+}
+
+func activeClass(current, path, class string) string {
+	if isActive(current, path) {
+		return class
+	}
+	return ""
+}
+
+func activeClassPrefix(current, path, class string) string {
+	if isActivePrefix(current, path) {
+		return class
+	}
+	return ""
+}
+
+// cleanNavPath quita los parámetros, el fragmento y la barra final de p.
+func cleanNavPath(p string) string {
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+
+	p = strings.TrimRight(p, "/")
+	if p == "" {
+		return "/"
+	}
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+
+	return p
+}
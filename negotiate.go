@@ -0,0 +1,144 @@
+package gorender
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Tipos que ofrece Negotiate, en orden de preferencia cuando a la petición le
+// da igual.
+var negotiateOffers = []string{"text/html", "application/json"}
+
+// Negotiate responde a la misma ruta con la página tmpl para los navegadores y
+// con jsonPayload para los clientes de una API, según la cabecera Accept de la
+// petición. Las peticiones de HTMX (cabecera HX-Request) reciben siempre HTML.
+// El JSON se escribe con el código de estado de td, o 200 si no tiene.
+//
+// Si la petición no acepta ninguno de los dos se responde con un 406 que
+// indica los tipos disponibles y se devuelve un error que envuelve
+// ErrNotAcceptable.
+//
+// Ejemplo:
+//
+//	ren.Negotiate(w, r, "user.html", gorender.NewData().Set("user", u).Build(), u)
+func (re *Render) Negotiate(w http.ResponseWriter, r *http.Request, tmpl string, td *TemplateData, jsonPayload any) error {
+	w.Header().Add("Vary", "Accept")
+
+	offer := "text/html"
+	if r.Header.Get("HX-Request") != "true" {
+		var ok bool
+		offer, ok = NegotiateType(r, negotiateOffers...)
+		if !ok {
+			available := strings.Join(negotiateOffers, ", ")
+			http.Error(w, "Not Acceptable. Available types: "+available, http.StatusNotAcceptable)
+			return fmt.Errorf("%w: %q, available types: %s", ErrNotAcceptable, r.Header.Get("Accept"), available)
+		}
+	}
+
+	if offer == "application/json" {
+		status := http.StatusOK
+		if td != nil && td.Status != 0 {
+			status = td.Status
+		}
+		return re.JSON(w, status, jsonPayload)
+	}
+
+	return re.Template(w, r, tmpl, td)
+}
+
+// NegotiateType elige, de los tipos offered, el que prefiere la petición
+// según su cabecera Accept, teniendo en cuenta los valores q y los comodines
+// como text/* o */*. Ante la misma preferencia gana el primero de offered y,
+// sin cabecera Accept, se devuelve el primero. Si la petición no acepta
+// ninguno devuelve false.
+//
+// Ejemplo:
+//
+//	switch t, _ := gorender.NegotiateType(r, "text/csv", "application/json"); t {
+func NegotiateType(r *http.Request, offered ...string) (string, bool) {
+	accept := r.Header.Values("Accept")
+	if len(accept) == 0 {
+		if len(offered) == 0 {
+			return "", false
+		}
+		return offered[0], true
+	}
+
+	ranges := parseAccept(strings.Join(accept, ","))
+
+	best, bestQ := "", 0.0
+	for _, offer := range offered {
+		q := acceptQuality(ranges, strings.ToLower(offer))
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best, bestQ > 0
+}
+
+// acceptRange es un tipo de la cabecera Accept con su valor q.
+type acceptRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// parseAccept lee los tipos de la cabecera Accept. Los que tienen un valor q
+// no válido se ignoran.
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		typ, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(mediaType)), "/")
+		if !ok || typ == "" || subtype == "" {
+			continue
+		}
+
+		rng := acceptRange{typ: typ, subtype: subtype, q: 1}
+		valid := true
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if !strings.EqualFold(key, "q") {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || q < 0 || q > 1 {
+				valid = false
+				break
+			}
+			rng.q = q
+		}
+		if valid {
+			ranges = append(ranges, rng)
+		}
+	}
+
+	return ranges
+}
+
+// acceptQuality devuelve el valor q con el que ranges acepta offer: el del
+// tipo más concreto que coincide, de modo que "text/html;q=0" excluye HTML
+// aunque también se acepte "*/*".
+func acceptQuality(ranges []acceptRange, offer string) float64 {
+	typ, subtype, _ := strings.Cut(offer, "/")
+
+	q, specificity := 0.0, -1
+	for _, rng := range ranges {
+		s := -1
+		switch {
+		case rng.typ == typ && rng.subtype == subtype:
+			s = 2
+		case rng.typ == typ && rng.subtype == "*":
+			s = 1
+		case rng.typ == "*" && rng.subtype == "*":
+			s = 0
+		}
+		if s > specificity || s == specificity && s >= 0 && rng.q > q {
+			q, specificity = rng.q, s
+		}
+	}
+
+	return q
+}
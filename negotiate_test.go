@@ -0,0 +1,98 @@
+package gorender
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateType(t *testing.T) {
+	tests := []struct {
+		name    string
+		accept  string
+		offered []string
+		want    string
+		ok      bool
+	}{
+		{"no header", "", []string{"text/html", "application/json"}, "text/html", true},
+		{"exact", "application/json", []string{"text/html", "application/json"}, "application/json", true},
+		{"q values", "text/html;q=0.5, application/json;q=0.9", []string{"text/html", "application/json"}, "application/json", true},
+		{"tie keeps offer order", "application/json, text/html", []string{"text/html", "application/json"}, "text/html", true},
+		{"browser", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", []string{"application/json", "text/html"}, "text/html", true},
+		{"subtype wildcard", "text/*", []string{"application/json", "text/csv"}, "text/csv", true},
+		{"html excluded with wildcard", "text/html;q=0, */*", []string{"text/html", "application/json"}, "application/json", true},
+		{"specific beats wildcard", "*/*;q=0.1, application/json;q=0", []string{"application/json"}, "", false},
+		{"invalid q ignored", "application/json;q=2, text/html;q=0.1", []string{"application/json", "text/html"}, "text/html", true},
+		{"case insensitive", "Application/JSON", []string{"application/json"}, "application/json", true},
+		{"nothing acceptable", "image/png", []string{"text/html", "application/json"}, "", false},
+		{"no offers", "", nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			got, ok := NegotiateType(r, tt.offered...)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("NegotiateType = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/user.html": `<h1>{{ .Data.name }}</h1>`})
+	payload := map[string]string{"name": "Ana"}
+
+	tests := []struct {
+		name        string
+		accept      string
+		htmx        bool
+		status      int
+		code        int
+		contentType string
+		body        string
+	}{
+		{"browser", "text/html,*/*;q=0.8", false, 0, http.StatusOK, "text/html", "<h1>Ana</h1>"},
+		{"api", "application/json", false, http.StatusCreated, http.StatusCreated, "application/json", `{"name":"Ana"}`},
+		{"html excluded", "text/html;q=0, */*", false, 0, http.StatusOK, "application/json", `{"name":"Ana"}`},
+		{"htmx forces html", "application/json", true, 0, http.StatusOK, "text/html", "<h1>Ana</h1>"},
+		{"not acceptable", "image/png", false, 0, http.StatusNotAcceptable, "text/plain", "text/html, application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Accept", tt.accept)
+			if tt.htmx {
+				r.Header.Set("HX-Request", "true")
+			}
+			rec := httptest.NewRecorder()
+			td := &TemplateData{Data: map[string]interface{}{"name": "Ana"}, Status: tt.status}
+
+			err := re.Negotiate(rec, r, "user.html", td, payload)
+			if tt.code == http.StatusNotAcceptable {
+				if !errors.Is(err, ErrNotAcceptable) || !strings.Contains(err.Error(), "image/png") {
+					t.Errorf("Negotiate error = %v, want ErrNotAcceptable naming the Accept header", err)
+				}
+			} else if err != nil {
+				t.Fatalf("Negotiate: %v", err)
+			}
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d", rec.Code, tt.code)
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.contentType) {
+				t.Errorf("Content-Type = %q, want %s", got, tt.contentType)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept" {
+				t.Errorf("Vary = %q, want Accept", got)
+			}
+			if got := rec.Body.String(); !strings.Contains(got, tt.body) {
+				t.Errorf("body = %q, want %q", got, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package gorender
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// WithOverridePath indica un directorio en disco con plantillas que sustituyen
+// a las originales, pensado para personalizar la aplicación para cada cliente.
+// El directorio replica la estructura de TemplatesPath: cualquier archivo con
+// la misma ruta relativa sustituye al original y los que sólo existen en el
+// directorio de sustitución se añaden. Si el directorio no existe se ignora.
+func WithOverridePath(overridePath string) OptionFunc {
+	return func(re *Render) {
+		re.overridePath = overridePath
+	}
+}
+
+// templateParser es lo que tienen en común html/template y text/template para
+// procesar archivos.
+type templateParser[T any] interface {
+	Name() string
+	New(name string) T
+	Parse(text string) (T, error)
+}
+
+// parseFiles procesa los archivos sobre t igual que ParseFiles: el archivo
+// cuyo nombre coincide con el de t se convierte en su contenido y el resto se
+// añaden como plantillas asociadas. Cada archivo se lee del directorio de
+// sustitución si existe allí, o de su origen en caso contrario.
+func parseFiles[T templateParser[T]](re *Render, t T, files ...string) (T, error) {
+	for _, file := range files {
+		b, err := re.readTemplate(file)
+		if err != nil {
+			return t, err
+		}
+
+		name := path.Base(filepath.ToSlash(file))
+		tmpl := t
+		if name != t.Name() {
+			tmpl = t.New(name)
+		}
+
+		_, err = tmpl.Parse(string(b))
+		if err != nil {
+			return t, err
+		}
+	}
+
+	return t, nil
+}
+
+// readTemplate lee el contenido de una plantilla.
+func (re *Render) readTemplate(file string) ([]byte, error) {
+	if override, ok := re.overrideFile(file); ok {
+		return os.ReadFile(override)
+	}
+
+	if re.fs != nil {
+		return fs.ReadFile(re.fs, file)
+	}
+
+	return os.ReadFile(file)
+}
+
+// templateModTime devuelve la fecha de modificación de file, o la de su
+// sustituto si lo hay. Si no se puede consultar devuelve la fecha cero.
+func (re *Render) templateModTime(file string) time.Time {
+	var info fs.FileInfo
+	var err error
+	if override, ok := re.overrideFile(file); ok {
+		info, err = os.Stat(override)
+	} else if re.fs != nil {
+		info, err = fs.Stat(re.fs, file)
+	} else {
+		info, err = os.Stat(file)
+	}
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
+
+// Orígenes de las plantillas que indica Stats.Sources.
+const (
+	// SourceOverride es una plantilla leída del directorio de WithOverridePath.
+	SourceOverride = "override"
+	// SourceFS es una plantilla leída del sistema de archivos de WithFS.
+	SourceFS = "fs"
+	// SourceDisk es una plantilla leída del disco.
+	SourceDisk = "disk"
+	// SourceMemory es una plantilla registrada con AddTemplate o AddPartial.
+	SourceMemory = "memory"
+)
+
+// templateSource indica de dónde se lee file: del directorio de sustitución,
+// del sistema de archivos de WithFS o del disco.
+func (re *Render) templateSource(file string) string {
+	if _, ok := re.overrideFile(file); ok {
+		return SourceOverride
+	}
+	if re.fs != nil {
+		return SourceFS
+	}
+
+	return SourceDisk
+}
+
+// recordSources guarda en set el origen de cada archivo de files.
+func (re *Render) recordSources(set *templateSet, files ...string) {
+	for _, file := range files {
+		set.sources[file] = re.templateSource(file)
+	}
+}
+
+// overrideFile devuelve la ruta del archivo que sustituye a file, si existe.
+func (re *Render) overrideFile(file string) (string, bool) {
+	if re.overridePath == "" {
+		return "", false
+	}
+
+	rel, ok := re.relativeToTemplates(file)
+	if !ok {
+		return "", false
+	}
+
+	override := filepath.Join(re.overridePath, filepath.FromSlash(rel))
+	info, err := os.Stat(override)
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+
+	return override, true
+}
+
+// relativeToTemplates devuelve la ruta de file relativa a TemplatesPath, con
+// barras normales. Si PageTemplatesPath no está dentro de TemplatesPath, sus
+// páginas se consideran dentro de un directorio con su mismo nombre.
+func (re *Render) relativeToTemplates(file string) (string, bool) {
+	rel, err := filepath.Rel(re.root(re.TemplatesPath), file)
+	if err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel), true
+	}
+
+	pages := re.root(re.PageTemplatesPath)
+	rel, err = filepath.Rel(pages, file)
+	if err == nil && !strings.HasPrefix(rel, "..") {
+		return path.Join(filepath.Base(pages), filepath.ToSlash(rel)), true
+	}
+
+	return "", false
+}
+
+// overrideDir devuelve el directorio de sustitución equivalente a root.
+func (re *Render) overrideDir(root string) (string, bool) {
+	if re.overridePath == "" {
+		return "", false
+	}
+
+	rel, ok := re.relativeToTemplates(re.root(root))
+	if !ok {
+		return "", false
+	}
+
+	return filepath.Join(re.overridePath, filepath.FromSlash(rel)), true
+}
+
+// overrideOnlyFiles devuelve, como rutas dentro de root, las plantillas con
+// alguna de las extensiones indicadas que sólo existen en el directorio de
+// sustitución.
+func (re *Render) overrideOnlyFiles(root string, exts []string, found []string) []string {
+	dir, ok := re.overrideDir(root)
+	if !ok {
+		return nil
+	}
+
+	existing := make(map[string]bool, len(found))
+	for _, file := range found {
+		existing[file] = true
+	}
+
+	var added []string
+	_ = re.walkDisk(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if skip, err := re.skipEntry(dir, p, d); skip {
+			return err
+		}
+		if d.IsDir() || !hasExtension(p, exts) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return nil
+		}
+
+		var file string
+		if re.fs != nil {
+			file = path.Join(re.root(root), filepath.ToSlash(rel))
+		} else {
+			file = filepath.Join(root, rel)
+		}
+
+		if !existing[file] {
+			added = append(added, file)
+		}
+		return nil
+	})
+
+	return added
+}
+
+// logOverrides registra qué plantillas se leen del directorio de sustitución.
+// Cada una se registra sólo la primera vez que se sustituye, no en cada
+// reconstrucción, que con la caché deshabilitada es cada petición.
+func (re *Render) logOverrides(files []string) {
+	re.overridesMu.Lock()
+	defer re.overridesMu.Unlock()
+
+	for _, file := range files {
+		override, ok := re.overrideFile(file)
+		if !ok {
+			delete(re.overrides, file)
+			continue
+		}
+		if re.overrides[file] == override {
+			continue
+		}
+
+		if re.overrides == nil {
+			re.overrides = map[string]string{}
+		}
+		re.overrides[file] = override
+		re.log().Info("template overridden", "template", file, "override", override)
+	}
+}
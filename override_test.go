@@ -0,0 +1,58 @@
+package gorender
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOverridePath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "pages"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"pages/home.html": `custom {{template "extra" .}}`,
+		"only.html":       `{{define "extra"}}added{{end}}`,
+	} {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var logs bytes.Buffer
+	files := map[string]string{
+		"shared/pages/home.html":  `default`,
+		"shared/pages/about.html": `about`,
+	}
+	re := newTestRender(t, files,
+		WithPageTemplatesPath("shared/pages"),
+		WithCache(false),
+		WithOverridePath(dir),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+	)
+
+	for range 3 {
+		got, err := re.TemplateString("home.html", nil)
+		if err != nil {
+			t.Fatalf("TemplateString: %v", err)
+		}
+		if got != "custom added" {
+			t.Fatalf("home.html = %q, want the override", got)
+		}
+	}
+	if got, err := re.TemplateString("about.html", nil); err != nil || got != "about" {
+		t.Fatalf("about.html = %q, %v, want the default", got, err)
+	}
+
+	if n := strings.Count(logs.String(), "template overridden"); n != 2 {
+		t.Errorf("logged %d overrides in 4 renders, want 2, one per file:\n%s", n, logs.String())
+	}
+}
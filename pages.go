@@ -38,6 +38,10 @@ func NewPages(totalElements, showElements, currentPage int) Pages {
 	if p.currentPage > p.TotalPages() {
 		p.currentPage = p.TotalPages()
 	}
+	// Sin elementos sigue habiendo una página actual, vacía.
+	if p.currentPage < 1 {
+		p.currentPage = 1
+	}
 
 	return p
 }
@@ -57,9 +61,15 @@ func (p *Pages) IsFirst() bool {
 	return p.currentPage == 1
 }
 
-// IsLast indica si la página actual es la última.
+// IsLast indica si la página actual es la última. Sin elementos la única
+// página es a la vez la primera y la última.
 func (p *Pages) IsLast() bool {
-	return p.currentPage == p.TotalPages()
+	return p.currentPage >= p.TotalPages()
+}
+
+// CurrentPage devuelve el número de la página actual.
+func (p *Pages) CurrentPage() int {
+	return p.currentPage
 }
 
 // HasPrevious indica si hay una página anterior.
@@ -82,6 +92,60 @@ func (p *Pages) Next() int {
 	return p.currentPage + 1
 }
 
+// HasPrev es un alias de HasPrevious.
+func (p *Pages) HasPrev() bool {
+	return p.HasPrevious()
+}
+
+// PrevPage devuelve el número de la página anterior sin bajar de la primera.
+func (p *Pages) PrevPage() int {
+	if p.currentPage <= 1 {
+		return 1
+	}
+	return p.currentPage - 1
+}
+
+// NextPage devuelve el número de la página siguiente sin pasar de la última.
+func (p *Pages) NextPage() int {
+	if p.currentPage >= p.TotalPages() {
+		return p.currentPage
+	}
+	return p.currentPage + 1
+}
+
+// Window devuelve hasta size números de página centrados en la actual. Cerca
+// de los extremos la ventana se desplaza para seguir mostrando size páginas,
+// y si hay menos páginas que size se muestran todas.
+//
+// Ejemplo:
+//
+//	{{ range .Page.Window 5 }}
+//	 <a href="?page={{ . }}"{{ if eq . $.Page.CurrentPage }} class="active"{{ end }}>{{ . }}</a>
+//	{{ end }}
+func (p *Pages) Window(size int) []int {
+	total := p.TotalPages()
+	if size <= 0 || total == 0 {
+		return nil
+	}
+	if size > total {
+		size = total
+	}
+
+	start := p.currentPage - (size-1)/2
+	if start < 1 {
+		start = 1
+	}
+	if start+size-1 > total {
+		start = total - size + 1
+	}
+
+	window := make([]int, size)
+	for i := range window {
+		window[i] = start + i
+	}
+	return window
+}
+
 func (p *Page) NumberOfPage() int {
 	return p.number
 }
@@ -0,0 +1,84 @@
+package gorender
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPagesWindow(t *testing.T) {
+	tests := []struct {
+		name          string
+		total, per    int
+		current, size int
+		want          []int
+	}{
+		{"first page", 100, 10, 1, 5, []int{1, 2, 3, 4, 5}},
+		{"second page", 100, 10, 2, 5, []int{1, 2, 3, 4, 5}},
+		{"middle", 100, 10, 5, 5, []int{3, 4, 5, 6, 7}},
+		{"even size", 100, 10, 5, 4, []int{4, 5, 6, 7}},
+		{"last page", 100, 10, 10, 5, []int{6, 7, 8, 9, 10}},
+		{"past the last page", 100, 10, 50, 5, []int{6, 7, 8, 9, 10}},
+		{"window larger than total", 25, 10, 2, 5, []int{1, 2, 3}},
+		{"single page", 3, 10, 1, 5, []int{1}},
+		{"no elements", 0, 10, 1, 5, nil},
+		{"zero size", 100, 10, 5, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPages(tt.total, tt.per, tt.current)
+			if got := p.Window(tt.size); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Window(%d) = %v, want %v", tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPagesWindowHasNoGaps(t *testing.T) {
+	for current := 1; current <= 12; current++ {
+		p := NewPages(120, 10, current)
+		window := p.Window(5)
+		if len(window) != 5 {
+			t.Fatalf("page %d: Window(5) = %v, want 5 pages", current, window)
+		}
+		for i := 1; i < len(window); i++ {
+			if window[i] != window[i-1]+1 {
+				t.Errorf("page %d: Window(5) = %v has a gap", current, window)
+			}
+		}
+		if current < window[0] || current > window[len(window)-1] {
+			t.Errorf("page %d: Window(5) = %v does not include the current page", current, window)
+		}
+	}
+}
+
+func TestPagesPrevNext(t *testing.T) {
+	tests := []struct {
+		name       string
+		total      int
+		current    int
+		prev, next int
+		first      bool
+		last       bool
+	}{
+		{"first page", 100, 1, 1, 2, true, false},
+		{"middle", 100, 5, 4, 6, false, false},
+		{"last page", 100, 10, 9, 10, false, true},
+		{"past the last page", 100, 11, 9, 10, false, true},
+		{"no elements", 0, 1, 1, 1, true, true},
+		{"zero page", 100, 0, 1, 2, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPages(tt.total, 10, tt.current)
+			if got := p.PrevPage(); got != tt.prev {
+				t.Errorf("PrevPage() = %d, want %d", got, tt.prev)
+			}
+			if got := p.NextPage(); got != tt.next {
+				t.Errorf("NextPage() = %d, want %d", got, tt.next)
+			}
+			if p.IsFirst() != tt.first || p.IsLast() != tt.last {
+				t.Errorf("IsFirst, IsLast = %v, %v, want %v, %v", p.IsFirst(), p.IsLast(), tt.first, tt.last)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package gorender
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PluralRule devuelve la forma plural que le corresponde a n: "zero", "one",
+// "two", "few", "many" u "other", como en las reglas de Unicode CLDR.
+type PluralRule func(n float64) string
+
+// pluralRules son las reglas de cada lengua. Las lenguas que no están usan
+// pluralOne, que sirve para la mayoría de las europeas.
+var pluralRules = map[string]PluralRule{
+	"en": pluralOne,
+	"es": pluralOne,
+	"de": pluralOne,
+	"it": pluralOne,
+	"nl": pluralOne,
+	"pt": pluralOne,
+	"ca": pluralOne,
+	"fr": pluralZeroOne,
+	"ru": pluralSlavic,
+	"uk": pluralSlavic,
+	"pl": pluralPolish,
+	"ja": pluralNone,
+	"zh": pluralNone,
+	"ko": pluralNone,
+}
+
+// RegisterPluralRule añade o sustituye la regla de una lengua, por ejemplo
+// "cs" o "ar". Se debe llamar antes de renderizar, normalmente en un init.
+func RegisterPluralRule(lang string, rule PluralRule) {
+	pluralRules[normalizeLocale(lang)] = rule
+}
+
+func pluralOne(n float64) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+func pluralZeroOne(n float64) string {
+	if n >= 0 && n < 2 {
+		return "one"
+	}
+	return "other"
+}
+
+func pluralNone(float64) string {
+	return "other"
+}
+
+func pluralSlavic(n float64) string {
+	if n != math.Trunc(n) {
+		return "other"
+	}
+	i := int64(math.Abs(n))
+	switch {
+	case i%10 == 1 && i%100 != 11:
+		return "one"
+	case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+		return "few"
+	default:
+		return "many"
+	}
+}
+
+func pluralPolish(n float64) string {
+	if n != math.Trunc(n) {
+		return "other"
+	}
+	i := int64(math.Abs(n))
+	switch {
+	case i == 1:
+		return "one"
+	case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+		return "few"
+	default:
+		return "many"
+	}
+}
+
+// pluralRule devuelve la regla del idioma lang o de su lengua.
+func pluralRule(lang string) PluralRule {
+	lang = normalizeLocale(lang)
+	if rule, ok := pluralRules[lang]; ok {
+		return rule
+	}
+
+	base, _, _ := strings.Cut(lang, "_")
+	if rule, ok := pluralRules[base]; ok {
+		return rule
+	}
+
+	return pluralOne
+}
+
+// plural traduce la forma de key que corresponde a count. Las formas se
+// guardan como subclaves, "results_count.one" y "results_count.other", y si
+// falta la forma elegida se usa "other". En el mensaje se sustituye {count}
+// por count y {nombre} por cada par nombre-valor de args. Si args tiene un
+// número impar de elementos el primero es el idioma.
+//
+// Ejemplo:
+//
+//	{"results_count": {"one": "{count} resultado para {query}", "other": "{count} resultados para {query}"}}
+//
+//	{{ plural "results_count" .Data.count "query" .Data.q }}
+func (re *Render) plural(key string, count interface{}, args ...interface{}) (string, error) {
+	n, ok := toFloat(count)
+	if !ok {
+		return "", fmt.Errorf("plural: count for %q must be a number, got %T", key, count)
+	}
+
+	var lang string
+	if len(args)%2 == 1 {
+		lang = fmt.Sprint(args[0])
+		args = args[1:]
+	}
+	lang = re.resolveLocale(lang)
+
+	message, ok := re.translation(lang, key+"."+pluralRule(lang)(n))
+	if !ok {
+		message, ok = re.translation(lang, key+".other")
+	}
+	if !ok {
+		return key, nil
+	}
+
+	replacements := []string{"{count}", strconv.FormatFloat(n, 'f', -1, 64)}
+	for i := 0; i < len(args); i += 2 {
+		replacements = append(replacements, "{"+fmt.Sprint(args[i])+"}", fmt.Sprint(args[i+1]))
+	}
+
+	return strings.NewReplacer(replacements...).Replace(message), nil
+}
+
+// toFloat convierte cualquier tipo numérico a float64.
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
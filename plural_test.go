@@ -0,0 +1,91 @@
+package gorender
+
+import (
+	"io"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPlural(t *testing.T) {
+	catalogs := fstest.MapFS{
+		"en.json": &fstest.MapFile{Data: []byte(`{"results": {"one": "{count} result for {q}", "other": "{count} results for {q}"}}`)},
+		"es.json": &fstest.MapFile{Data: []byte(`{"results": {"one": "{count} resultado para {q}", "other": "{count} resultados para {q}"}}`)},
+	}
+	re := newTestRender(t, nil, WithLocales("es", "en"), WithTranslationsFS(catalogs))
+
+	tests := []struct {
+		locale string
+		count  interface{}
+		want   string
+	}{
+		{"en", 1, "1 result for go"},
+		{"en", 3, "3 results for go"},
+		{"en", 0, "0 results for go"},
+		{"es", int64(1), "1 resultado para go"},
+		{"es", 2.5, "2.5 resultados para go"},
+	}
+	for _, tt := range tests {
+		got, err := re.plural("results", tt.count, tt.locale, "q", "go")
+		if err != nil {
+			t.Fatalf("plural(%v, %s): %v", tt.count, tt.locale, err)
+		}
+		if got != tt.want {
+			t.Errorf("plural(%v, %s) = %q, want %q", tt.count, tt.locale, got, tt.want)
+		}
+	}
+
+	if _, err := re.plural("results", "three"); err == nil {
+		t.Error("plural with a non numeric count returned no error")
+	}
+}
+
+func TestPluralRules(t *testing.T) {
+	tests := []struct {
+		lang string
+		n    float64
+		want string
+	}{
+		{"es_ES", 1, "one"},
+		{"es_ES", 0, "other"},
+		{"fr", 0, "one"},
+		{"fr", 2, "other"},
+		{"ru", 21, "one"},
+		{"ru", 3, "few"},
+		{"ru", 11, "many"},
+		{"pl", 22, "few"},
+		{"pl", 25, "many"},
+		{"ja", 1, "other"},
+		{"xx", 1, "one"},
+	}
+	for _, tt := range tests {
+		if got := pluralRule(tt.lang)(tt.n); got != tt.want {
+			t.Errorf("pluralRule(%s)(%v) = %q, want %q", tt.lang, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestPluralWithoutCatalogs(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/count.html": `{{plural "results" 3}}`})
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	got, renderErr := re.TemplateString("count.html", nil)
+	os.Stdout = stdout
+	w.Close()
+	printed, _ := io.ReadAll(r)
+
+	if renderErr != nil {
+		t.Fatalf("TemplateString: %v", renderErr)
+	}
+	if got != "results" {
+		t.Errorf("output = %q, want the key", got)
+	}
+	if len(printed) != 0 {
+		t.Errorf("rendering printed %q to stdout", printed)
+	}
+}
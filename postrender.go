@@ -0,0 +1,60 @@
+package gorender
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// PostRenderFunc transforma el resultado de una plantilla antes de escribirlo
+// en la respuesta, por ejemplo para minificarlo o para inyectar un script.
+type PostRenderFunc func(r *http.Request, tmpl string, body []byte) ([]byte, error)
+
+// WithPostRender registra funciones que transforman el HTML ya generado antes
+// de escribirlo. Se aplican en el orden en que se registran, cada una sobre el
+// resultado de la anterior. Si alguna devuelve un error no se escribe nada y
+// el error se devuelve al llamador. En Generate, que no tiene petición, r es
+// nil.
+func WithPostRender(fns ...PostRenderFunc) OptionFunc {
+	return func(re *Render) {
+		re.postRenderFuncs = append(re.postRenderFuncs, fns...)
+	}
+}
+
+// respond aplica las transformaciones posteriores al renderizado sobre buf,
+// después el script de WithLiveReload y la minificación de WithMinifyHTML, y
+// escribe el resultado en la respuesta.
+func (re *Render) respond(w http.ResponseWriter, r *http.Request, tmpl string, buf *bytes.Buffer, td *TemplateData) error {
+	err := re.postRender(r, tmpl, buf)
+	if err != nil {
+		putBuffer(buf)
+		return err
+	}
+
+	re.injectLiveReload(w, buf, td)
+	if re.minify && !td.SkipMinify {
+		body := minifyHTML(buf.Bytes())
+		buf.Reset()
+		buf.Write(body)
+	}
+
+	return re.write(w, r, tmpl, buf, td, re.contentType)
+}
+
+// postRender aplica las funciones de WithPostRender sobre el contenido de buf.
+func (re *Render) postRender(r *http.Request, tmpl string, buf *bytes.Buffer) error {
+	for _, fn := range re.postRenderFuncs {
+		body, err := fn(r, tmpl, buf.Bytes())
+		if err != nil {
+			re.log().Error("error in post render hook:", logAttrs(r, tmpl, "error", err)...)
+			return err
+		}
+
+		// body puede compartir memoria con buf, así que se copia antes de
+		// vaciarlo.
+		body = bytes.Clone(body)
+		buf.Reset()
+		buf.Write(body)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package gorender
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Problem es un error en formato application/problem+json (RFC 7807) para
+// las respuestas de una API.
+type Problem struct {
+	// Type es una dirección que identifica el tipo de problema. Si está vacío
+	// se entiende "about:blank".
+	Type string
+	// Title es un resumen del problema. Problem usa el texto estándar del
+	// código si está vacío.
+	Title  string
+	Status int
+	Detail string
+	// Instance identifica esta aparición concreta del problema, normalmente
+	// la ruta de la petición.
+	Instance string
+	// Extensions son miembros adicionales, como los errores de cada campo.
+	// Se escriben al mismo nivel que el resto y no sustituyen a los
+	// estándar.
+	Extensions map[string]any
+}
+
+// MarshalJSON escribe los miembros estándar y las extensiones en un único
+// objeto, omitiendo los vacíos.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+
+	members := []struct {
+		key   string
+		value any
+		empty bool
+	}{
+		{"type", p.Type, p.Type == ""},
+		{"title", p.Title, p.Title == ""},
+		{"status", p.Status, p.Status == 0},
+		{"detail", p.Detail, p.Detail == ""},
+		{"instance", p.Instance, p.Instance == ""},
+	}
+	for _, member := range members {
+		delete(m, member.key)
+		if !member.empty {
+			m[member.key] = member.value
+		}
+	}
+
+	return json.Marshal(m)
+}
+
+// WithProblemErrors hace que Error responda con Problem en lugar de con la
+// página de error a las peticiones que prefieren JSON según su cabecera
+// Accept, para que una API y las páginas compartan los mismos manejadores.
+func WithProblemErrors(enabled bool) OptionFunc {
+	return func(re *Render) {
+		re.problemErrors = enabled
+	}
+}
+
+// Problem escribe p como application/problem+json con su código de estado, o
+// un 500 si no tiene. Si Title está vacío se usa el texto estándar del
+// código.
+//
+// Ejemplo:
+//
+//	ren.Problem(w, gorender.Problem{
+//		Status:     http.StatusUnprocessableEntity,
+//		Detail:     "El formulario tiene errores.",
+//		Extensions: map[string]any{"errors": td.FormData.Errors},
+//	})
+func (re *Render) Problem(w http.ResponseWriter, p Problem) error {
+	if p.Status == 0 {
+		p.Status = http.StatusInternalServerError
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	return re.writeJSON(w, p.Status, "application/problem+json", p)
+}
+
+// wantsProblem indica si Error debe responder a r con Problem.
+func (re *Render) wantsProblem(r *http.Request) bool {
+	if !re.problemErrors || r == nil || r.Header.Get("HX-Request") == "true" {
+		return false
+	}
+
+	offer, _ := NegotiateType(r, "text/html", "application/problem+json", "application/json")
+	return offer == "application/problem+json" || offer == "application/json"
+}
@@ -0,0 +1,122 @@
+package gorender
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestProblemMarshalJSON(t *testing.T) {
+	p := Problem{
+		Type:   "https://example.com/probs/validation",
+		Status: http.StatusUnprocessableEntity,
+		Detail: "El formulario tiene errores.",
+		Extensions: map[string]any{
+			"errors": map[string]string{"email": "obligatorio"},
+			"status": 200,
+			"title":  "extension title",
+		},
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	want := map[string]any{
+		"type":   "https://example.com/probs/validation",
+		"status": float64(http.StatusUnprocessableEntity),
+		"detail": "El formulario tiene errores.",
+		"errors": map[string]any{"email": "obligatorio"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("problem = %s, want the extensions at the top level without the standard members they shadow", data)
+	}
+}
+
+func TestRenderProblem(t *testing.T) {
+	re := newTestRender(t, nil)
+
+	rec := httptest.NewRecorder()
+	if err := re.Problem(rec, Problem{Status: http.StatusNotFound, Instance: "/users/7"}); err != nil {
+		t.Fatalf("Problem: %v", err)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", got)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got["title"] != "Not Found" || got["instance"] != "/users/7" {
+		t.Errorf("problem = %v, want the default title and the instance", got)
+	}
+
+	rec = httptest.NewRecorder()
+	_ = re.Problem(rec, Problem{Title: "Algo ha fallado"})
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status without Status = %d, want 500", rec.Code)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || got["title"] != "Algo ha fallado" {
+		t.Errorf("problem = %v, %v, want the given title kept", got, err)
+	}
+}
+
+func TestErrorProblem(t *testing.T) {
+	files := map[string]string{"pages/404.html": `pagina {{ .Error.Status }}`}
+	errorPages := WithErrorTemplates(map[int]string{http.StatusNotFound: "404.html"})
+
+	tests := []struct {
+		name        string
+		opts        []OptionFunc
+		accept      string
+		htmx        bool
+		contentType string
+	}{
+		{"json client", []OptionFunc{WithProblemErrors(true)}, "application/json", false, "application/problem+json"},
+		{"problem client", []OptionFunc{WithProblemErrors(true)}, "application/problem+json", false, "application/problem+json"},
+		{"browser", []OptionFunc{WithProblemErrors(true)}, "text/html,*/*;q=0.8", false, "text/html; charset=utf-8"},
+		{"htmx", []OptionFunc{WithProblemErrors(true)}, "application/json", true, "text/html; charset=utf-8"},
+		{"disabled", nil, "application/json", false, "text/html; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := newTestRender(t, files, append(tt.opts, errorPages)...)
+			r := httptest.NewRequest("GET", "/users/7", nil)
+			r.Header.Set("Accept", tt.accept)
+			if tt.htmx {
+				r.Header.Set("HX-Request", "true")
+			}
+			rec := httptest.NewRecorder()
+			re.Error(rec, r, http.StatusNotFound, errors.New("no such user"))
+
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want 404", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			if tt.contentType == "application/problem+json" {
+				var p map[string]any
+				if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+					t.Fatalf("Unmarshal: %v", err)
+				}
+				if p["status"] != float64(http.StatusNotFound) || p["instance"] != "/users/7" || p["detail"] != nil {
+					t.Errorf("problem = %v, want the status, the path and no detail outside debug mode", p)
+				}
+			} else if got := rec.Body.String(); got != "pagina 404" {
+				t.Errorf("body = %q, want the error page", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package gorender
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// Invalidate quita de la caché la página name, que se vuelve a procesar sola
+// la próxima vez que se pida, por ejemplo después de cambiar en la base de
+// datos una plantilla registrada con AddTemplate. Sin la caché habilitada no
+// hace nada, porque las páginas se procesan en cada petición.
+func (re *Render) Invalidate(name string) {
+	if !re.EnableCache && !re.watch {
+		return
+	}
+
+	re.reloadMu.Lock()
+	defer re.reloadMu.Unlock()
+
+	set := re.TemplateCache.current().clone()
+	key := set.name(name)
+	set.drop(key)
+	set.invalidated[key] = true
+	set.invalidated[name] = true
+	re.TemplateCache.swap(set)
+
+	re.log().Debug("template invalidated", "template", key)
+}
+
+// Rebuild vuelve a procesar sólo la página name, con las plantillas
+// compartidas actuales, y la sustituye en la caché, sin el coste de Reload. Si
+// falla se mantiene la versión anterior y se devuelve el error. Si la página
+// ya no existe, ni en el disco ni registrada con AddTemplate, se quita de la
+// caché y se devuelve un error que envuelve ErrTemplateNotFound.
+//
+// Los cambios en las plantillas compartidas sólo llegan a las demás páginas
+// con Reload.
+func (re *Render) Rebuild(name string) error {
+	re.reloadMu.Lock()
+	defer re.reloadMu.Unlock()
+
+	return re.rebuild(name)
+}
+
+// rebuildInvalidated procesa de nuevo la página name si sigue invalidada,
+// porque otra petición puede haberlo hecho mientras se esperaba el bloqueo.
+func (re *Render) rebuildInvalidated(name string) error {
+	re.reloadMu.Lock()
+	defer re.reloadMu.Unlock()
+
+	if !re.TemplateCache.current().invalidated[name] {
+		return nil
+	}
+
+	return re.rebuild(name)
+}
+
+// parseOnDemand procesa la página name y la añade a la caché la primera vez
+// que se pide con WithLazyParse. Si otra petición la ha procesado mientras se
+// esperaba el bloqueo no se hace nada. Si la página no existe la caché no
+// cambia y el error lo da la búsqueda posterior.
+func (re *Render) parseOnDemand(name string) error {
+	re.reloadMu.Lock()
+	defer re.reloadMu.Unlock()
+
+	current := re.TemplateCache.current()
+	if current.has(name) {
+		return nil
+	}
+
+	start := time.Now()
+	built, err := re.buildSet(name)
+	if err != nil {
+		re.log().Error("error parsing template:", "template", name, "error", err)
+		return err
+	}
+	if len(built.templates) == 0 && len(built.texts) == 0 {
+		return nil
+	}
+
+	set := current.clone()
+	set.merge(built)
+	re.TemplateCache.swap(set)
+	re.stats.built(start, set)
+	re.log().Debug("template parsed", "template", name)
+
+	return nil
+}
+
+func (re *Render) rebuild(name string) error {
+	current := re.TemplateCache.current()
+	key := current.name(name)
+
+	built, err := re.buildSet(key)
+	if err != nil {
+		re.log().Error("error rebuilding template:", "template", key, "error", err)
+		return err
+	}
+	_, found := built.templates[key]
+	if _, ok := built.texts[key]; ok {
+		found = true
+	}
+
+	if !re.EnableCache && !re.watch {
+		if !found {
+			return notFoundError(name, built.keys())
+		}
+		return nil
+	}
+
+	set := current.clone()
+	set.drop(key)
+	delete(set.invalidated, key)
+	delete(set.invalidated, name)
+	if !found {
+		re.TemplateCache.swap(set)
+		re.log().Info("template removed from cache", "template", key)
+		return notFoundError(name, set.keys())
+	}
+
+	set.merge(built)
+	re.TemplateCache.swap(set)
+	re.log().Info("template rebuilt", "template", key)
+	re.liveReload.broadcast()
+
+	return nil
+}
+
+// drop quita del conjunto la página key.
+func (s *templateSet) drop(key string) {
+	delete(s.templates, key)
+	delete(s.texts, key)
+	delete(s.pageFiles, key)
+	delete(s.modTimes, key)
+	delete(s.memory, key)
+	delete(s.pristine, key)
+}
+
+// merge añade a s las páginas de built, que sustituyen a las que tengan la
+// misma clave. Si un nombre de archivo pasa a estar en varias páginas deja de
+// servir como alias.
+func (s *templateSet) merge(built *templateSet) {
+	for key, t := range built.templates {
+		s.templates[key] = t
+	}
+	for key, t := range built.texts {
+		s.texts[key] = t
+	}
+	for key, file := range built.pageFiles {
+		s.pageFiles[key] = file
+	}
+	for key, t := range built.modTimes {
+		s.modTimes[key] = t
+	}
+	for file, source := range built.sources {
+		s.sources[file] = source
+	}
+	for key, content := range built.memory {
+		s.memory[key] = content
+	}
+	for key, t := range built.pristine {
+		s.pristine[key] = t
+	}
+
+	for name, key := range built.aliases {
+		if keys, ok := s.ambiguous[name]; ok {
+			if !slices.Contains(keys, key) {
+				keys = append(append([]string{}, keys...), key)
+				sort.Strings(keys)
+				s.ambiguous[name] = keys
+			}
+			continue
+		}
+		if other, ok := s.aliases[name]; ok && other != key {
+			delete(s.aliases, name)
+			keys := []string{other, key}
+			sort.Strings(keys)
+			s.ambiguous[name] = keys
+			continue
+		}
+		s.aliases[name] = key
+	}
+}
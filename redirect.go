@@ -0,0 +1,89 @@
+package gorender
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WithRedirectHosts indica los hosts, además del de la propia petición, a los
+// que Redirect puede enviar al usuario, por ejemplo el de un proveedor de
+// pagos.
+func WithRedirectHosts(hosts ...string) OptionFunc {
+	return func(re *Render) {
+		if re.redirectHosts == nil {
+			re.redirectHosts = map[string]bool{}
+		}
+		for _, host := range hosts {
+			re.redirectHosts[strings.ToLower(host)] = true
+		}
+	}
+}
+
+// Redirect guarda feedback como mensajes flash y redirige a target, para el
+// patrón POST-redirección-GET. status tiene que ser un 3xx; con cero se usa
+// 303 para los POST y demás métodos que no son GET ni HEAD, de modo que el
+// navegador pide la página siguiente con GET, y 302 para el resto.
+//
+// Para evitar redirecciones abiertas, target tiene que ser una ruta del propio
+// sitio o una dirección http(s) del mismo host o de uno de WithRedirectHosts;
+// si no, se devuelve un error que envuelve ErrUnsafeRedirect y no se responde
+// nada.
+//
+// Ejemplo:
+//
+//	ren.Redirect(w, r, "/users", 0, gorender.FlashMessage{Level: gorender.FeedbackSuccess, Message: "Usuario guardado"})
+func (re *Render) Redirect(w http.ResponseWriter, r *http.Request, target string, status int, feedback ...FlashMessage) error {
+	if status == 0 {
+		status = http.StatusFound
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			status = http.StatusSeeOther
+		}
+	}
+	if status < 300 || status > 399 {
+		return fmt.Errorf("redirect to %s: status %d is not a redirection", target, status)
+	}
+
+	if err := re.checkRedirect(r, target); err != nil {
+		re.log().Warn("refusing unsafe redirect", "path", r.URL.Path, "target", target, "error", err)
+		return err
+	}
+	if err := re.checkStarted(w, "redirect to "+target); err != nil {
+		return err
+	}
+
+	for _, msg := range feedback {
+		if err := re.flashes().Add(w, r, msg); err != nil {
+			return fmt.Errorf("redirect to %s: saving flash message: %w", target, err)
+		}
+	}
+
+	http.Redirect(w, r, target, status)
+
+	return nil
+}
+
+// checkRedirect comprueba que target no saca al usuario del sitio salvo a un
+// host de WithRedirectHosts.
+func (re *Render) checkRedirect(r *http.Request, target string) error {
+	// Los navegadores tratan las barras invertidas como normales, así que
+	// "/\evil.com" es lo mismo que "//evil.com".
+	u, err := url.Parse(strings.ReplaceAll(target, `\`, "/"))
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrUnsafeRedirect, target, err)
+	}
+	if u.Scheme == "" && u.Host == "" {
+		return nil
+	}
+	if u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: %s: scheme %q", ErrUnsafeRedirect, target, u.Scheme)
+	}
+
+	host := strings.ToLower(u.Host)
+	if host == strings.ToLower(r.Host) || re.redirectHosts[host] || re.redirectHosts[strings.ToLower(u.Hostname())] {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s: host %q is not allowed", ErrUnsafeRedirect, target, u.Host)
+}
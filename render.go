@@ -3,17 +3,25 @@ package gorender
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"html/template"
+	"io"
 	"io/fs"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"path"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/justinas/nosurf"
 )
 
-type TemplateCache map[string]*template.Template
-
 type Render struct {
 	EnableCache bool
 	// TemplatesPath es la ruta donde se encuentran las plantillas de la
@@ -23,14 +31,170 @@ type Render struct {
 	// páginas de la aplicación. Estas son las que van a ser llamadas para
 	// mostrar en pantalla.
 	PageTemplatesPath string
-	TemplateCache     TemplateCache
+	TemplateCache     *TemplateCache
 	Functions         template.FuncMap
+	// Logger es donde se registran los mensajes del paquete. Si es nil se usa
+	// slog.Default().
+	Logger *slog.Logger
+	// CSRFTokenFunc obtiene el token CSRF de la petición que se guarda en
+	// TemplateData.CSRFToken. Por defecto usa nosurf; si es nil no se añade
+	// ningún token.
+	CSRFTokenFunc func(*http.Request) string
+	// postRenderFuncs transforman el HTML generado antes de escribirlo.
+	postRenderFuncs []PostRenderFunc
+	// globalMu protege globalData, que se puede modificar en tiempo de
+	// ejecución.
+	globalMu   sync.RWMutex
+	globalData map[string]interface{}
+	// dataFactory crea el TemplateData de partida de cada renderizado.
+	dataFactory func(r *http.Request) *TemplateData
+	// defaultDataFuncs rellenan los datos por defecto de cada renderizado.
+	defaultDataFuncs []DefaultDataFunc
+	// overridePath es el directorio con las plantillas que sustituyen a las
+	// originales.
+	overridePath string
+	// overrides son las plantillas sustituidas que ya se han registrado, con
+	// su archivo de sustitución.
+	overridesMu sync.Mutex
+	overrides   map[string]string
+	// layoutsPath es el directorio de las bases que se pueden elegir con
+	// TemplateWithLayout.
+	layoutsPath string
+	// extensions son las extensiones de los archivos que se consideran
+	// plantillas.
+	extensions []string
+	// textExtensions son las extensiones de las plantillas de texto plano.
+	textExtensions []string
+	// lazy hace que sin caché sólo se procese la página solicitada.
+	lazy bool
+	// parseConcurrency es la cantidad de páginas que se procesan a la vez.
+	parseConcurrency int
+	// leftDelim y rightDelim son los delimitadores de las acciones de las
+	// plantillas. Vacíos equivalen a "{{" y "}}".
+	leftDelim  string
+	rightDelim string
+	// contentType es el Content-Type por defecto de las respuestas.
+	contentType string
+	// jsonIndent y jsonEscapeHTML configuran la salida de JSON.
+	jsonIndent     string
+	jsonEscapeHTML bool
+	// strict hace que las plantillas fallen al acceder a claves inexistentes.
+	strict bool
+	// fs es el sistema de archivos desde el que se leen las plantillas. Si es
+	// nil se leen directamente del disco.
+	fs fs.FS
+	// watch indica si se vigilan las plantillas para reconstruir la caché
+	// cuando cambian.
+	watch         bool
+	watchInterval time.Duration
+	stopWatch     chan struct{}
+	// mu protege el estado de la vigilancia de plantillas.
+	mu sync.Mutex
+	// flashStore guarda los mensajes flash; flashOnce crea el de por defecto.
+	flashStore FlashStore
+	flashOnce  sync.Once
+	// defaultLocale y locales son el idioma por defecto y los soportados
+	// configurados con WithLocales.
+	defaultLocale string
+	locales       []string
+	// translationsFS contiene los catálogos de traducciones en JSON y
+	// translationsDir su directorio en el disco, si lo hay. catalogs guarda
+	// los ya leídos por idioma.
+	translationsFS  fs.FS
+	translationsDir string
+	catalogsMu      sync.RWMutex
+	catalogs        map[string]map[string]string
+	legacyOnce      sync.Once
+	// disableSafeFuncs quita las funciones safe* del FuncMap.
+	disableSafeFuncs bool
+	// staticPrefix y staticDir son la URL y el directorio de los archivos
+	// estáticos de WithStaticDir. assetURLs guarda sus URL con versión.
+	staticPrefix string
+	staticDir    string
+	assetMu      sync.RWMutex
+	assetURLs    map[string]string
+	// manifestPath es el manifest.json de WithAssetManifest y manifest su
+	// contenido. viteDevServer es la URL de WithViteDevServer.
+	manifestPath  string
+	manifestMu    sync.RWMutex
+	manifest      map[string]manifestChunk
+	viteDevServer string
+	// cspPolicy es la cabecera Content-Security-Policy de WithCSP.
+	cspPolicy string
+	// etag activa la cabecera ETag salvo en las páginas de etagExclude.
+	etag        bool
+	etagExclude map[string]bool
+	// lastModified activa la cabecera Last-Modified.
+	lastModified bool
+	// compress activa la compresión de las respuestas de al menos
+	// compressMin bytes.
+	compress    bool
+	compressMin int
+	// minify activa WithMinifyHTML.
+	minify bool
+	// renderTimeout es el tiempo máximo de WithRenderTimeout.
+	renderTimeout time.Duration
+	// errorTemplates son las páginas de error de WithErrorTemplates y
+	// autoErrorPages activa WithAutoErrorPages.
+	errorTemplates map[int]string
+	autoErrorPages bool
+	// debug activa WithDebug.
+	debug bool
+	// stats acumula los contadores de Stats.
+	stats renderStats
+	// metricsHook es la función de WithMetricsHook.
+	metricsHook MetricsHook
+	// memory guarda las plantillas de AddTemplate y AddPartial. reloadMu
+	// evita que se registren mientras se reconstruye la caché.
+	memory   memoryTemplates
+	reloadMu sync.Mutex
+	// exclude son los patrones de WithExclude.
+	exclude []string
+	// followSymlinks activa WithFollowSymlinks y hiddenFiles WithHiddenFiles.
+	followSymlinks bool
+	hiddenFiles    bool
+	// liveReload guarda la configuración y los navegadores de WithLiveReload.
+	liveReload liveReload
+	// requestFuncs activa WithRequestFuncs.
+	requestFuncs bool
+	// boundFuncs son las funciones que se atan al idioma de cada renderizado.
+	// Ver localeFuncNames.
+	boundFuncs []string
+	// funcCollisions son los nombres de función que se han registrado más de
+	// una vez y strictFuncs activa WithStrictFunctions.
+	funcCollisions []string
+	strictFuncs    bool
+	// titlePattern es el patrón de los títulos de WithTitlePattern.
+	titlePattern string
+	// sitemapPages es la dirección de las partes del sitemap de
+	// WithSitemapPages.
+	sitemapPages string
+	// csvBOM activa WithCSVBOM.
+	csvBOM bool
+	// redirectHosts son los hosts externos de WithRedirectHosts.
+	redirectHosts map[string]bool
+	// problemErrors activa WithProblemErrors.
+	problemErrors bool
+	// streamTemplates son las páginas de WithStreaming y streamFlush el
+	// tamaño de WithStreamFlushSize.
+	streamTemplates map[string]bool
+	streamFlush     int
+	// maxRenderSize es el tamaño máximo de WithMaxRenderSize.
+	maxRenderSize int64
 }
 
 type OptionFunc func(*Render)
 
 type TemplateData struct {
 	Data map[string]interface{}
+	// Model es el modelo de la página cuando se renderiza con TypedTemplate.
+	Model interface{}
+	// Meta son el título y las etiquetas de la cabecera de la página, que se
+	// escriben con renderMeta.
+	Meta Meta
+	// Breadcrumbs es la ruta de navegación de la página, que se escribe con
+	// renderBreadcrumbs.
+	Breadcrumbs []Breadcrumb
 	// SessionData contiene los datos de la sesión del usuario.
 	SessionData interface{}
 	// FeedbackData tiene como función mostrar los mensajes habituales de
@@ -38,47 +202,355 @@ type TemplateData struct {
 	// con los errores de validación de formularios pero pueden ser usados para
 	// ello.
 	FeedbackData map[string]string
+	// Messages contiene todos los mensajes de cada nivel añadidos con
+	// AddFeedback y similares, en el orden en que se añadieron.
+	Messages map[string][]string
 	// FormData es una estructura que contiene los errores de validación de los
 	// formularios además de los valores que se han introducido en los campos.
 	FormData  FormData
 	CSRFToken string
 	Page      Pages
+	// Global contiene los datos comunes a todo el sitio configurados con
+	// WithGlobalData. También se copian en Data salvo que la petición ya
+	// tenga un valor con la misma clave.
+	Global map[string]interface{}
+	// CSPNonce es el nonce de la petición para los <script> y <style> en línea
+	// cuando se usa WithCSP. Es el mismo en toda la página. Sin WithCSP ni
+	// CSPMiddleware está vacío.
+	CSPNonce string
+	// Locale es el idioma de la petición detectado con WithLocales. Se puede
+	// fijar a mano para forzar un idioma.
+	Locale string
+	// URL es la dirección de la petición ya procesada, para construir enlaces
+	// con pageURL y sortURL. Se rellena automáticamente si está vacía. Para
+	// todo lo demás se usa Request.
+	URL *url.URL
+	// ContentType sustituye, sólo para esta respuesta, el Content-Type
+	// configurado en el Render.
+	ContentType string
+	// Status es el código de estado HTTP de la respuesta. Si es cero se
+	// responde con 200. Sólo se escribe cuando la plantilla se ha ejecutado
+	// correctamente, así que ante un error se puede seguir respondiendo con
+	// un 500.
+	Status int
+	// SkipLastModified evita la cabecera Last-Modified de WithLastModified en
+	// las páginas cuyo contenido cambia aunque no cambien las plantillas.
+	SkipLastModified bool
+	// Error es el error que muestran las páginas de WithErrorTemplates.
+	Error *ErrorData
+	// SkipMinify deja sin minificar esta respuesta aunque se use
+	// WithMinifyHTML.
+	SkipMinify bool
+	// Request son los datos de la petición: ruta, dirección completa,
+	// consulta, método, host y si viene de HTMX. Es la fuente de la ruta
+	// actual en las plantillas y en IsActive y ActiveClass. Se rellena
+	// automáticamente si está vacío y queda vacío en los métodos que no
+	// reciben petición.
+	Request RequestInfo
+	// Funcs son funciones propias de este renderizado, que sustituyen a las
+	// del FuncMap con el mismo nombre. Ver WithRequestFuncs.
+	Funcs template.FuncMap
+	// modTime es la fecha de modificación de la página renderizada.
+	modTime time.Time
+	// template es el nombre resuelto de la página renderizada.
+	template string
 }
 
+// WithRenderOptions copia de opts las rutas, las funciones, la caché, la
+// función del token CSRF y el logger. Los campos vacíos se ignoran, así que
+// se mantienen los valores por defecto o los de las opciones anteriores.
 func WithRenderOptions(opts *Render) OptionFunc {
 	return func(re *Render) {
-		re.TemplatesPath = opts.TemplatesPath
-		re.PageTemplatesPath = opts.PageTemplatesPath
-
-		if opts.Functions != nil {
-			for k, v := range opts.Functions {
-				re.Functions[k] = v
-			}
+		if opts.TemplatesPath != "" {
+			re.TemplatesPath = opts.TemplatesPath
 		}
+		if opts.PageTemplatesPath != "" {
+			re.PageTemplatesPath = opts.PageTemplatesPath
+		}
+
+		re.addFunctions(opts.Functions)
 
 		if opts.EnableCache {
 			re.EnableCache = opts.EnableCache
-			re.TemplateCache, _ = re.createTemplateCache()
 		}
+
+		if opts.CSRFTokenFunc != nil {
+			re.CSRFTokenFunc = opts.CSRFTokenFunc
+		}
+
+		if opts.Logger != nil {
+			re.Logger = opts.Logger
+		}
+	}
+}
+
+// WithTemplatesPath cambia el directorio de las plantillas compartidas, que
+// por defecto es "templates".
+func WithTemplatesPath(templatesPath string) OptionFunc {
+	return func(re *Render) {
+		re.TemplatesPath = templatesPath
+	}
+}
+
+// WithPageTemplatesPath cambia el directorio de las páginas, que por defecto
+// es "templates/pages".
+func WithPageTemplatesPath(pageTemplatesPath string) OptionFunc {
+	return func(re *Render) {
+		re.PageTemplatesPath = pageTemplatesPath
+	}
+}
+
+// WithCache habilita o deshabilita la caché de plantillas. La caché se
+// construye cuando ya se han aplicado todas las opciones, así que las rutas
+// indicadas después también se tienen en cuenta.
+func WithCache(enabled bool) OptionFunc {
+	return func(re *Render) {
+		re.EnableCache = enabled
+	}
+}
+
+// WithFunctions añade funciones a las plantillas. Si alguna se llama igual que
+// una ya registrada o que una de las de text/template, la sustituye y se
+// registra un aviso; con WithStrictFunctions, New y NewE fallan.
+func WithFunctions(funcs template.FuncMap) OptionFunc {
+	return func(re *Render) {
+		re.addFunctions(funcs)
+	}
+}
+
+// WithLogger hace que los mensajes del paquete se registren en logger en lugar
+// de en slog.Default().
+func WithLogger(logger *slog.Logger) OptionFunc {
+	return func(re *Render) {
+		re.Logger = logger
+	}
+}
+
+// log devuelve el logger configurado o slog.Default() si no hay ninguno.
+func (re *Render) log() *slog.Logger {
+	if re.Logger != nil {
+		return re.Logger
+	}
+
+	return slog.Default()
+}
+
+// logAttrs devuelve los atributos comunes de los registros de un renderizado,
+// la plantilla y la ruta de la petición si la hay, seguidos de attrs.
+func logAttrs(r *http.Request, tmpl string, attrs ...any) []any {
+	all := []any{"template", tmpl}
+	if r != nil && r.URL != nil {
+		all = append(all, "path", r.URL.Path)
 	}
+
+	return append(all, attrs...)
 }
 
+// WithCSRFTokenFunc cambia la forma de obtener el token CSRF de cada petición,
+// por ejemplo para usar gorilla/csrf o un middleware propio. Con nil no se
+// añade ningún token a TemplateData.
+func WithCSRFTokenFunc(fn func(*http.Request) string) OptionFunc {
+	return func(re *Render) {
+		re.CSRFTokenFunc = fn
+	}
+}
+
+// WithExtensions indica las extensiones de los archivos que se consideran
+// plantillas, por ejemplo ".gohtml" o ".tmpl". Por defecto sólo ".html". La
+// comparación no distingue mayúsculas de minúsculas.
+func WithExtensions(exts ...string) OptionFunc {
+	return func(re *Render) {
+		re.extensions = normalizeExtensions(exts)
+	}
+}
+
+func normalizeExtensions(exts []string) []string {
+	var normalized []string
+	for _, ext := range exts {
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		normalized = append(normalized, strings.ToLower(ext))
+	}
+
+	return normalized
+}
+
+// WithParseConcurrency indica cuántas páginas se procesan a la vez al construir
+// la caché. Con cero o menos se usa GOMAXPROCS.
+func WithParseConcurrency(n int) OptionFunc {
+	return func(re *Render) {
+		re.parseConcurrency = n
+	}
+}
+
+// WithLazyParse hace que, con la caché deshabilitada, cada petición procese
+// sólo la página solicitada junto con las plantillas compartidas en lugar de
+// todas las páginas. Las plantillas siguen leyéndose en cada petición, así que
+// los cambios se ven al momento.
+//
+// Con la caché habilitada, New no procesa ninguna página: cada una se procesa
+// la primera vez que se pide y se guarda en la caché. Si varias peticiones la
+// piden a la vez, sólo la primera la procesa. Los errores de sintaxis de una
+// página no se ven hasta que se pide, y Templates sólo devuelve las ya
+// procesadas; Reload y Validate siguen procesándolas todas.
+func WithLazyParse(lazy bool) OptionFunc {
+	return func(re *Render) {
+		re.lazy = lazy
+	}
+}
+
+// WithDelims cambia los delimitadores de las acciones de todas las plantillas,
+// útil cuando las páginas contienen sintaxis de Vue o Angular que también usa
+// "{{ }}". Por ejemplo WithDelims("[[", "]]").
+func WithDelims(left, right string) OptionFunc {
+	return func(re *Render) {
+		re.leftDelim = left
+		re.rightDelim = right
+	}
+}
+
+// WithContentType cambia el Content-Type por defecto de las respuestas, que es
+// "text/html; charset=utf-8".
+func WithContentType(contentType string) OptionFunc {
+	return func(re *Render) {
+		re.contentType = contentType
+	}
+}
+
+// WithStrictMode hace que acceder a una clave que no existe en los datos
+// produzca un error al ejecutar la plantilla en lugar de mostrar "<no value>".
+// El error indica la plantilla y la clave que falta. Como la plantilla se
+// ejecuta sobre un búfer, no se llega a escribir nada en la respuesta.
+func WithStrictMode(strict bool) OptionFunc {
+	return func(re *Render) {
+		re.strict = strict
+	}
+}
+
+// WithFS hace que las plantillas se lean desde fsys en lugar del disco, por
+// ejemplo un embed.FS. TemplatesPath y PageTemplatesPath se resuelven dentro de
+// fsys, así que deben ser rutas con barras normales y sin "./" al principio.
+func WithFS(fsys fs.FS) OptionFunc {
+	return func(re *Render) {
+		re.fs = fsys
+	}
+}
+
+// New crea un nuevo Render con las opciones indicadas. Si la caché está
+// habilitada y no se puede construir, el error se registra y el Render se
+// devuelve igualmente con la caché vacía. Usa NewE para detener el arranque
+// ante plantillas rotas.
 func New(opts ...OptionFunc) *Render {
+	re, err := NewE(opts...)
+	if err != nil {
+		re.log().Error("error creating template cache:", "error", err)
+	}
+
+	return re
+}
+
+// NewE funciona igual que New pero devuelve el error producido al construir la
+// caché de plantillas, con el archivo que lo ha provocado.
+func NewE(opts ...OptionFunc) (*Render, error) {
 	functions := template.FuncMap{
-		"translateKey":   translateKey,
-		"or":             or,
-		"containsErrors": containsErrors,
+		"notEmpty":          notEmpty,
+		"dict":              dict,
+		"list":              list,
+		"default":           defaultValue,
+		"seq":               seq,
+		"until":             until,
+		"hasKey":            hasKey,
+		"containsErrors":    containsErrors,
+		"fieldValue":        fieldValue,
+		"fieldError":        fieldError,
+		"hasError":          hasError,
+		"pageURL":           pageURL,
+		"sortURL":           sortURL,
+		"safeHTML":          safeHTML,
+		"safeCSS":           safeCSS,
+		"safeURL":           safeURL,
+		"safeJS":            safeJS,
+		"safeHTMLAttr":      safeHTMLAttr,
+		"json":              toJSON,
+		"jsonPretty":        toJSONPretty,
+		"truncate":          truncate,
+		"excerpt":           excerpt,
+		"slugify":           slugify,
+		"nl2br":             nl2br,
+		"isActive":          isActive,
+		"isActivePrefix":    isActivePrefix,
+		"activeClass":       activeClass,
+		"activeClassPrefix": activeClassPrefix,
 	}
 
 	config := &Render{
 		EnableCache:       false,
 		TemplatesPath:     "templates",
 		PageTemplatesPath: "templates/pages",
-		TemplateCache:     TemplateCache{},
+		TemplateCache:     NewTemplateCache(),
 		Functions:         functions,
+		CSRFTokenFunc:     nosurf.Token,
+		extensions:        []string{".html"},
+		textExtensions:    []string{".txt"},
+		contentType:       "text/html; charset=utf-8",
+		jsonEscapeHTML:    true,
+		watchInterval:     time.Second,
+	}
+	// Las funciones de traducción y formato dependen de los idiomas
+	// configurados en el Render.
+	functions["translateKey"] = config.translateKey
+	functions["plural"] = config.plural
+	functions["formatNumber"] = config.formatNumber
+	functions["formatCurrency"] = config.formatCurrency
+	functions["formatCents"] = config.formatCents
+	functions["formatDate"] = config.formatDate
+	functions["timeAgo"] = config.timeAgo
+	functions["humanDuration"] = config.humanDuration
+	functions["asset"] = config.asset
+	functions["vite"] = config.vite
+	functions["viteCSS"] = config.viteCSS
+	functions["viteTags"] = config.viteTags
+	functions["renderMeta"] = config.renderMeta
+	functions["renderBreadcrumbs"] = config.renderBreadcrumbs
+	functions["breadcrumbsJSONLD"] = config.breadcrumbsJSONLD
+
+	re := config.apply(opts...)
+	re.removeSafeFuncs()
+	re.setBoundFuncs()
+	re.logFunctions()
+	if err := re.checkFunctions(); err != nil {
+		return re, err
+	}
+
+	if (re.EnableCache || re.watch) && !re.lazy {
+		set, err := re.createTemplateCache()
+		if err != nil {
+			return re, err
+		}
+		re.TemplateCache.swap(set)
+	}
+
+	if re.watch {
+		re.startWatch()
 	}
 
-	return config.apply(opts...)
+	return re, nil
+}
+
+// logFunctions registra una sola vez las funciones disponibles en las
+// plantillas.
+func (re *Render) logFunctions() {
+	names := make([]string, 0, len(re.Functions))
+	for name := range re.Functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	re.log().Debug("template functions registered", "functions", names)
 }
 
 func (re *Render) apply(opts ...OptionFunc) *Render {
@@ -89,94 +561,621 @@ func (re *Render) apply(opts ...OptionFunc) *Render {
 	return re
 }
 
-func addDefaultData(td *TemplateData, r *http.Request) *TemplateData {
-	td.CSRFToken = nosurf.Token(r)
+// initData devuelve td listo para usarse en una plantilla: si es nil crea uno
+// nuevo y los mapas nil se inicializan para que las plantillas puedan
+// recorrerlos sin problemas.
+func initData(td *TemplateData) *TemplateData {
+	if td == nil {
+		td = &TemplateData{}
+	}
+
+	if td.Data == nil {
+		td.Data = map[string]interface{}{}
+	}
+	if td.FeedbackData == nil {
+		td.FeedbackData = map[string]string{}
+	}
+	if td.Messages == nil {
+		td.Messages = map[string][]string{}
+	}
+	if td.FormData.Errors == nil {
+		td.FormData.Errors = map[string]string{}
+	}
+	if td.FormData.Values == nil {
+		td.FormData.Values = map[string]string{}
+	}
+
 	return td
 }
 
-func (re *Render) Template(w http.ResponseWriter, r *http.Request, tmpl string, td *TemplateData) error {
-	var tc TemplateCache
-	var err error
+func (re *Render) addDefaultData(td *TemplateData, r *http.Request) *TemplateData {
+	td = re.fromFactory(td, r)
+	td = re.baseData(td)
+	if re.CSRFTokenFunc != nil {
+		td.CSRFToken = re.CSRFTokenFunc(r)
+	}
+	if td.URL == nil {
+		td.URL = r.URL
+	}
+	if td.Locale == "" {
+		td.Locale = re.Locale(r)
+	}
+	if td.CSPNonce == "" {
+		td.CSPNonce = re.requestNonce(r)
+	}
+	if td.Request.isZero() {
+		td.Request = newRequestInfo(r)
+	}
+	re.runDefaultDataFuncs(td, r)
+	return td
+}
 
-	if re.EnableCache {
-		tc = re.TemplateCache
-	} else {
-		tc, err = re.createTemplateCache()
-		if err != nil {
-			slog.Error("error creating template cache:", "error", err)
-			return err
+func (re *Render) Template(w http.ResponseWriter, r *http.Request, tmpl string, td *TemplateData) (err error) {
+	if re.streamTemplates[tmpl] {
+		return re.TemplateStream(w, r, tmpl, td)
+	}
+
+	start := time.Now()
+	defer func() { re.finish(w, r, start, tmpl, td, err) }()
+
+	buf := getBuffer()
+	td = re.addDefaultData(td, r)
+	re.drainFlashes(w, r, td)
+	err = re.execute(buf, r, tmpl, td)
+	if err != nil {
+		putBuffer(buf)
+		return err
+	}
+
+	return re.respond(w, r, tmpl, buf, td)
+}
+
+// write envía buf a la respuesta con el Content-Type y el código de estado de
+// td. Si el manejador ya había puesto un Content-Type se respeta. El búfer
+// vuelve al pool cuando se ha escrito por completo.
+func (re *Render) write(w http.ResponseWriter, r *http.Request, tmpl string, buf *bytes.Buffer, td *TemplateData, contentType string) error {
+	// Si el cliente ya se ha ido no se escribe nada.
+	if err := requestGone(r); err != nil {
+		putBuffer(buf)
+		re.log().Debug("request canceled before writing response", logAttrs(r, tmpl, "error", err)...)
+		return fmt.Errorf("%s: %w", tmpl, err)
+	}
+	// Si ya se ha escrito algo, la página acabaría pegada a ello.
+	if responseStarted(w) {
+		putBuffer(buf)
+		return re.startedError(r, tmpl)
+	}
+
+	if w.Header().Get("Content-Type") == "" {
+		if td.ContentType != "" {
+			contentType = td.ContentType
 		}
+		w.Header().Set("Content-Type", contentType)
 	}
+	re.setCSPHeader(w, td)
 
-	t, ok := tc[tmpl]
-	if !ok {
-		return errors.New("can't get template from cache")
+	encoding := re.responseEncoding(w, r, buf)
+	if re.notModified(w, r, tmpl, buf, td, encoding) {
+		putBuffer(buf)
+		return nil
+	}
+	buf = re.compressBody(w, buf, encoding)
+	setContentLength(w, buf, td.Status)
+
+	if td.Status != 0 {
+		w.WriteHeader(td.Status)
+	}
+
+	// A una petición HEAD se le envían las cabeceras, incluida la longitud,
+	// pero no el cuerpo.
+	if r != nil && r.Method == http.MethodHead {
+		putBuffer(buf)
+		return nil
+	}
+
+	_, err := buf.WriteTo(w)
+	if err != nil {
+		re.log().Error("error writing template to browser:", logAttrs(r, tmpl, "error", err)...)
+		return fmt.Errorf("%w: %w", ErrWrite, err)
 	}
 
-	buf := new(bytes.Buffer)
-	td = addDefaultData(td, r)
-	err = t.Execute(buf, td)
+	putBuffer(buf)
+	return nil
+}
+
+// setContentLength fija Content-Length con el tamaño de buf salvo que ya esté
+// fijada o que otro, un middleware de compresión por ejemplo, vaya a cambiar
+// el cuerpo después. Las respuestas sin cuerpo no la llevan.
+func setContentLength(w http.ResponseWriter, buf *bytes.Buffer, status int) {
+	header := w.Header()
+	if header.Get("Content-Length") != "" || header.Get("Content-Encoding") != "" || header.Get("Transfer-Encoding") != "" {
+		return
+	}
+	if status == http.StatusNoContent || status == http.StatusNotModified || (status >= 100 && status < 200) {
+		return
+	}
+
+	header.Set("Content-Length", strconv.Itoa(buf.Len()))
+}
+
+// RenderTo procesa una página y la escribe en w sin necesidad de una petición
+// HTTP, por ejemplo para correos o informes generados en segundo plano. No se
+// añaden los datos por defecto de la petición, así que el token CSRF queda
+// como venga en td. La página se ejecuta primero sobre un búfer, de modo que
+// si falla no se escribe nada en w.
+func (re *Render) RenderTo(w io.Writer, tmpl string, td *TemplateData) (err error) {
+	start := time.Now()
+	defer func() { re.observe(start, tmpl, td, err) }()
+
+	td = re.baseData(td)
+	buf := getBuffer()
+	err = re.execute(buf, nil, tmpl, td)
+	if err != nil {
+		putBuffer(buf)
+		return err
+	}
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		return err
+	}
+
+	putBuffer(buf)
+	return nil
+}
+
+// RenderRequest procesa una página con los datos por defecto de r, como el
+// token CSRF, el idioma o la ruta, y la escribe en w. Está pensado para los
+// frameworks que piden el HTML sobre un io.Writer y se encargan ellos de la
+// respuesta; por eso no se escriben cabeceras ni se consumen los mensajes
+// flash. Igual que RenderTo, si la página falla no se escribe nada en w, y
+// con r nil se comporta como RenderTo.
+func (re *Render) RenderRequest(w io.Writer, r *http.Request, tmpl string, td *TemplateData) (err error) {
+	start := time.Now()
+	defer func() { re.observe(start, tmpl, td, err) }()
+
+	if r != nil {
+		td = re.addDefaultData(td, r)
+	} else {
+		td = re.baseData(td)
+	}
+	buf := getBuffer()
+	err = re.execute(buf, r, tmpl, td)
 	if err != nil {
-		slog.Error("error executing template:", "error", err)
+		putBuffer(buf)
 		return err
 	}
 
 	_, err = buf.WriteTo(w)
 	if err != nil {
-		slog.Error("error writing template to browser:", "error", err)
+		return fmt.Errorf("%w: %w", ErrWrite, err)
 	}
 
+	putBuffer(buf)
 	return nil
 }
 
-func findHTMLFiles(root string) ([]string, error) {
+// TemplateString procesa una página y devuelve el resultado como cadena, útil
+// para pruebas o para componer correos. Igual que RenderTo, no necesita una
+// petición HTTP y el token CSRF queda como venga en td.
+func (re *Render) TemplateString(tmpl string, td *TemplateData) (string, error) {
+	td = re.baseData(td)
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	err := re.execute(buf, nil, tmpl, td)
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// execute busca la página en la caché y la ejecuta sobre out. r sólo se usa
+// para los registros y puede ser nil.
+func (re *Render) execute(out io.Writer, r *http.Request, tmpl string, td *TemplateData) error {
+	t, set, err := re.lookupSet(tmpl)
+	if err != nil {
+		return err
+	}
+
+	return re.executeSet(out, r, set, t, tmpl, td)
+}
+
+// executeSet ejecuta sobre out la página t, que es tmpl dentro de set.
+func (re *Render) executeSet(out io.Writer, r *http.Request, set *templateSet, t *template.Template, tmpl string, td *TemplateData) error {
+	td.template = set.name(tmpl)
+	td.modTime, _ = set.modTime(tmpl)
+	t, err := re.withFuncs(set, t, set.pristine[td.template], tmpl, td)
+	if err != nil {
+		return err
+	}
+
+	err = re.executeGuarded(out, r, tmpl, func(w io.Writer) error {
+		return t.Execute(w, td)
+	})
+	if err != nil {
+		re.stats.failed(td.template)
+		return err
+	}
+	re.stats.rendered(td.template)
+
+	return nil
+}
+
+// lookup busca una página en la caché o, si está deshabilitada, la procesa.
+func (re *Render) lookup(tmpl string) (*template.Template, error) {
+	t, _, err := re.lookupSet(tmpl)
+	return t, err
+}
+
+// lookupSet funciona igual que lookup pero devuelve también el conjunto del
+// que se ha obtenido la página.
+func (re *Render) lookupSet(tmpl string) (*template.Template, *templateSet, error) {
+	set, err := re.setFor(tmpl)
+	if err != nil {
+		re.log().Error("error creating template cache:", "template", tmpl, "error", err)
+		return nil, nil, err
+	}
+
+	t, ok := set.lookup(tmpl)
+	re.stats.lookup(tmpl, ok)
+	if !ok {
+		return nil, nil, re.missingError(set, tmpl)
+	}
+
+	return t, set, nil
+}
+
+// missingError devuelve el error de una página que no está en set: si su nombre
+// de archivo lo comparten varias páginas, ErrAmbiguousTemplate con sus
+// archivos, y si no ErrTemplateNotFound.
+func (re *Render) missingError(set *templateSet, name string) error {
+	keys, ok := set.ambiguous[name]
+	if !ok {
+		return notFoundError(name, set.keys())
+	}
+
+	files := make([]string, 0, len(keys))
+	for _, key := range keys {
+		file, ok := set.pageFiles[key]
+		if !ok {
+			file = filepath.Join(re.root(re.PageTemplatesPath), filepath.FromSlash(key))
+			if re.fs != nil {
+				file = path.Join(re.root(re.PageTemplatesPath), key)
+			}
+		}
+		if re.fs == nil {
+			if abs, err := filepath.Abs(file); err == nil {
+				file = abs
+			}
+		}
+		files = append(files, file)
+	}
+
+	return ambiguousError(name, files)
+}
+
+// currentSet devuelve las plantillas de la caché o, si está deshabilitada, las
+// procesa de nuevo.
+func (re *Render) currentSet() (*templateSet, error) {
+	if re.EnableCache || re.watch {
+		return re.TemplateCache.current(), nil
+	}
+
+	return re.createTemplateCache()
+}
+
+// setFor devuelve un conjunto que contiene al menos la página tmpl. Con
+// WithLazyParse sólo se procesa esa página: sin caché en cada petición y con
+// caché la primera vez que se pide. Si tmpl se ha quitado de la caché con
+// Invalidate se procesa de nuevo.
+func (re *Render) setFor(tmpl string) (*templateSet, error) {
+	if re.EnableCache || re.watch {
+		current := re.TemplateCache.current()
+		if current.invalidated[tmpl] {
+			if err := re.rebuildInvalidated(tmpl); err != nil {
+				return nil, err
+			}
+		} else if re.lazy && !current.has(tmpl) {
+			if err := re.parseOnDemand(tmpl); err != nil {
+				return nil, err
+			}
+		}
+		return re.TemplateCache.current(), nil
+	}
+
+	re.stats.requestBuild()
+	if re.lazy {
+		start := time.Now()
+		set, err := re.buildSet(tmpl)
+		if err == nil {
+			re.stats.built(start, set)
+		}
+		return set, err
+	}
+
+	return re.createTemplateCache()
+}
+
+// findTemplateFiles busca recursivamente las plantillas HTML dentro de root, ya
+// sea en el disco o en el sistema de archivos configurado con WithFS.
+func (re *Render) findTemplateFiles(root string) ([]string, error) {
+	return re.findFiles(root, re.extensions)
+}
+
+// findFiles busca recursivamente dentro de root las plantillas con alguna de
+// las extensiones indicadas.
+func (re *Render) findFiles(root string, exts []string) ([]string, error) {
 	var files []string
 
-	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+	err := re.walkTemplates(root, func(path string, d fs.DirEntry) error {
+		if hasExtension(path, exts) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	files = append(files, re.overrideOnlyFiles(root, exts, files)...)
+
+	return files, nil
+}
+
+// walkTemplates recorre root y llama a fn por cada plantilla encontrada, sea
+// HTML o de texto.
+func (re *Render) walkTemplates(root string, fn func(path string, d fs.DirEntry) error) error {
+	walkRoot := re.root(root)
+	walkFn := func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if !d.IsDir() && filepath.Ext(path) == ".html" {
-			files = append(files, path)
+		if skip, err := re.skipEntry(walkRoot, path, d); skip {
+			return err
+		}
+
+		if !d.IsDir() && re.isTemplateFile(path) {
+			return fn(path, d)
 		}
 
 		return nil
-	})
+	}
 
+	if re.fs != nil {
+		return fs.WalkDir(re.fs, walkRoot, walkFn)
+	}
+
+	return re.walkDisk(walkRoot, walkFn)
+}
+
+// isTemplateFile indica si la extensión del archivo es una de las configuradas.
+func (re *Render) isTemplateFile(file string) bool {
+	return hasExtension(file, re.extensions) || hasExtension(file, re.textExtensions)
+}
+
+func hasExtension(file string, exts []string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+	for _, e := range exts {
+		if ext == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+// root devuelve p tal y como se usa al recorrer las plantillas: sin cambios en
+// disco y adaptada con fsPath si se usa WithFS.
+func (re *Render) root(p string) string {
+	if re.fs != nil {
+		return fsPath(p)
+	}
+
+	return p
+}
+
+// fsPath adapta una ruta para que sea válida dentro de un fs.FS.
+func fsPath(p string) string {
+	return path.Clean(filepath.ToSlash(p))
+}
+
+// createTemplateCache procesa todas las páginas y devuelve un conjunto nuevo,
+// listo para sustituir al de TemplateCache.
+func (re *Render) createTemplateCache() (*templateSet, error) {
+	start := time.Now()
+	set, err := re.buildSet("")
 	if err != nil {
-		return nil, err
+		return set, err
 	}
 
-	return files, nil
+	re.stats.built(start, set)
+	re.log().Debug("template cache built", "templates", len(set.templates), "text_templates", len(set.texts), "duration", time.Since(start))
+
+	return set, nil
 }
 
-func (re *Render) createTemplateCache() (TemplateCache, error) {
-	myCache := TemplateCache{}
+// buildSet procesa las páginas y devuelve un conjunto nuevo. Si only no está
+// vacío sólo se procesan las páginas cuya clave o nombre de archivo coincide
+// con only.
+func (re *Render) buildSet(only string) (*templateSet, error) {
+	myCache := newTemplateSet()
 
-	pagesTemplates, err := findHTMLFiles(re.PageTemplatesPath)
+	// Las traducciones, el manifest y las versiones de los archivos estáticos
+	// se leen a la vez que las plantillas para que los cambios se vean en los
+	// mismos casos.
+	if err := re.loadCatalogs(); err != nil {
+		return myCache, err
+	}
+	re.resetAssets()
+	if err := re.loadManifest(); err != nil {
+		return myCache, err
+	}
+
+	pagesTemplates, err := re.findTemplateFiles(re.PageTemplatesPath)
+	if err != nil {
+		return myCache, fmt.Errorf("finding page templates in %s: %w", re.PageTemplatesPath, err)
+	}
+	pagesTemplates = re.filterPages(pagesTemplates, only)
+
+	files, err := re.findTemplateFiles(re.TemplatesPath)
+	if err != nil {
+		return myCache, fmt.Errorf("finding templates in %s: %w", re.TemplatesPath, err)
+	}
+
+	files = re.withoutLayouts(files)
+	myCache.sharedFiles = files
+	re.logOverrides(append(append([]string{}, files...), pagesTemplates...))
+	re.recordSources(myCache, files...)
+	re.recordSources(myCache, pagesTemplates...)
+
+	parsed, err := re.parsePages(pagesTemplates, files)
 	if err != nil {
 		return myCache, err
 	}
 
-	files, err := findHTMLFiles(re.TemplatesPath)
+	var sharedModTime time.Time
+	for _, file := range files {
+		if t := re.templateModTime(file); t.After(sharedModTime) {
+			sharedModTime = t
+		}
+	}
+
+	basenames := map[string][]string{}
+	for i, file := range pagesTemplates {
+		ts := parsed[i]
+		key := re.pageKey(file)
+		myCache.templates[key] = ts
+		re.keepPristine(myCache, key, ts)
+		myCache.pageFiles[key] = file
+		myCache.modTimes[key] = sharedModTime
+		if t := re.templateModTime(file); t.After(sharedModTime) {
+			myCache.modTimes[key] = t
+		}
+		name := ts.Name()
+		basenames[name] = append(basenames[name], key)
+	}
+
+	err = re.parseTextPages(myCache, basenames, only)
 	if err != nil {
 		return myCache, err
 	}
 
-	for function := range re.Functions {
-		slog.Info("function found", "function", function)
+	err = re.addMemoryPages(myCache, only)
+	if err != nil {
+		return myCache, err
 	}
 
-	for _, file := range pagesTemplates {
-		name := filepath.Base(file)
-		ts, err := template.New(name).Funcs(re.Functions).ParseFiles(append(files, file)...)
-		if err != nil {
-			return myCache, err
+	for name, keys := range basenames {
+		if len(keys) == 1 {
+			myCache.aliases[name] = keys[0]
+			continue
 		}
 
-		myCache[name] = ts
+		sort.Strings(keys)
+		myCache.ambiguous[name] = keys
+		re.log().Warn("page templates share the same file name, use the relative path to render them", "name", name, "templates", keys)
 	}
 
 	return myCache, nil
 }
+
+// filterPages devuelve las páginas cuya clave o nombre de archivo coincide con
+// only, o todas si only está vacío.
+func (re *Render) filterPages(pages []string, only string) []string {
+	if only == "" {
+		return pages
+	}
+
+	var filtered []string
+	for _, file := range pages {
+		if re.pageKey(file) == only || path.Base(filepath.ToSlash(file)) == only {
+			filtered = append(filtered, file)
+		}
+	}
+
+	return filtered
+}
+
+// parsePages procesa las páginas en paralelo con tantos trabajadores como
+// indique WithParseConcurrency. El resultado mantiene el orden de pages y, si
+// alguna falla, se devuelven todos los errores con el archivo que los provoca.
+func (re *Render) parsePages(pages []string, shared []string) ([]*template.Template, error) {
+	parsed := make([]*template.Template, len(pages))
+	errs := make([]error, len(pages))
+
+	workers := re.parseConcurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(pages) {
+		workers = len(pages)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				ts, err := re.parsePage(pages[i], shared...)
+				if err != nil {
+					errs[i] = fmt.Errorf("parsing page template %s: %w", pages[i], err)
+					continue
+				}
+				parsed[i] = ts
+			}
+		}()
+	}
+
+	for i := range pages {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return parsed, errors.Join(errs...)
+}
+
+// parsePage procesa la página file junto con las plantillas compartidas. Los
+// archivos se procesan en orden, así que las definiciones de los últimos
+// sustituyen a las de los primeros y las de la página prevalecen sobre todas.
+// Los fragmentos de AddPartial se añaden entre las compartidas y la página.
+func (re *Render) parsePage(file string, shared ...string) (*template.Template, error) {
+	ts, err := parseFiles(re, re.newPage(path.Base(filepath.ToSlash(file))), shared...)
+	if err != nil {
+		return nil, err
+	}
+
+	ts, err = re.addPartials(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFiles(re, ts, file)
+}
+
+// newPage crea una página vacía con los delimitadores, las funciones y las
+// opciones configuradas.
+func (re *Render) newPage(name string) *template.Template {
+	ts := template.New(name).Delims(re.leftDelim, re.rightDelim).Funcs(re.Functions)
+	if re.strict {
+		ts = ts.Option("missingkey=error")
+	}
+
+	return ts
+}
+
+// pageKey devuelve la clave de la página en la caché: su ruta relativa a
+// PageTemplatesPath con barras normales, por ejemplo "admin/index.html".
+func (re *Render) pageKey(file string) string {
+	rel, err := filepath.Rel(re.root(re.PageTemplatesPath), file)
+	if err != nil {
+		return path.Base(filepath.ToSlash(file))
+	}
+
+	return filepath.ToSlash(rel)
+}
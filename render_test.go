@@ -0,0 +1,344 @@
+package gorender
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithDelims(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/app.html":    `[[ template "title" . ]]<div id="app">{{ message }} [[ .Data.name ]]</div>`,
+		"shared/title.html": `[[ define "title" ]]<h1>[[ .Data.title ]]</h1>[[ end ]]`,
+	}, WithDelims("[[", "]]"))
+
+	var buf bytes.Buffer
+	td := &TemplateData{Data: map[string]interface{}{"title": "Hola", "name": "Ana"}}
+	if err := re.RenderTo(&buf, "app.html", td); err != nil {
+		t.Fatalf("RenderTo: %v", err)
+	}
+
+	want := `<h1>Hola</h1><div id="app">{{ message }} Ana</div>`
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+// syntheticPages devuelve n páginas que usan varias plantillas compartidas.
+func syntheticPages(n int) map[string]string {
+	files := map[string]string{
+		"shared/base.html":   `{{ define "base" }}<html>{{ template "body" . }}</html>{{ end }}`,
+		"shared/header.html": `{{ define "header" }}<header>{{ .Data.title }}</header>{{ end }}`,
+		"shared/footer.html": `{{ define "footer" }}<footer>pie</footer>{{ end }}`,
+	}
+	for i := 0; i < n; i++ {
+		files[fmt.Sprintf("pages/section%d/page%d.html", i%10, i)] = fmt.Sprintf(
+			`{{ template "base" . }}{{ define "body" }}{{ template "header" . }}<p>%d</p>{{ template "footer" }}{{ end }}`, i)
+	}
+
+	return files
+}
+
+func TestParseConcurrency(t *testing.T) {
+	files := syntheticPages(50)
+	var outputs []map[string]string
+	for _, n := range []int{1, 8} {
+		re := newTestRender(t, files, WithParseConcurrency(n))
+		out := map[string]string{}
+		for _, key := range re.TemplateCache.current().keys() {
+			var buf bytes.Buffer
+			if err := re.RenderTo(&buf, key, &TemplateData{Data: map[string]interface{}{"title": "t"}}); err != nil {
+				t.Fatalf("RenderTo %s: %v", key, err)
+			}
+			out[key] = buf.String()
+		}
+		outputs = append(outputs, out)
+	}
+
+	if len(outputs[0]) != 50 {
+		t.Fatalf("got %d pages, want 50", len(outputs[0]))
+	}
+	for key, want := range outputs[0] {
+		if got := outputs[1][key]; got != want {
+			t.Errorf("%s: concurrent parse = %q, sequential = %q", key, got, want)
+		}
+	}
+}
+
+func TestParseConcurrencyErrors(t *testing.T) {
+	files := syntheticPages(20)
+	files["pages/broken1.html"] = `{{ if }}`
+	files["pages/broken2.html"] = `{{ end }}`
+
+	fsys := fstest.MapFS{}
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	_, err := NewE(
+		WithFS(fsys),
+		WithTemplatesPath("shared"),
+		WithPageTemplatesPath("pages"),
+		WithCache(true),
+		WithParseConcurrency(4),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	if err == nil {
+		t.Fatal("NewE succeeded with broken pages")
+	}
+	for _, file := range []string{"broken1.html", "broken2.html"} {
+		if !strings.Contains(err.Error(), file) {
+			t.Errorf("error %q does not name %s", err, file)
+		}
+	}
+}
+
+func BenchmarkParsePages(b *testing.B) {
+	files := syntheticPages(300)
+	for _, n := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", n), func(b *testing.B) {
+			re := newTestRender(b, files, WithParseConcurrency(n))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := re.createTemplateCache(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestNilTemplateData(t *testing.T) {
+	files := map[string]string{
+		"pages/index.html": `{{ range $k, $v := .Data }}{{ $k }}{{ end }}` +
+			`{{ range $k, $v := .FeedbackData }}{{ $k }}{{ end }}` +
+			`{{ range $k, $v := .FormData.Errors }}{{ $k }}{{ end }}` +
+			`{{ index .FormData.Values "email" }}ok`,
+	}
+
+	tests := []struct {
+		name string
+		td   *TemplateData
+	}{
+		{"nil", nil},
+		{"nil maps", &TemplateData{}},
+		{"nil form maps", &TemplateData{Data: map[string]interface{}{}, FormData: FormData{HasErrors: true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := newTestRender(t, files)
+			rec := httptest.NewRecorder()
+			if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "index.html", tt.td); err != nil {
+				t.Fatalf("Template: %v", err)
+			}
+			if got := rec.Body.String(); got != "ok" {
+				t.Errorf("body = %q, want %q", got, "ok")
+			}
+
+			var buf bytes.Buffer
+			if err := re.RenderTo(&buf, "index.html", tt.td); err != nil {
+				t.Fatalf("RenderTo: %v", err)
+			}
+			if buf.String() != "ok" {
+				t.Errorf("RenderTo output = %q, want %q", buf.String(), "ok")
+			}
+		})
+	}
+}
+
+func TestHeadRequest(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": `<p>{{ .Data.msg }}</p>`}, WithETag(true))
+	td := func() *TemplateData { return &TemplateData{Data: map[string]interface{}{"msg": "hola"}} }
+
+	get := httptest.NewRecorder()
+	if err := re.Template(get, httptest.NewRequest("GET", "/", nil), "index.html", td()); err != nil {
+		t.Fatalf("Template GET: %v", err)
+	}
+
+	head := httptest.NewRecorder()
+	if err := re.Template(head, httptest.NewRequest("HEAD", "/", nil), "index.html", td()); err != nil {
+		t.Fatalf("Template HEAD: %v", err)
+	}
+
+	if head.Body.Len() != 0 {
+		t.Errorf("HEAD body = %q, want none", head.Body.String())
+	}
+	if head.Code != http.StatusOK {
+		t.Errorf("HEAD status = %d, want 200", head.Code)
+	}
+	if got, want := head.Header().Get("Content-Length"), strconv.Itoa(get.Body.Len()); got != want {
+		t.Errorf("HEAD Content-Length = %q, want the GET body size %s", got, want)
+	}
+	for _, h := range []string{"Content-Type", "ETag"} {
+		if got, want := head.Header().Get(h), get.Header().Get(h); got == "" || got != want {
+			t.Errorf("HEAD %s = %q, want %q", h, got, want)
+		}
+	}
+}
+
+func TestHeadRequestExecuteError(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": `{{ .Data.msg.Missing }}`})
+
+	rec := httptest.NewRecorder()
+	td := &TemplateData{Data: map[string]interface{}{"msg": "hola"}}
+	if err := re.Template(rec, httptest.NewRequest("HEAD", "/", nil), "index.html", td); !errors.Is(err, ErrExecute) {
+		t.Errorf("Template HEAD error = %v, want ErrExecute", err)
+	}
+}
+
+func TestAmbiguousTemplate(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/blog/index.html": `blog`,
+		"pages/shop/index.html": `tienda`,
+		"pages/about.html":      `sobre`,
+	})
+
+	_, err := re.TemplateString("index.html", nil)
+	if !errors.Is(err, ErrAmbiguousTemplate) {
+		t.Fatalf("TemplateString error = %v, want ErrAmbiguousTemplate", err)
+	}
+	for _, file := range []string{"pages/blog/index.html", "pages/shop/index.html"} {
+		if !strings.Contains(err.Error(), file) {
+			t.Errorf("error %q does not list %s", err, file)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "index.html", nil); !errors.Is(err, ErrAmbiguousTemplate) {
+		t.Errorf("Template error = %v, want ErrAmbiguousTemplate", err)
+	}
+
+	for page, want := range map[string]string{"blog/index.html": "blog", "shop/index.html": "tienda", "about.html": "sobre"} {
+		if got, err := re.TemplateString(page, nil); err != nil || got != want {
+			t.Errorf("%s = %q, %v, want %q", page, got, err, want)
+		}
+	}
+}
+
+func TestWithExtensions(t *testing.T) {
+	files := map[string]string{
+		"shared/nav.TMPL":   `{{ define "nav" }}<nav></nav>{{ end }}`,
+		"shared/title.html": `{{ define "title" }}<h1></h1>{{ end }}`,
+		"pages/home.HTML":   `{{ template "title" }}home`,
+		"pages/post.gohtml": `{{ template "nav" }}post`,
+		"pages/card.tmpl":   `card`,
+		"pages/notes.md":    `{{ if }}`,
+	}
+
+	tests := []struct {
+		name  string
+		opts  []OptionFunc
+		pages []string
+	}{
+		{"default", nil, []string{"home.HTML"}},
+		{"several", []OptionFunc{WithExtensions("html", ".GoHTML", ".tmpl")}, []string{"card.tmpl", "home.HTML", "post.gohtml"}},
+		{"without html", []OptionFunc{WithExtensions(".gohtml", "TMPL", "")}, []string{"card.tmpl", "post.gohtml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, content := range files {
+				fsys[name] = &fstest.MapFile{Data: []byte(content)}
+			}
+			re := newTestRender(t, nil, append([]OptionFunc{WithFS(fsys)}, tt.opts...)...)
+
+			if got := re.Templates(); strings.Join(got, ",") != strings.Join(tt.pages, ",") {
+				t.Errorf("Templates() = %v, want %v", got, tt.pages)
+			}
+			for _, page := range tt.pages {
+				if err := re.RenderTo(io.Discard, page, nil); err != nil {
+					t.Errorf("RenderTo(%s): %v", page, err)
+				}
+			}
+		})
+	}
+}
+
+func TestWithStrictMode(t *testing.T) {
+	files := map[string]string{
+		"pages/index.html": `<p>{{ .Data.name }}</p>{{ .Data.missing }}`,
+		"pages/mail.txt":   `{{ .Data.name }} {{ .Data.missing }}`,
+	}
+	td := func() *TemplateData { return NewData().Set("name", "Ana").Build() }
+
+	re := newTestRender(t, files)
+	var buf bytes.Buffer
+	if err := re.TextTo(&buf, "mail.txt", td()); err != nil {
+		t.Fatalf("TextTo: %v", err)
+	}
+	if got := buf.String(); got != "Ana <no value>" {
+		t.Errorf("without strict mode = %q, want %q", got, "Ana <no value>")
+	}
+	buf.Reset()
+	if err := re.RenderTo(&buf, "index.html", td()); err != nil {
+		t.Fatalf("RenderTo: %v", err)
+	}
+	if got := buf.String(); got != "<p>Ana</p>" {
+		t.Errorf("without strict mode = %q, want %q", got, "<p>Ana</p>")
+	}
+
+	re = newTestRender(t, files, WithStrictMode(true))
+	for _, page := range []string{"index.html", "mail.txt"} {
+		t.Run(page, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			var err error
+			if page == "mail.txt" {
+				err = re.Text(rec, httptest.NewRequest("GET", "/", nil), page, td())
+			} else {
+				err = re.Template(rec, httptest.NewRequest("GET", "/", nil), page, td())
+			}
+			if !errors.Is(err, ErrExecute) {
+				t.Fatalf("err = %v, want ErrExecute", err)
+			}
+			if !strings.Contains(err.Error(), page) || !strings.Contains(err.Error(), `"missing"`) {
+				t.Errorf("error = %q, want the template and the missing key", err)
+			}
+			if rec.Body.Len() != 0 {
+				t.Errorf("failed render wrote %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWithContentType(t *testing.T) {
+	files := map[string]string{"pages/feed.html": `<feed></feed>`}
+
+	tests := []struct {
+		name   string
+		opts   []OptionFunc
+		header string
+		td     *TemplateData
+		want   string
+	}{
+		{"default", nil, "", nil, "text/html; charset=utf-8"},
+		{"configured", []OptionFunc{WithContentType("application/xhtml+xml")}, "", nil, "application/xhtml+xml"},
+		{"per response", []OptionFunc{WithContentType("application/xhtml+xml")}, "", &TemplateData{ContentType: "application/atom+xml"}, "application/atom+xml"},
+		{"set by the handler", []OptionFunc{WithContentType("application/xhtml+xml")}, "text/plain", nil, "text/plain"},
+		{"handler over per response", nil, "text/plain", &TemplateData{ContentType: "application/atom+xml"}, "text/plain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := newTestRender(t, files, tt.opts...)
+			rec := httptest.NewRecorder()
+			if tt.header != "" {
+				rec.Header().Set("Content-Type", tt.header)
+			}
+			if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "feed.html", tt.td); err != nil {
+				t.Fatalf("Template: %v", err)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.want {
+				t.Errorf("Content-Type = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
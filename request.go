@@ -0,0 +1,72 @@
+package gorender
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// RequestInfo son los datos de la petición que suelen necesitar las
+// plantillas: enlaces canónicos, el enlace activo del menú, campos ocultos con
+// la dirección de vuelta o los filtros que hay que conservar en un formulario.
+type RequestInfo struct {
+	// Path es la ruta de la petición, sin la consulta.
+	Path string
+	// FullURL es la dirección completa, con el esquema, el host y la consulta.
+	FullURL string
+	// Query son los parámetros de la consulta. Aunque no haya petición se
+	// puede usar {{ .Request.Query.Get "q" }}.
+	Query  url.Values
+	Method string
+	Host   string
+	// IsHTMX indica si la petición la hace HTMX (cabecera HX-Request).
+	IsHTMX bool
+}
+
+// isZero indica si ri está vacío, sin petición para rellenarlo.
+func (ri RequestInfo) isZero() bool {
+	return ri.Method == "" && ri.Path == ""
+}
+
+// newRequestInfo devuelve los datos de r. Con nil devuelve un RequestInfo
+// vacío.
+func newRequestInfo(r *http.Request) RequestInfo {
+	if r == nil {
+		return RequestInfo{}
+	}
+
+	info := RequestInfo{
+		Method: r.Method,
+		Host:   r.Host,
+		IsHTMX: r.Header.Get("HX-Request") == "true",
+	}
+	if r.URL == nil {
+		return info
+	}
+
+	info.Path = r.URL.Path
+	info.Query = r.URL.Query()
+
+	full := *r.URL
+	if full.Host == "" {
+		full.Host = r.Host
+	}
+	if full.Scheme == "" {
+		full.Scheme = requestScheme(r)
+	}
+	info.FullURL = full.String()
+
+	return info
+}
+
+// requestScheme devuelve el esquema con el que ha llegado r, teniendo en
+// cuenta la cabecera X-Forwarded-Proto de los proxies.
+func requestScheme(r *http.Request) string {
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "https" || proto == "http" {
+		return proto
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+
+	return "http"
+}
@@ -0,0 +1,73 @@
+package gorender
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestInfo(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/users.html": `{{.Request.Method}} {{.Request.Path}} {{.Request.FullURL}} {{.Request.Query.Get "q"}} {{.Request.IsHTMX}} {{.ActiveClassPrefix "/admin/users" "active"}} {{pageURL .URL 2}}`,
+	})
+
+	r := httptest.NewRequest("GET", "/admin/users/42/?q=ana", nil)
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set("X-Forwarded-Proto", "https")
+	rec := httptest.NewRecorder()
+	if err := re.Template(rec, r, "users.html", nil); err != nil {
+		t.Fatalf("Template: %v", err)
+	}
+
+	want := "GET /admin/users/42/ https://example.com/admin/users/42/?q=ana ana true active /admin/users/42/?page=2&amp;q=ana"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestRequestInfoWithoutRequest(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/mail.html": `[{{.Request.Path}}][{{.Request.Query.Get "q"}}][{{.IsActive "/users"}}]`,
+	})
+
+	var buf bytes.Buffer
+	if err := re.RenderTo(&buf, "mail.html", nil); err != nil {
+		t.Fatalf("RenderTo: %v", err)
+	}
+	if got, want := buf.String(), "[][][false]"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	td := &TemplateData{Request: RequestInfo{Path: "/users/"}}
+	buf.Reset()
+	if err := re.RenderTo(&buf, "mail.html", td); err != nil {
+		t.Fatalf("RenderTo: %v", err)
+	}
+	if got, want := buf.String(), "[/users/][][true]"; got != want {
+		t.Errorf("output with a manual path = %q, want %q", got, want)
+	}
+}
+
+func TestActivePaths(t *testing.T) {
+	tests := []struct {
+		current, path string
+		exact, prefix bool
+	}{
+		{"/users", "/users", true, true},
+		{"/users/", "/users", true, true},
+		{"/users?page=2", "/users/", true, true},
+		{"/admin/users/42", "/admin/users", false, true},
+		{"/admin/users", "/admin/use", false, false},
+		{"/", "/", true, true},
+		{"/about", "/", false, false},
+		{"", "/", true, true},
+	}
+	for _, tt := range tests {
+		if got := isActive(tt.current, tt.path); got != tt.exact {
+			t.Errorf("isActive(%q, %q) = %v, want %v", tt.current, tt.path, got, tt.exact)
+		}
+		if got := isActivePrefix(tt.current, tt.path); got != tt.prefix {
+			t.Errorf("isActivePrefix(%q, %q) = %v, want %v", tt.current, tt.path, got, tt.prefix)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package gorender
+
+import (
+	"fmt"
+	"html/template"
+	texttemplate "text/template"
+)
+
+// WithRequestFuncs declara funciones de las plantillas cuya implementación
+// depende de la petición, como un "can" que consulta los permisos del usuario
+// actual, y que se indican en cada renderizado con TemplateData.Funcs, por
+// ejemplo desde una DefaultDataFunc. Las plantillas se procesan con una
+// versión provisional que devuelve un error si se llega a ejecutar sin valor.
+//
+// Ejemplo:
+//
+//	gorender.WithRequestFuncs("can"),
+//	gorender.WithDefaultDataFunc(func(td *gorender.TemplateData, r *http.Request) {
+//		user := auth.User(r)
+//		td.Funcs = template.FuncMap{"can": user.Can}
+//	}),
+//
+// TemplateData.Funcs también puede sustituir funciones normales del FuncMap.
+// Como las plantillas de la caché no se modifican nunca, cada renderizado con
+// TemplateData.Funcs trabaja sobre una copia de la página, lo que tiene un
+// coste; los que no lo usan no se ven afectados.
+func WithRequestFuncs(names ...string) OptionFunc {
+	return func(re *Render) {
+		re.requestFuncs = true
+		for _, name := range names {
+			if _, ok := re.Functions[name]; !ok {
+				re.Functions[name] = missingRequestFunc(name)
+			}
+		}
+	}
+}
+
+// missingRequestFunc es la versión provisional de una función de
+// WithRequestFuncs.
+func missingRequestFunc(name string) func(...interface{}) (interface{}, error) {
+	return func(...interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("template function %q has no value for this render, set it in TemplateData.Funcs", name)
+	}
+}
+
+// keepPristine guarda en set una copia sin ejecutar de la página key, de la
+// que se parte en los renderizados con TemplateData.Funcs o en otro idioma.
+// html/template no permite copiar una plantilla que ya se ha ejecutado, así
+// que tiene que hacerse antes de publicar el conjunto.
+func (re *Render) keepPristine(set *templateSet, key string, t *template.Template) {
+	if !re.keepsPristine() {
+		return
+	}
+
+	c, err := t.Clone()
+	if err != nil {
+		re.log().Warn("error cloning template, TemplateData.Funcs will not be available", "template", key, "error", err)
+		return
+	}
+	set.pristine[key] = c
+}
+
+// keepsPristine indica si hay que guardar las copias sin ejecutar de las
+// páginas.
+func (re *Render) keepsPristine() bool {
+	return re.requestFuncs || len(re.locales) > 0
+}
+
+// withFuncs devuelve la plantilla que se ejecuta con td: t tal cual o, si td
+// trae funciones propias o está en un idioma distinto del de por defecto, una
+// copia de la página sin ejecutar con esas funciones. Las copias de cada
+// idioma se guardan en set para no tener que procesarlas en cada petición;
+// las que llevan TemplateData.Funcs se hacen cada vez.
+func (re *Render) withFuncs(set *templateSet, t, pristine *template.Template, tmpl string, td *TemplateData) (*template.Template, error) {
+	locale := re.boundLocale(td)
+	if td == nil || len(td.Funcs) == 0 {
+		if locale == "" || pristine == nil {
+			return t, nil
+		}
+		return set.localizedTemplate(pristine, locale, re.localeFuncs(locale))
+	}
+	if pristine == nil {
+		return nil, fmt.Errorf("%s: TemplateData.Funcs requires WithRequestFuncs", tmpl)
+	}
+
+	c, err := pristine.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("%s: cloning template: %w", tmpl, err)
+	}
+	if locale != "" {
+		c = c.Funcs(re.localeFuncs(locale))
+	}
+
+	return c.Funcs(td.Funcs), nil
+}
+
+// localizedTemplate devuelve la copia de pristine con funcs para locale,
+// creándola la primera vez.
+func (s *templateSet) localizedTemplate(pristine *template.Template, locale string, funcs template.FuncMap) (*template.Template, error) {
+	s.localizedMu.Lock()
+	defer s.localizedMu.Unlock()
+
+	key := localizedKey{pristine, locale}
+	if t, ok := s.localized[key]; ok {
+		return t, nil
+	}
+
+	c, err := pristine.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("%s: cloning template: %w", pristine.Name(), err)
+	}
+	c = c.Funcs(funcs)
+	s.localized[key] = c
+
+	return c, nil
+}
+
+// withTextFuncs es igual que withFuncs para las plantillas de texto, que se
+// pueden copiar en cualquier momento.
+func (re *Render) withTextFuncs(t *texttemplate.Template, tmpl string, td *TemplateData) (*texttemplate.Template, error) {
+	locale := re.boundLocale(td)
+	if td == nil || (len(td.Funcs) == 0 && locale == "") {
+		return t, nil
+	}
+
+	c, err := t.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("%s: cloning template: %w", tmpl, err)
+	}
+	if locale != "" {
+		c = c.Funcs(texttemplate.FuncMap(re.localeFuncs(locale)))
+	}
+
+	return c.Funcs(texttemplate.FuncMap(td.Funcs)), nil
+}
@@ -0,0 +1,117 @@
+package gorender
+
+import (
+	"bytes"
+	"html/template"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestFuncs(t *testing.T) {
+	files := map[string]string{
+		"pages/post.html": `{{ define "actions" }}{{ if can "edit:post" }}editar{{ else }}-{{ end }}{{ end }}[{{ template "actions" . }}]`,
+	}
+	re := newTestRender(t, files, WithRequestFuncs("can"))
+	can := func(allowed bool) *TemplateData {
+		return &TemplateData{Funcs: template.FuncMap{"can": func(string) bool { return allowed }}}
+	}
+
+	tests := []struct {
+		name string
+		td   *TemplateData
+		want string
+	}{
+		{"allowed", can(true), "[editar]"},
+		{"denied", can(false), "[-]"},
+		{"allowed again", can(true), "[editar]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := re.RenderTo(&buf, "post.html", tt.td); err != nil {
+				t.Fatalf("RenderTo: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// La plantilla de la caché sigue con la versión provisional.
+	err := re.RenderTo(&bytes.Buffer{}, "post.html", nil)
+	if err == nil || !strings.Contains(err.Error(), `"can"`) {
+		t.Errorf("RenderTo without Funcs error = %v, want the missing can function", err)
+	}
+}
+
+func TestRequestFuncsReplaceDefault(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": `{{ slugify "A b" }}`}, WithRequestFuncs())
+
+	var buf bytes.Buffer
+	td := &TemplateData{Funcs: template.FuncMap{"slugify": func(s string) string { return "<" + s + ">" }}}
+	if err := re.RenderTo(&buf, "index.html", td); err != nil {
+		t.Fatalf("RenderTo: %v", err)
+	}
+	if got := buf.String(); got != "&lt;A b&gt;" {
+		t.Errorf("output = %q, want the replaced function", got)
+	}
+
+	buf.Reset()
+	if err := re.RenderTo(&buf, "index.html", nil); err != nil {
+		t.Fatalf("RenderTo: %v", err)
+	}
+	if got := buf.String(); got != "a-b" {
+		t.Errorf("output without Funcs = %q, want %q", got, "a-b")
+	}
+}
+
+func TestRequestFuncsRequireOption(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": `hola`})
+
+	td := &TemplateData{Funcs: template.FuncMap{"can": func() bool { return true }}}
+	err := re.RenderTo(&bytes.Buffer{}, "index.html", td)
+	if err == nil || !strings.Contains(err.Error(), "WithRequestFuncs") {
+		t.Errorf("RenderTo error = %v, want one asking for WithRequestFuncs", err)
+	}
+}
+
+// BenchmarkRequestFuncs compara un renderizado sin WithRequestFuncs con los de
+// una página que la usa, sin TemplateData.Funcs, que no debería copiar la
+// plantilla, y con ellas, que la copia en cada petición.
+func BenchmarkRequestFuncs(b *testing.B) {
+	files := map[string]string{
+		"pages/index.html": `<ul>{{ range .Data.items }}<li>{{ slugify . }}</li>{{ end }}</ul>`,
+	}
+	items := make([]string, 2000)
+	for i := range items {
+		items[i] = strings.Repeat("x", 10)
+	}
+	upper := template.FuncMap{"slugify": strings.ToUpper}
+
+	tests := []struct {
+		name  string
+		opts  []OptionFunc
+		funcs template.FuncMap
+	}{
+		{"baseline", nil, nil},
+		{"without Funcs", []OptionFunc{WithRequestFuncs()}, nil},
+		{"with Funcs", []OptionFunc{WithRequestFuncs()}, upper},
+	}
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			re := newTestRender(b, files, tt.opts...)
+			req := httptest.NewRequest("GET", "/", nil)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				rec := httptest.NewRecorder()
+				td := &TemplateData{Data: map[string]interface{}{"items": items}, Funcs: tt.funcs}
+				if err := re.Template(rec, req, "index.html", td); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package gorender
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// ResponseWriter envuelve un http.ResponseWriter y anota si ya se ha empezado
+// a escribir la respuesta, para que Template y el resto de métodos devuelvan
+// ErrResponseStarted en lugar de añadir la página a lo que ya se haya
+// enviado. Se instala con TrackResponses.
+type ResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	written int64
+}
+
+// NewResponseWriter envuelve w. Si w ya es un *ResponseWriter se devuelve tal
+// cual.
+func NewResponseWriter(w http.ResponseWriter) *ResponseWriter {
+	if rw, ok := w.(*ResponseWriter); ok {
+		return rw
+	}
+
+	return &ResponseWriter{ResponseWriter: w}
+}
+
+// TrackResponses es un middleware que envuelve la respuesta con
+// ResponseWriter.
+//
+// Ejemplo:
+//
+//	http.ListenAndServe(":8080", gorender.TrackResponses(mux))
+func TrackResponses(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(NewResponseWriter(w), r)
+	})
+}
+
+func (rw *ResponseWriter) WriteHeader(status int) {
+	// Las respuestas informativas, como 103 Early Hints, no son la
+	// definitiva.
+	informational := status >= 100 && status < 200 && status != http.StatusSwitchingProtocols
+	if rw.status == 0 && !informational {
+		rw.status = status
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *ResponseWriter) Write(p []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	n, err := rw.ResponseWriter.Write(p)
+	rw.written += int64(n)
+
+	return n, err
+}
+
+// Written indica si ya se han enviado las cabeceras o parte del cuerpo.
+func (rw *ResponseWriter) Written() bool {
+	return rw.status != 0
+}
+
+// Status devuelve el código de estado enviado, o cero si todavía no se ha
+// enviado ninguno.
+func (rw *ResponseWriter) Status() int {
+	return rw.status
+}
+
+// BytesWritten devuelve los bytes del cuerpo escritos hasta ahora.
+func (rw *ResponseWriter) BytesWritten() int64 {
+	return rw.written
+}
+
+// Unwrap devuelve el http.ResponseWriter original, para http.ResponseController.
+func (rw *ResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Flush envía lo escrito al cliente, lo que también envía las cabeceras.
+func (rw *ResponseWriter) Flush() {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack permite tomar la conexión, como hacen los WebSocket.
+func (rw *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+
+	return h.Hijack()
+}
+
+// responseStarted indica si ya se ha empezado a escribir en w. Se reconoce
+// cualquier ResponseWriter con un método Written() bool, como el de
+// TrackResponses o el de gin, aunque esté envuelto por otros que tengan
+// Unwrap. Si no se puede saber se supone que no.
+func responseStarted(w http.ResponseWriter) bool {
+	for w != nil {
+		if tracked, ok := w.(interface{ Written() bool }); ok {
+			return tracked.Written()
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return false
+		}
+		w = unwrapper.Unwrap()
+	}
+
+	return false
+}
+
+// checkStarted devuelve un error que envuelve ErrResponseStarted si ya se ha
+// empezado a escribir en w. what es lo que se iba a escribir, como "json",
+// para el registro y el error.
+func (re *Render) checkStarted(w http.ResponseWriter, what string) error {
+	if !responseStarted(w) {
+		return nil
+	}
+
+	re.log().Warn("response already started, not writing", "response", what)
+	return fmt.Errorf("%w: %s", ErrResponseStarted, what)
+}
+
+// startedError devuelve el error de una respuesta de tmpl que ya se había
+// empezado a escribir.
+func (re *Render) startedError(r *http.Request, tmpl string) error {
+	re.log().Warn("response already started, not writing", logAttrs(r, tmpl)...)
+	return fmt.Errorf("%w: %s", ErrResponseStarted, tmpl)
+}
@@ -0,0 +1,93 @@
+package gorender
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// wrappedWriter envuelve un http.ResponseWriter sin decir si se ha escrito,
+// como hacen muchos middlewares, pero con Unwrap.
+type wrappedWriter struct {
+	http.ResponseWriter
+}
+
+func (w wrappedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func TestResponseStarted(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/home.html": `<p>home</p>`})
+
+	writers := map[string]func(w http.ResponseWriter, r *http.Request) error{
+		"Template": func(w http.ResponseWriter, r *http.Request) error {
+			return re.Template(w, r, "home.html", nil)
+		},
+		"TemplateStream": func(w http.ResponseWriter, r *http.Request) error {
+			return re.TemplateStream(w, r, "home.html", nil)
+		},
+		"JSON": func(w http.ResponseWriter, r *http.Request) error {
+			return re.JSON(w, http.StatusOK, "ok")
+		},
+		"XML": func(w http.ResponseWriter, r *http.Request) error {
+			return re.XML(w, http.StatusOK, xmlBook{})
+		},
+		"CSV": func(w http.ResponseWriter, r *http.Request) error {
+			return re.CSV(w, "a.csv", []string{"a"}, nil)
+		},
+		"Feed": func(w http.ResponseWriter, r *http.Request) error {
+			return re.Feed(w, Feed{Title: "t", Link: "https://example.com"}, FeedRSS)
+		},
+		"Sitemap": func(w http.ResponseWriter, r *http.Request) error {
+			return re.Sitemap(w, []SitemapEntry{{Loc: "https://example.com/"}})
+		},
+		"Download": func(w http.ResponseWriter, r *http.Request) error {
+			return re.Download(w, r, bytes.NewReader([]byte("data")), "a.txt", testTime)
+		},
+		"Redirect": func(w http.ResponseWriter, r *http.Request) error {
+			return re.Redirect(w, r, "/", 0)
+		},
+	}
+
+	for name, write := range writers {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			var w http.ResponseWriter = wrappedWriter{NewResponseWriter(rec)}
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("partial"))
+
+			err := write(w, httptest.NewRequest("GET", "/", nil))
+			if !errors.Is(err, ErrResponseStarted) {
+				t.Fatalf("error = %v, want ErrResponseStarted", err)
+			}
+			if got := rec.Body.String(); got != "partial" {
+				t.Errorf("body = %q, want only the partial response", got)
+			}
+			if rec.Code != http.StatusInternalServerError {
+				t.Errorf("status = %d, want the original 500", rec.Code)
+			}
+		})
+	}
+}
+
+func TestResponseNotStarted(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/home.html": `<p>home</p>`})
+
+	rec := httptest.NewRecorder()
+	rw := NewResponseWriter(rec)
+	if rw.Written() {
+		t.Fatal("Written() = true before writing")
+	}
+
+	if err := re.Template(rw, httptest.NewRequest("GET", "/", nil), "home.html", nil); err != nil {
+		t.Fatalf("Template: %v", err)
+	}
+	if rw.Status() != http.StatusOK || rw.BytesWritten() != int64(len("<p>home</p>")) {
+		t.Errorf("Status() = %d, BytesWritten() = %d", rw.Status(), rw.BytesWritten())
+	}
+	if NewResponseWriter(rw) != rw {
+		t.Error("NewResponseWriter wrapped a *ResponseWriter again")
+	}
+}
@@ -0,0 +1,82 @@
+package gorender
+
+import (
+	"fmt"
+	"html/template"
+	"reflect"
+)
+
+// safeFuncNames son las funciones que desactivan el escapado de html/template.
+var safeFuncNames = []string{"safeHTML", "safeCSS", "safeURL", "safeJS", "safeHTMLAttr"}
+
+// WithSafeFunctions activa o desactiva las funciones safeHTML, safeCSS,
+// safeURL, safeJS y safeHTMLAttr. Están activas por defecto; al desactivarlas
+// se quitan del FuncMap, también si las ha definido la aplicación, para que
+// ninguna plantilla pueda saltarse el escapado.
+func WithSafeFunctions(enabled bool) OptionFunc {
+	return func(re *Render) {
+		re.disableSafeFuncs = !enabled
+	}
+}
+
+// removeSafeFuncs quita las funciones safe* si se han desactivado.
+func (re *Render) removeSafeFuncs() {
+	if !re.disableSafeFuncs {
+		return
+	}
+
+	for _, name := range safeFuncNames {
+		delete(re.Functions, name)
+	}
+}
+
+// safeHTML marca v como HTML seguro, que se escribe sin escapar. Sólo debe
+// usarse con contenido ya saneado.
+//
+// Ejemplo:
+//
+//	{{ safeHTML .Data.body }}
+func safeHTML(v interface{}) template.HTML {
+	return template.HTML(safeString(v))
+}
+
+// safeCSS marca v como CSS seguro.
+func safeCSS(v interface{}) template.CSS {
+	return template.CSS(safeString(v))
+}
+
+// safeURL marca v como URL segura, sin filtrar esquemas como javascript:.
+func safeURL(v interface{}) template.URL {
+	return template.URL(safeString(v))
+}
+
+// safeJS marca v como JavaScript seguro.
+func safeJS(v interface{}) template.JS {
+	return template.JS(safeString(v))
+}
+
+// safeHTMLAttr marca v como un atributo HTML seguro, por ejemplo
+// `data-id="1"`.
+func safeHTMLAttr(v interface{}) template.HTMLAttr {
+	return template.HTMLAttr(safeString(v))
+}
+
+// safeString convierte v en cadena. nil se convierte en una cadena vacía en
+// lugar de "<nil>".
+func safeString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case fmt.Stringer:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return ""
+		}
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
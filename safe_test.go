@@ -0,0 +1,91 @@
+package gorender
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+)
+
+type namedValue struct{ name string }
+
+func (v *namedValue) String() string { return v.name }
+
+func TestSafeFuncs(t *testing.T) {
+	var nilStringer *namedValue
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"nil", nil, ""},
+		{"string", "<b>hola</b>", "<b>hola</b>"},
+		{"bytes", []byte("<i>x</i>"), "<i>x</i>"},
+		{"stringer", &namedValue{"<em>Ana</em>"}, "<em>Ana</em>"},
+		{"nil stringer", nilStringer, ""},
+		{"number", 42, "42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(safeHTML(tt.value)); got != tt.want {
+				t.Errorf("safeHTML = %q, want %q", got, tt.want)
+			}
+			if got := string(safeURL(tt.value)); got != tt.want {
+				t.Errorf("safeURL = %q, want %q", got, tt.want)
+			}
+			if got := string(safeJS(tt.value)); got != tt.want {
+				t.Errorf("safeJS = %q, want %q", got, tt.want)
+			}
+			if got := string(safeCSS(tt.value)); got != tt.want {
+				t.Errorf("safeCSS = %q, want %q", got, tt.want)
+			}
+			if got := string(safeHTMLAttr(tt.value)); got != tt.want {
+				t.Errorf("safeHTMLAttr = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafeFuncsInTemplate(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/index.html": `{{ safeHTML .Data.body }}<a href="{{ safeURL .Data.url }}" {{ safeHTMLAttr .Data.attr }}>x</a><script>var v = {{ safeJS .Data.js }};</script>`,
+	})
+
+	td := &TemplateData{Data: map[string]interface{}{
+		"body": "<b>hola</b>",
+		"url":  "javascript:void(0)",
+		"attr": `data-id="1"`,
+		"js":   "1 + 2",
+	}}
+	got, err := re.TemplateString("index.html", td)
+	if err != nil {
+		t.Fatalf("TemplateString: %v", err)
+	}
+	if want := `<b>hola</b><a href="javascript:void%280%29" data-id="1">x</a><script>var v = 1 + 2;</script>`; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestWithSafeFunctionsDisabled(t *testing.T) {
+	re := newTestRender(t, map[string]string{"pages/index.html": `hola`},
+		WithFunctions(template.FuncMap{"safeHTML": func(s string) template.HTML { return template.HTML(s) }}),
+		WithSafeFunctions(false))
+
+	for _, name := range safeFuncNames {
+		if _, ok := re.Functions[name]; ok {
+			t.Errorf("%s is registered with WithSafeFunctions(false)", name)
+		}
+	}
+
+	err := re.AddTemplate("unsafe.html", `{{ safeHTML .Data.body }}`)
+	if err == nil || !strings.Contains(err.Error(), `function "safeHTML" not defined`) {
+		t.Errorf("AddTemplate error = %v, want safeHTML undefined", err)
+	}
+
+	re = newTestRender(t, map[string]string{"pages/index.html": `hola`})
+	for _, name := range safeFuncNames {
+		if _, ok := re.Functions[name]; !ok {
+			t.Errorf("%s is not registered by default", name)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package gorender
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Límites de un archivo sitemap según sitemaps.org: 50.000 direcciones y 50
+// MB sin comprimir.
+const (
+	sitemapMaxEntries = 50000
+	sitemapMaxBytes   = 50 << 20
+)
+
+const (
+	sitemapHeader      = `<?xml version="1.0" encoding="UTF-8"?>` + "\n"
+	sitemapURLSet      = `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`
+	sitemapURLSetEnd   = `</urlset>`
+	sitemapIndexSet    = `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`
+	sitemapIndexSetEnd = `</sitemapindex>`
+)
+
+// SitemapEntry es una dirección del sitemap. Los campos vacíos se omiten, así
+// que una prioridad de cero no se escribe.
+type SitemapEntry struct {
+	// Loc es la dirección absoluta de la página.
+	Loc     string
+	LastMod time.Time
+	// ChangeFreq es la frecuencia de cambio: "daily", "weekly"...
+	ChangeFreq string
+	// Priority es la prioridad entre 0 y 1.
+	Priority float64
+}
+
+type sitemapURL struct {
+	XMLName    xml.Name `xml:"url"`
+	Loc        string   `xml:"loc"`
+	LastMod    string   `xml:"lastmod,omitempty"`
+	ChangeFreq string   `xml:"changefreq,omitempty"`
+	Priority   string   `xml:"priority,omitempty"`
+}
+
+type sitemapRef struct {
+	XMLName xml.Name `xml:"sitemap"`
+	Loc     string   `xml:"loc"`
+	LastMod string   `xml:"lastmod,omitempty"`
+}
+
+// WithSitemapPages indica la dirección de cada parte del sitemap, donde %d es
+// el número de la parte empezando por 1, para cuando no cabe en un solo
+// archivo. Sitemap responde entonces con un índice que apunta a esas partes y
+// la aplicación sirve cada una con SitemapPage.
+//
+// Ejemplo:
+//
+//	gorender.WithSitemapPages("https://example.com/sitemap-%d.xml")
+func WithSitemapPages(pattern string) OptionFunc {
+	return func(re *Render) {
+		re.sitemapPages = pattern
+	}
+}
+
+// Sitemap escribe entries como sitemap XML. Si no caben en un solo archivo,
+// más de 50.000 direcciones o 50 MB, escribe un índice con las partes de
+// WithSitemapPages; sin esa opción devuelve un error que envuelve
+// ErrSitemapTooLarge. La respuesta se prepara entera antes de escribirla, así
+// que ante un error no se envía nada.
+func (re *Render) Sitemap(w http.ResponseWriter, entries []SitemapEntry) error {
+	parts, err := splitSitemap(entries)
+	if err != nil {
+		return err
+	}
+	if len(parts) == 1 {
+		return re.writeSitemap(w, sitemapURLSet, parts[0], sitemapURLSetEnd)
+	}
+	if re.sitemapPages == "" {
+		return fmt.Errorf("%w: %d entries in %d parts, use WithSitemapPages", ErrSitemapTooLarge, len(entries), len(parts))
+	}
+
+	refs := make([][]byte, len(parts))
+	for i, part := range parts {
+		ref := sitemapRef{Loc: fmt.Sprintf(re.sitemapPages, i+1), LastMod: sitemapTime(part.lastMod)}
+		refs[i], err = xml.Marshal(ref)
+		if err != nil {
+			return err
+		}
+	}
+
+	return re.writeSitemap(w, sitemapIndexSet, sitemapPart{urls: refs}, sitemapIndexSetEnd)
+}
+
+// SitemapPage escribe la parte page, empezando por 1, del sitemap que Sitemap
+// ha dividido en un índice. Las partes se calculan igual en las dos, así que
+// entries tiene que ser la misma lista. Si la parte no existe devuelve un
+// error que envuelve ErrSitemapPageNotFound, para responder con un 404.
+func (re *Render) SitemapPage(w http.ResponseWriter, entries []SitemapEntry, page int) error {
+	parts, err := splitSitemap(entries)
+	if err != nil {
+		return err
+	}
+	if page < 1 || page > len(parts) {
+		return fmt.Errorf("%w: %d of %d", ErrSitemapPageNotFound, page, len(parts))
+	}
+
+	return re.writeSitemap(w, sitemapURLSet, parts[page-1], sitemapURLSetEnd)
+}
+
+// sitemapPart son las direcciones ya codificadas de un archivo del sitemap y
+// la fecha de modificación más reciente entre ellas.
+type sitemapPart struct {
+	urls    [][]byte
+	lastMod time.Time
+}
+
+// splitSitemap codifica entries y las reparte en archivos que respetan los
+// límites del protocolo.
+func splitSitemap(entries []SitemapEntry) ([]sitemapPart, error) {
+	overhead := len(sitemapHeader) + len(sitemapURLSet) + len(sitemapURLSetEnd)
+
+	parts := []sitemapPart{{}}
+	size := overhead
+	for i, e := range entries {
+		if strings.TrimSpace(e.Loc) == "" {
+			return nil, fmt.Errorf("sitemap entry %d: empty location", i)
+		}
+
+		u := sitemapURL{Loc: e.Loc, LastMod: sitemapTime(e.LastMod), ChangeFreq: e.ChangeFreq}
+		if e.Priority != 0 {
+			u.Priority = strconv.FormatFloat(e.Priority, 'f', 1, 64)
+		}
+		data, err := xml.Marshal(u)
+		if err != nil {
+			return nil, fmt.Errorf("sitemap entry %s: %w", e.Loc, err)
+		}
+		if overhead+len(data) > sitemapMaxBytes {
+			return nil, fmt.Errorf("sitemap entry %d: too large", i)
+		}
+
+		part := &parts[len(parts)-1]
+		if len(part.urls) == sitemapMaxEntries || size+len(data) > sitemapMaxBytes {
+			parts = append(parts, sitemapPart{})
+			part = &parts[len(parts)-1]
+			size = overhead
+		}
+		part.urls = append(part.urls, data)
+		size += len(data)
+		if e.LastMod.After(part.lastMod) {
+			part.lastMod = e.LastMod
+		}
+	}
+
+	return parts, nil
+}
+
+// writeSitemap escribe un archivo del sitemap con las direcciones de part
+// entre open y end.
+func (re *Render) writeSitemap(w http.ResponseWriter, open string, part sitemapPart, end string) error {
+	if err := re.checkStarted(w, "sitemap"); err != nil {
+		return err
+	}
+
+	buf := getBuffer()
+	buf.WriteString(sitemapHeader)
+	buf.WriteString(open)
+	for _, u := range part.urls {
+		buf.Write(u)
+	}
+	buf.WriteString(end)
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	setContentLength(w, buf, http.StatusOK)
+	w.WriteHeader(http.StatusOK)
+
+	_, err := buf.WriteTo(w)
+	if err != nil {
+		re.log().Error("error writing sitemap to browser:", "error", err)
+		return fmt.Errorf("%w: %w", ErrWrite, err)
+	}
+
+	putBuffer(buf)
+	return nil
+}
+
+// sitemapTime da formato W3C a t, o devuelve una cadena vacía si es cero.
+func sitemapTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
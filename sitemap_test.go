@@ -0,0 +1,72 @@
+package gorender
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSitemap(t *testing.T) {
+	re := newTestRender(t, nil)
+	rec := httptest.NewRecorder()
+	entries := []SitemapEntry{
+		{Loc: "https://example.com/", LastMod: testTime, ChangeFreq: "daily", Priority: 1},
+		{Loc: "https://example.com/a?b=1&c=2"},
+	}
+	if err := re.Sitemap(rec, entries); err != nil {
+		t.Fatalf("Sitemap: %v", err)
+	}
+
+	want := sitemapHeader + sitemapURLSet +
+		`<url><loc>https://example.com/</loc><lastmod>2024-03-05T14:30:00Z</lastmod><changefreq>daily</changefreq><priority>1.0</priority></url>` +
+		`<url><loc>https://example.com/a?b=1&amp;c=2</loc></url>` +
+		sitemapURLSetEnd
+	if got := rec.Body.String(); got != want {
+		t.Errorf("body =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSitemapIndex(t *testing.T) {
+	entries := make([]SitemapEntry, sitemapMaxEntries+1)
+	for i := range entries {
+		entries[i] = SitemapEntry{Loc: fmt.Sprintf("https://example.com/%d", i)}
+	}
+
+	re := newTestRender(t, nil)
+	if err := re.Sitemap(httptest.NewRecorder(), entries); !errors.Is(err, ErrSitemapTooLarge) {
+		t.Errorf("Sitemap without WithSitemapPages error = %v, want ErrSitemapTooLarge", err)
+	}
+
+	re = newTestRender(t, nil, WithSitemapPages("https://example.com/sitemap-%d.xml"))
+	rec := httptest.NewRecorder()
+	if err := re.Sitemap(rec, entries); err != nil {
+		t.Fatalf("Sitemap: %v", err)
+	}
+	for _, want := range []string{sitemapIndexSet, "sitemap-1.xml", "sitemap-2.xml"} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("index does not contain %q", want)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	if err := re.SitemapPage(rec, entries, 2); err != nil {
+		t.Fatalf("SitemapPage: %v", err)
+	}
+	if n := strings.Count(rec.Body.String(), "<url>"); n != 1 {
+		t.Errorf("second part has %d urls, want 1", n)
+	}
+	if err := re.SitemapPage(httptest.NewRecorder(), entries, 3); !errors.Is(err, ErrSitemapPageNotFound) {
+		t.Errorf("SitemapPage(3) error = %v, want ErrSitemapPageNotFound", err)
+	}
+}
+
+func TestSitemapWriteError(t *testing.T) {
+	re := newTestRender(t, nil)
+
+	err := re.Sitemap(failingWriter{httptest.NewRecorder()}, []SitemapEntry{{Loc: "https://example.com/"}})
+	if !errors.Is(err, ErrWrite) || !errors.Is(err, errBrokenPipe) {
+		t.Errorf("Sitemap error = %v, want ErrWrite wrapping the write error", err)
+	}
+}
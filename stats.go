@@ -0,0 +1,180 @@
+package gorender
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxMissedNames limita los nombres distintos que se guardan en
+// Stats.MissedTemplates, para que una ruta que construye el nombre de la
+// página a partir de la petición no haga crecer el mapa sin fin.
+const maxMissedNames = 100
+
+// Stats es una foto de la caché de plantillas y de su uso desde que se creó
+// el Render.
+type Stats struct {
+	// Cached indica si las páginas se sirven desde la caché. Si es false cada
+	// petición vuelve a procesar las plantillas.
+	Cached bool `json:"cached"`
+	// Templates y TextTemplates son las páginas procesadas en la última
+	// construcción.
+	Templates     int `json:"templates"`
+	TextTemplates int `json:"text_templates"`
+	// LastBuild es cuándo terminó la última construcción y BuildDuration lo
+	// que tardó.
+	LastBuild     time.Time     `json:"last_build"`
+	BuildDuration time.Duration `json:"build_duration"`
+	// Builds es el total de construcciones y RequestBuilds las que se han
+	// hecho durante una petición por no usar la caché.
+	Builds        int64 `json:"builds"`
+	RequestBuilds int64 `json:"request_builds"`
+	// Hits y Misses cuentan las búsquedas de páginas que se han encontrado y
+	// las que no. MissedTemplates guarda cuántas veces se ha pedido cada
+	// nombre que no existe.
+	Hits            int64            `json:"hits"`
+	Misses          int64            `json:"misses"`
+	MissedTemplates map[string]int64 `json:"missed_templates"`
+	// Renders cuenta las ejecuciones correctas de cada página y Errors las
+	// que han fallado.
+	Renders map[string]int64 `json:"renders"`
+	Errors  map[string]int64 `json:"errors"`
+	// Sources indica, para cada archivo de plantilla de la última
+	// construcción, de dónde se ha leído: SourceOverride, SourceFS,
+	// SourceDisk o SourceMemory.
+	Sources map[string]string `json:"sources"`
+}
+
+// renderStats acumula los contadores de Stats.
+type renderStats struct {
+	mu            sync.Mutex
+	templates     int
+	textTemplates int
+	lastBuild     time.Time
+	buildDuration time.Duration
+	builds        int64
+	requestBuilds int64
+	hits          int64
+	misses        int64
+	missed        map[string]int64
+	renders       map[string]int64
+	errors        map[string]int64
+	sources       map[string]string
+}
+
+// Stats devuelve los contadores de la caché y de los renderizados. Es seguro
+// llamarlo mientras se atienden peticiones.
+func (re *Render) Stats() Stats {
+	s := &re.stats
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := Stats{
+		Cached:          re.EnableCache || re.watch,
+		Templates:       s.templates,
+		TextTemplates:   s.textTemplates,
+		LastBuild:       s.lastBuild,
+		BuildDuration:   s.buildDuration,
+		Builds:          s.builds,
+		RequestBuilds:   s.requestBuilds,
+		Hits:            s.hits,
+		Misses:          s.misses,
+		MissedTemplates: make(map[string]int64, len(s.missed)),
+		Renders:         make(map[string]int64, len(s.renders)),
+		Errors:          make(map[string]int64, len(s.errors)),
+		Sources:         make(map[string]string, len(s.sources)),
+	}
+	for name, n := range s.missed {
+		stats.MissedTemplates[name] = n
+	}
+	for name, n := range s.renders {
+		stats.Renders[name] = n
+	}
+	for name, n := range s.errors {
+		stats.Errors[name] = n
+	}
+	for file, source := range s.sources {
+		stats.Sources[file] = source
+	}
+
+	return stats
+}
+
+// StatsHandler devuelve un http.Handler que responde con Stats en JSON, para
+// montarlo en las rutas de depuración de la aplicación.
+//
+// Ejemplo:
+//
+//	mux.Handle("/debug/templates", ren.StatsHandler())
+func (re *Render) StatsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		err := json.NewEncoder(w).Encode(re.Stats())
+		if err != nil {
+			re.log().Error("error writing stats:", "error", err)
+		}
+	})
+}
+
+// built registra una construcción de set que empezó en start.
+func (s *renderStats) built(start time.Time, set *templateSet) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.builds++
+	s.lastBuild = time.Now()
+	s.buildDuration = s.lastBuild.Sub(start)
+	s.templates = len(set.templates)
+	s.textTemplates = len(set.texts)
+	s.sources = set.sources
+}
+
+func (s *renderStats) requestBuild() {
+	s.mu.Lock()
+	s.requestBuilds++
+	s.mu.Unlock()
+}
+
+// lookup registra la búsqueda de la página name.
+func (s *renderStats) lookup(name string, found bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if found {
+		s.hits++
+		return
+	}
+
+	s.misses++
+	if s.missed == nil {
+		s.missed = map[string]int64{}
+	}
+	if _, ok := s.missed[name]; ok || len(s.missed) < maxMissedNames {
+		s.missed[name]++
+	}
+}
+
+// rendered registra una ejecución correcta de la página name.
+func (s *renderStats) rendered(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.renders == nil {
+		s.renders = map[string]int64{}
+	}
+	s.renders[name]++
+}
+
+// failed registra una ejecución fallida de la página name.
+func (s *renderStats) failed(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.errors == nil {
+		s.errors = map[string]int64{}
+	}
+	s.errors[name]++
+}
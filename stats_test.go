@@ -0,0 +1,73 @@
+package gorender
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/index.html":  `hola`,
+		"pages/broken.html": `{{ index .Data.items 5 }}`,
+		"pages/blocks.html": `{{ define "row" }}fila{{ end }}`,
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	for i := 0; i < 3; i++ {
+		if err := re.Template(httptest.NewRecorder(), req, "index.html", nil); err != nil {
+			t.Fatalf("Template: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := re.Template(httptest.NewRecorder(), req, "broken.html", nil); err == nil {
+			t.Fatal("broken.html rendered without error")
+		}
+	}
+	if err := re.Block(httptest.NewRecorder(), req, "blocks.html", "row", nil); err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	if err := re.Block(httptest.NewRecorder(), req, "blocks.html", "missing", nil); err == nil {
+		t.Fatal("missing block rendered without error")
+	}
+	_ = re.Template(httptest.NewRecorder(), req, "missing.html", nil)
+
+	stats := re.Stats()
+	if got := stats.Renders["index.html"]; got != 3 {
+		t.Errorf("Renders[index.html] = %d, want 3", got)
+	}
+	if got := stats.Renders["broken.html"]; got != 0 {
+		t.Errorf("Renders[broken.html] = %d, want 0", got)
+	}
+	if got := stats.Errors["broken.html"]; got != 2 {
+		t.Errorf("Errors[broken.html] = %d, want 2", got)
+	}
+	if got := stats.Renders["blocks.html"]; got != 1 {
+		t.Errorf("Renders[blocks.html] = %d, want 1", got)
+	}
+	if got := stats.Errors["index.html"]; got != 0 {
+		t.Errorf("Errors[index.html] = %d, want 0", got)
+	}
+	if stats.Hits != 7 || stats.Misses != 1 || stats.MissedTemplates["missing.html"] != 1 {
+		t.Errorf("Hits, Misses, MissedTemplates = %d, %d, %v", stats.Hits, stats.Misses, stats.MissedTemplates)
+	}
+	if !stats.Cached || stats.Templates != 3 || stats.Builds != 1 {
+		t.Errorf("Cached, Templates, Builds = %v, %d, %d", stats.Cached, stats.Templates, stats.Builds)
+	}
+
+	// Stats devuelve una copia.
+	stats.Renders["index.html"] = 100
+	if got := re.Stats().Renders["index.html"]; got != 3 {
+		t.Errorf("Renders[index.html] after changing the copy = %d, want 3", got)
+	}
+
+	rec := httptest.NewRecorder()
+	re.StatsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/templates", nil))
+	var decoded Stats
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded.Errors["broken.html"] != 2 || decoded.Renders["index.html"] != 3 {
+		t.Errorf("StatsHandler = %s", rec.Body.String())
+	}
+}
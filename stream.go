@@ -0,0 +1,143 @@
+package gorender
+
+import (
+	"net/http"
+	"time"
+)
+
+// defaultStreamFlush es cada cuántos bytes TemplateStream envía al cliente lo
+// escrito si no se indica otro valor con WithStreamFlushSize.
+const defaultStreamFlush = 32 << 10
+
+// WithStreaming hace que Template procese las páginas indicadas con
+// TemplateStream, sin pasar por el búfer, para informes muy grandes que no
+// conviene tener enteros en memoria. Los nombres son los mismos que se pasan
+// a Template.
+func WithStreaming(templates ...string) OptionFunc {
+	return func(re *Render) {
+		if re.streamTemplates == nil {
+			re.streamTemplates = map[string]bool{}
+		}
+		for _, tmpl := range templates {
+			re.streamTemplates[tmpl] = true
+		}
+	}
+}
+
+// WithStreamFlushSize indica cada cuántos bytes TemplateStream envía al
+// cliente lo que lleva escrito. Por defecto 32 KB.
+func WithStreamFlushSize(size int) OptionFunc {
+	return func(re *Render) {
+		re.streamFlush = size
+	}
+}
+
+// TemplateStream procesa una página igual que Template pero la escribe
+// directamente en la respuesta a medida que se ejecuta, enviándola al cliente
+// cada WithStreamFlushSize bytes, en lugar de prepararla entera en un búfer.
+// Así la memoria no crece con el tamaño de la página, a cambio de lo que el
+// búfer permite: no se envían Content-Length, ETag ni Last-Modified, y no se
+// aplican WithPostRender, WithMinifyHTML, WithCompression ni WithLiveReload.
+//
+// Si la página falla antes de escribir nada se devuelve el error sin haber
+// respondido, como en Template, y WithAutoErrorPages puede mostrar la página
+// de error. Si falla después, las cabeceras y parte del cuerpo ya se han
+// enviado: se registra el error, no se escribe nada más y se devuelve,
+// quedando la página incompleta. Para que el cliente sepa que la respuesta
+// está cortada, el manejador puede llamar a panic(http.ErrAbortHandler), que
+// cierra la conexión sin terminar la respuesta.
+func (re *Render) TemplateStream(w http.ResponseWriter, r *http.Request, tmpl string, td *TemplateData) (err error) {
+	start := time.Now()
+	sw := &streamWriter{re: re, w: w, r: r, flushSize: re.streamFlush}
+	defer func() {
+		if sw.started {
+			re.observe(start, tmpl, td, err)
+			return
+		}
+		re.finish(w, r, start, tmpl, td, err)
+	}()
+
+	if err := requestGone(r); err != nil {
+		return err
+	}
+	if responseStarted(w) {
+		return re.startedError(r, tmpl)
+	}
+
+	td = re.addDefaultData(td, r)
+	re.drainFlashes(w, r, td)
+	sw.td = td
+
+	err = re.execute(sw, r, tmpl, td)
+	if err != nil {
+		return err
+	}
+	// Una página vacía también tiene que enviar las cabeceras.
+	sw.start()
+	sw.flush()
+
+	return nil
+}
+
+// streamWriter escribe en la respuesta la página de TemplateStream. Las
+// cabeceras y el código de estado se envían con el primer byte, de modo que
+// hasta entonces se puede responder con un error.
+type streamWriter struct {
+	re        *Render
+	w         http.ResponseWriter
+	r         *http.Request
+	td        *TemplateData
+	flushSize int
+	started   bool
+	pending   int
+}
+
+func (sw *streamWriter) Write(p []byte) (int, error) {
+	sw.start()
+
+	n, err := sw.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	sw.pending += n
+	size := sw.flushSize
+	if size <= 0 {
+		size = defaultStreamFlush
+	}
+	if sw.pending >= size {
+		sw.flush()
+	}
+
+	return n, nil
+}
+
+// start envía las cabeceras si no se han enviado ya.
+func (sw *streamWriter) start() {
+	if sw.started {
+		return
+	}
+	sw.started = true
+
+	if sw.w.Header().Get("Content-Type") == "" {
+		contentType := sw.re.contentType
+		if sw.td.ContentType != "" {
+			contentType = sw.td.ContentType
+		}
+		sw.w.Header().Set("Content-Type", contentType)
+	}
+	sw.re.setCSPHeader(sw.w, sw.td)
+
+	status := sw.td.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	sw.w.WriteHeader(status)
+}
+
+func (sw *streamWriter) flush() {
+	sw.pending = 0
+	if f, ok := sw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
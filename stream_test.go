@@ -0,0 +1,106 @@
+package gorender
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// flushRecorder cuenta las veces que se envía la respuesta al cliente.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes int
+}
+
+func (f *flushRecorder) Flush() {
+	f.flushes++
+	f.ResponseRecorder.Flush()
+}
+
+func TestTemplateStream(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/report.html": `{{ range .Data.rows }}{{ . }}{{ end }}`,
+	}, WithStreamFlushSize(1000))
+
+	rows := make([]string, 100)
+	for i := range rows {
+		rows[i] = strings.Repeat("x", 100)
+	}
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	td := &TemplateData{Data: map[string]interface{}{"rows": rows}}
+	if err := re.TemplateStream(rec, httptest.NewRequest("GET", "/", nil), "report.html", td); err != nil {
+		t.Fatalf("TemplateStream: %v", err)
+	}
+
+	if rec.Body.Len() != 10000 {
+		t.Errorf("body has %d bytes, want 10000", rec.Body.Len())
+	}
+	// Una vez cada 1000 bytes y otra al terminar.
+	if rec.flushes != 11 {
+		t.Errorf("flushed %d times, want 11", rec.flushes)
+	}
+	if rec.Header().Get("Content-Length") != "" {
+		t.Errorf("streamed response has Content-Length %q", rec.Header().Get("Content-Length"))
+	}
+	if rec.Header().Get("Content-Type") == "" {
+		t.Error("streamed response has no Content-Type")
+	}
+}
+
+func TestTemplateStreamErrors(t *testing.T) {
+	td := func() *TemplateData { return &TemplateData{Data: map[string]interface{}{"x": 1}} }
+
+	t.Run("before the first byte", func(t *testing.T) {
+		re := newTestRender(t, map[string]string{"pages/report.html": `{{ .Data.x.Missing }}rest`})
+		rec := httptest.NewRecorder()
+		err := re.TemplateStream(rec, httptest.NewRequest("GET", "/", nil), "report.html", td())
+		if !errors.Is(err, ErrExecute) {
+			t.Fatalf("TemplateStream error = %v, want ErrExecute", err)
+		}
+		if rec.Body.Len() != 0 || rec.Header().Get("Content-Type") != "" {
+			t.Errorf("response started: body %q, headers %v", rec.Body.String(), rec.Header())
+		}
+	})
+
+	t.Run("after the first byte", func(t *testing.T) {
+		re := newTestRender(t, map[string]string{"pages/report.html": `start{{ .Data.x.Missing }}rest`})
+		rec := httptest.NewRecorder()
+		err := re.TemplateStream(rec, httptest.NewRequest("GET", "/", nil), "report.html", td())
+		if !errors.Is(err, ErrExecute) {
+			t.Fatalf("TemplateStream error = %v, want ErrExecute", err)
+		}
+		if got := rec.Body.String(); got != "start" {
+			t.Errorf("body = %q, want the part written before the error and nothing else", got)
+		}
+	})
+
+	t.Run("after the first byte with auto error pages", func(t *testing.T) {
+		re := newTestRender(t, map[string]string{
+			"pages/report.html": `start{{ .Data.x.Missing }}rest`,
+			"pages/500.html":    `error page`,
+		}, WithErrorTemplates(map[int]string{500: "500.html"}), WithAutoErrorPages(true))
+		rec := httptest.NewRecorder()
+		_ = re.TemplateStream(rec, httptest.NewRequest("GET", "/", nil), "report.html", td())
+		if got := rec.Body.String(); got != "start" {
+			t.Errorf("body = %q, want no error page appended", got)
+		}
+	})
+}
+
+func TestWithStreaming(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/report.html": `informe`,
+		"pages/index.html":  `inicio`,
+	}, WithStreaming("report.html"))
+
+	for page, streamed := range map[string]bool{"report.html": true, "index.html": false} {
+		rec := httptest.NewRecorder()
+		if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), page, nil); err != nil {
+			t.Fatalf("Template %s: %v", page, err)
+		}
+		if got := rec.Header().Get("Content-Length") == ""; got != streamed {
+			t.Errorf("%s streamed = %v, want %v", page, got, streamed)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package gorender
+
+import (
+	"html/template"
+	"strings"
+	"unicode"
+)
+
+// defaultEllipsis es el sufijo de truncate y excerpt cuando no se indica otro.
+const defaultEllipsis = "…"
+
+// truncate corta s a n caracteres, sin partir ninguno de varios bytes, y
+// añade suffix si se ha cortado. Sin suffix se usa "…".
+//
+// Ejemplo:
+//
+//	{{ truncate .Data.title 40 }}
+//	{{ truncate .Data.title 40 "..." }}
+func truncate(s string, n int, suffix ...string) string {
+	runes := []rune(s)
+	if n < 0 || len(runes) <= n {
+		return s
+	}
+
+	return string(runes[:n]) + ellipsis(suffix)
+}
+
+// excerpt funciona igual que truncate pero corta en el último espacio antes de
+// n caracteres, para no dejar palabras a medias. Si la primera palabra ya es
+// más larga que n se corta como truncate.
+func excerpt(s string, n int, suffix ...string) string {
+	runes := []rune(s)
+	if n < 0 || len(runes) <= n {
+		return s
+	}
+
+	cut := n
+	for cut > 0 && !unicode.IsSpace(runes[cut]) {
+		cut--
+	}
+	if cut == 0 {
+		cut = n
+	}
+
+	return strings.TrimRightFunc(string(runes[:cut]), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + ellipsis(suffix)
+}
+
+func ellipsis(suffix []string) string {
+	if len(suffix) > 0 {
+		return suffix[0]
+	}
+	return defaultEllipsis
+}
+
+// transliterations son las letras que slugify sustituye por su equivalente
+// sin tilde. Las que no están y no son ASCII se quitan.
+var transliterations = map[rune]string{
+	'á': "a", 'à': "a", 'â': "a", 'ä': "a", 'ã': "a", 'å': "a", 'ā': "a",
+	'é': "e", 'è': "e", 'ê': "e", 'ë': "e", 'ē': "e",
+	'í': "i", 'ì': "i", 'î': "i", 'ï': "i", 'ī': "i",
+	'ó': "o", 'ò': "o", 'ô': "o", 'ö': "o", 'õ': "o", 'ø': "o", 'ō': "o",
+	'ú': "u", 'ù': "u", 'û': "u", 'ü': "u", 'ū': "u",
+	'ý': "y", 'ÿ': "y",
+	'ñ': "n", 'ç': "c", 'ß': "ss", 'æ': "ae", 'œ': "oe", 'ł': "l", 'đ': "d",
+}
+
+// slugify convierte s en un fragmento de URL: minúsculas, sin tildes y con
+// guiones entre las palabras.
+//
+// Ejemplo:
+//
+//	{{ slugify "¡Canción Ñandú 2024!" }} → cancion-nandu-2024
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+			dash = false
+		case transliterations[r] != "":
+			b.WriteString(transliterations[r])
+			dash = false
+		case r > unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			// Las letras sin equivalente ASCII se quitan sin separar la
+			// palabra.
+		default:
+			if !dash && b.Len() > 0 {
+				b.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// nl2br escapa s y convierte sus saltos de línea en <br>, para mostrar texto
+// escrito por el usuario respetando los párrafos.
+//
+// Ejemplo:
+//
+//	<p>{{ nl2br .Data.comment }}</p>
+func nl2br(s string) template.HTML {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = template.HTMLEscapeString(line)
+	}
+
+	return template.HTML(strings.Join(lines, "<br>\n"))
+}
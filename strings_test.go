@@ -0,0 +1,92 @@
+package gorender
+
+import (
+	"html/template"
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s      string
+		n      int
+		suffix []string
+		want   string
+	}{
+		{"hola", 10, nil, "hola"},
+		{"hola", 4, nil, "hola"},
+		{"hola mundo", 4, nil, "hola…"},
+		{"hola mundo", 4, []string{"..."}, "hola..."},
+		{"hola mundo", 4, []string{""}, "hola"},
+		{"canción", 6, nil, "canció…"},
+		{"日本語のテキスト", 3, nil, "日本語…"},
+		{"👍👍👍", 1, nil, "👍…"},
+		{"hola", -1, nil, "hola"},
+		{"", 3, nil, ""},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.n, tt.suffix...); got != tt.want {
+			t.Errorf("truncate(%q, %d, %q) = %q, want %q", tt.s, tt.n, tt.suffix, got, tt.want)
+		}
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		s      string
+		n      int
+		suffix []string
+		want   string
+	}{
+		{"hola mundo", 20, nil, "hola mundo"},
+		{"hola mundo cruel", 12, nil, "hola mundo…"},
+		{"hola, mundo cruel", 8, nil, "hola…"},
+		{"hola mundo cruel", 12, []string{" [...]"}, "hola mundo [...]"},
+		{"supercalifragilístico", 5, nil, "super…"},
+		{"el niño pequeño juega", 14, nil, "el niño…"},
+		{"日本 語のテキスト", 4, nil, "日本…"},
+	}
+	for _, tt := range tests {
+		if got := excerpt(tt.s, tt.n, tt.suffix...); got != tt.want {
+			t.Errorf("excerpt(%q, %d, %q) = %q, want %q", tt.s, tt.n, tt.suffix, got, tt.want)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"Hola Mundo", "hola-mundo"},
+		{"¡Canción Ñandú 2024!", "cancion-nandu-2024"},
+		{"  espacios   de más  ", "espacios-de-mas"},
+		{"Straße & Œuvre", "strasse-oeuvre"},
+		{"a--b__c", "a-b-c"},
+		{"日本語 title", "title"},
+		{"Ελληνικά", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := slugify(tt.s); got != tt.want {
+			t.Errorf("slugify(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestNl2br(t *testing.T) {
+	tests := []struct {
+		s    string
+		want template.HTML
+	}{
+		{"hola", "hola"},
+		{"línea 1\nlínea 2", "línea 1<br>\nlínea 2"},
+		{"a\r\nb", "a<br>\nb"},
+		{"<script>alert(1)</script>\n&", "&lt;script&gt;alert(1)&lt;/script&gt;<br>\n&amp;"},
+		{"\n", "<br>\n"},
+	}
+	for _, tt := range tests {
+		if got := nl2br(tt.s); got != tt.want {
+			t.Errorf("nl2br(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package gorender
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"path/filepath"
+	texttemplate "text/template"
+	"time"
+)
+
+// WithTextExtensions indica las extensiones de las plantillas de texto plano,
+// que se procesan con text/template en lugar de html/template y por tanto no
+// escapan el contenido. Por defecto ".txt".
+func WithTextExtensions(exts ...string) OptionFunc {
+	return func(re *Render) {
+		re.textExtensions = normalizeExtensions(exts)
+	}
+}
+
+// parseTextPages procesa las páginas de texto plano junto con las plantillas de
+// texto compartidas y las añade al conjunto. Las funciones registradas son las
+// mismas que las de las plantillas HTML.
+func (re *Render) parseTextPages(set *templateSet, basenames map[string][]string, only string) error {
+	pages, err := re.findFiles(re.PageTemplatesPath, re.textExtensions)
+	if err != nil {
+		return fmt.Errorf("finding text page templates in %s: %w", re.PageTemplatesPath, err)
+	}
+	pages = re.filterPages(pages, only)
+
+	files, err := re.findFiles(re.TemplatesPath, re.textExtensions)
+	if err != nil {
+		return fmt.Errorf("finding text templates in %s: %w", re.TemplatesPath, err)
+	}
+
+	re.recordSources(set, files...)
+	re.recordSources(set, pages...)
+
+	for _, file := range pages {
+		name := path.Base(filepath.ToSlash(file))
+		ts := texttemplate.New(name).Delims(re.leftDelim, re.rightDelim).Funcs(texttemplate.FuncMap(re.Functions))
+		if re.strict {
+			ts = ts.Option("missingkey=error")
+		}
+		ts, err = parseFiles(re, ts, append(files, file)...)
+		if err != nil {
+			return fmt.Errorf("parsing text template %s: %w", file, err)
+		}
+
+		key := re.pageKey(file)
+		set.texts[key] = ts
+		set.pageFiles[key] = file
+		basenames[name] = append(basenames[name], key)
+	}
+
+	return nil
+}
+
+// Text procesa una plantilla de texto plano, como el cuerpo de un correo o un
+// robots.txt, y la escribe en la respuesta con "text/plain; charset=utf-8".
+func (re *Render) Text(w http.ResponseWriter, r *http.Request, tmpl string, td *TemplateData) (err error) {
+	start := time.Now()
+	defer func() { re.finish(w, r, start, tmpl, td, err) }()
+
+	buf := getBuffer()
+	td = re.addDefaultData(td, r)
+	err = re.executeText(buf, r, tmpl, td)
+	if err != nil {
+		putBuffer(buf)
+		return err
+	}
+
+	return re.write(w, r, tmpl, buf, td, "text/plain; charset=utf-8")
+}
+
+// TextTo procesa una plantilla de texto plano y la escribe en w, sin necesidad
+// de una petición HTTP. El token CSRF queda como venga en td.
+func (re *Render) TextTo(w io.Writer, tmpl string, td *TemplateData) error {
+	td = re.baseData(td)
+	buf := getBuffer()
+	err := re.executeText(buf, nil, tmpl, td)
+	if err != nil {
+		putBuffer(buf)
+		return err
+	}
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		return err
+	}
+
+	putBuffer(buf)
+	return nil
+}
+
+func (re *Render) executeText(buf *bytes.Buffer, r *http.Request, tmpl string, td *TemplateData) error {
+	set, err := re.setFor(tmpl)
+	if err != nil {
+		re.log().Error("error creating template cache:", logAttrs(r, tmpl, "error", err)...)
+		return err
+	}
+
+	t, ok := set.lookupText(tmpl)
+	re.stats.lookup(tmpl, ok)
+	if !ok {
+		return re.missingError(set, tmpl)
+	}
+
+	td.template = set.name(tmpl)
+	t, err = re.withTextFuncs(t, tmpl, td)
+	if err != nil {
+		return err
+	}
+
+	err = re.executeGuarded(buf, r, tmpl, func(w io.Writer) error {
+		return t.Execute(w, td)
+	})
+	if err != nil {
+		re.stats.failed(td.template)
+		return err
+	}
+	re.stats.rendered(td.template)
+
+	return nil
+}
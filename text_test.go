@@ -0,0 +1,45 @@
+package gorender
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"shared/signature.txt": `{{ define "signature" }}-- {{ .Data.from }}{{ end }}`,
+		"pages/mail.txt":       `Hola {{ .Data.name }}: <b>{{ .Data.body }}</b> & {{ slugify "Adiós Amigo" }}` + "\n" + `{{ template "signature" . }}`,
+	})
+	td := func() *TemplateData {
+		return NewData().
+			Set("name", `Ana "A" O'Brien`).
+			Set("body", "<script>alert(1)</script>").
+			Set("from", "Equipo <soporte@example.com>").
+			Build()
+	}
+	want := `Hola Ana "A" O'Brien: <b><script>alert(1)</script></b> & adios-amigo` + "\n" + `-- Equipo <soporte@example.com>`
+
+	rec := httptest.NewRecorder()
+	if err := re.Text(rec, httptest.NewRequest("GET", "/", nil), "mail.txt", td()); err != nil {
+		t.Fatalf("Text: %v", err)
+	}
+	if got := rec.Body.String(); got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want text/plain; charset=utf-8", got)
+	}
+
+	var buf bytes.Buffer
+	if err := re.TextTo(&buf, "mail.txt", td()); err != nil {
+		t.Fatalf("TextTo: %v", err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("TextTo = %q, want %q", got, want)
+	}
+
+	if err := re.TextTo(&buf, "missing.txt", nil); err == nil {
+		t.Error("TextTo with a missing template: got nil error")
+	}
+}
@@ -0,0 +1,217 @@
+package gorender
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// WithTranslationsDir carga las traducciones desde los archivos JSON de dir,
+// uno por idioma: es.json, en_US.json... Cada archivo es un objeto con las
+// claves y sus traducciones; los objetos anidados se aplanan con puntos, así
+// que {"form": {"name": "Nombre"}} se usa como translateKey "form.name".
+//
+// Las traducciones se vuelven a leer junto con las plantillas: en cada
+// petición si la caché está deshabilitada, al llamar a Reload y cuando
+// WithWatch detecta un cambio.
+func WithTranslationsDir(dir string) OptionFunc {
+	return func(re *Render) {
+		re.translationsFS = os.DirFS(dir)
+		re.translationsDir = dir
+	}
+}
+
+// WithTranslationsFS funciona igual que WithTranslationsDir pero lee los
+// archivos de la raíz de fsys, por ejemplo un embed.FS pasado por fs.Sub.
+func WithTranslationsFS(fsys fs.FS) OptionFunc {
+	return func(re *Render) {
+		re.translationsFS = fsys
+		re.translationsDir = ""
+	}
+}
+
+// legacyTranslationsExt es la extensión de los archivos de traducciones
+// anteriores a los catálogos JSON.
+const legacyTranslationsExt = ".translate"
+
+// loadCatalogs lee de nuevo las traducciones configuradas. Sin
+// WithTranslationsDir ni WithTranslationsFS lee los archivos ".translate" del
+// directorio de trabajo, si los hay; ver loadLegacyCatalogs.
+func (re *Render) loadCatalogs() error {
+	if re.translationsFS == nil {
+		return re.loadLegacyCatalogs()
+	}
+
+	entries, err := fs.ReadDir(re.translationsFS, ".")
+	if err != nil {
+		return fmt.Errorf("reading translations: %w", err)
+	}
+
+	catalogs := map[string]map[string]string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || path.Ext(name) != ".json" {
+			continue
+		}
+
+		data, err := fs.ReadFile(re.translationsFS, name)
+		if err != nil {
+			return fmt.Errorf("reading translations %s: %w", name, err)
+		}
+
+		var raw map[string]interface{}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("parsing translations %s: %w", name, err)
+		}
+
+		messages := map[string]string{}
+		flattenMessages(messages, "", raw)
+		catalogs[normalizeLocale(strings.TrimSuffix(name, ".json"))] = messages
+	}
+
+	re.catalogsMu.Lock()
+	re.catalogs = catalogs
+	re.catalogsMu.Unlock()
+
+	return nil
+}
+
+// loadLegacyCatalogs lee los archivos "<idioma>.translate" del directorio de
+// trabajo, con una línea "clave = traducción" por mensaje, que era el formato
+// de las traducciones antes de los catálogos JSON. Se mantiene para que las
+// instalaciones que aún los usan sigan traduciendo, pero se avisa una vez de
+// que hay que pasarlos a JSON con WithTranslationsDir.
+func (re *Render) loadLegacyCatalogs() error {
+	names, err := filepath.Glob("*" + legacyTranslationsExt)
+	if err != nil {
+		return fmt.Errorf("reading translations: %w", err)
+	}
+
+	var catalogs map[string]map[string]string
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("reading translations %s: %w", name, err)
+		}
+
+		messages := map[string]string{}
+		for _, line := range strings.Split(string(data), "\n") {
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			messages[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+
+		if catalogs == nil {
+			catalogs = map[string]map[string]string{}
+		}
+		catalogs[normalizeLocale(strings.TrimSuffix(name, legacyTranslationsExt))] = messages
+	}
+
+	if len(names) > 0 {
+		re.legacyOnce.Do(func() {
+			re.log().Warn("loading legacy .translate files, move them to JSON catalogs and use WithTranslationsDir", "files", names)
+		})
+	}
+
+	re.catalogsMu.Lock()
+	re.catalogs = catalogs
+	re.catalogsMu.Unlock()
+
+	return nil
+}
+
+// flattenMessages copia raw en messages uniendo las claves anidadas con
+// puntos.
+func flattenMessages(messages map[string]string, prefix string, raw map[string]interface{}) {
+	for k, v := range raw {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		switch v := v.(type) {
+		case string:
+			messages[key] = v
+		case map[string]interface{}:
+			flattenMessages(messages, key, v)
+		default:
+			messages[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// translateCatalog busca key en el catálogo de lang y después en el del idioma
+// por defecto. Si no está en ninguno se registra a nivel Debug para poder
+// encontrar las traducciones que faltan.
+func (re *Render) translateCatalog(lang, key string) (string, bool) {
+	if translated, ok := re.lookupCatalog(lang, key); ok {
+		return translated, true
+	}
+
+	re.log().Debug("missing translation", "key", key, "locale", lang)
+	return "", false
+}
+
+// lookupCatalog funciona igual que translateCatalog pero sin registrar las
+// claves que faltan, para las traducciones opcionales que tienen un valor
+// incluido en el paquete.
+func (re *Render) lookupCatalog(lang, key string) (string, bool) {
+	re.catalogsMu.RLock()
+	defer re.catalogsMu.RUnlock()
+
+	for _, l := range []string{lang, re.defaultLocale} {
+		if translated, ok := catalogFor(re.catalogs, l)[key]; ok {
+			return translated, true
+		}
+	}
+
+	return "", false
+}
+
+// catalogFor devuelve el catálogo de lang o, si no hay, el de su lengua: "en"
+// para "en_US".
+func catalogFor(catalogs map[string]map[string]string, lang string) map[string]string {
+	if lang == "" {
+		return nil
+	}
+
+	lang = normalizeLocale(lang)
+	if catalog, ok := catalogs[lang]; ok {
+		return catalog
+	}
+
+	base, _, _ := strings.Cut(lang, "_")
+	return catalogs[base]
+}
+
+// translationFiles devuelve los archivos de traducciones en el disco para que
+// WithWatch los vigile. Con WithTranslationsFS no hay nada que vigilar.
+func (re *Render) translationFiles() map[string]fileState {
+	files := map[string]fileState{}
+	if re.translationsDir == "" {
+		return files
+	}
+
+	entries, err := os.ReadDir(re.translationsDir)
+	if err != nil {
+		return files
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files[filepath.Join(re.translationsDir, entry.Name())] = fileState{info.ModTime(), info.Size()}
+	}
+
+	return files
+}
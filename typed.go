@@ -0,0 +1,49 @@
+package gorender
+
+import "net/http"
+
+// SetData guarda v en td.Data con la clave indicada, creando el mapa si hace
+// falta. Es lo mismo que td.Set, pero pensado para usarse junto con GetData.
+func SetData[T any](td *TemplateData, key string, v T) {
+	td.Set(key, v)
+}
+
+// GetData devuelve el valor de td.Data con la clave indicada si existe y es de
+// tipo T, sin tener que hacer la conversión a mano.
+//
+// Ejemplo:
+//
+//	user, ok := gorender.GetData[*User](td, "user")
+func GetData[T any](td *TemplateData, key string) (T, bool) {
+	v, ok := td.Get(key).(T)
+	return v, ok
+}
+
+// TypedTemplate renderiza páginas cuyo modelo es siempre de tipo T, de modo
+// que el compilador comprueba lo que recibe cada página. El modelo queda en
+// TemplateData.Model y las plantillas acceden a sus campos con
+// {{ .Model.Title }}.
+//
+// Ejemplo:
+//
+//	posts := gorender.NewTypedTemplate[PostView](ren)
+//	posts.Render(w, r, "post.html", PostView{Title: p.Title}, nil)
+type TypedTemplate[T any] struct {
+	render *Render
+}
+
+// NewTypedTemplate crea un TypedTemplate que renderiza con re.
+func NewTypedTemplate[T any](re *Render) *TypedTemplate[T] {
+	return &TypedTemplate[T]{render: re}
+}
+
+// Render procesa tmpl igual que Render.Template, con model en
+// TemplateData.Model. td puede ser nil.
+func (tt *TypedTemplate[T]) Render(w http.ResponseWriter, r *http.Request, tmpl string, model T, td *TemplateData) error {
+	if td == nil {
+		td = &TemplateData{}
+	}
+	td.Model = model
+
+	return tt.render.Template(w, r, tmpl, td)
+}
@@ -0,0 +1,66 @@
+package gorender
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type postView struct {
+	Title string
+	Tags  []string
+}
+
+func TestTypedTemplate(t *testing.T) {
+	re := newTestRender(t, map[string]string{
+		"pages/post.html":  `<h1>{{ .Model.Title }}</h1>{{ range .Model.Tags }}<span>{{ . }}</span>{{ end }}{{ .Data.extra }}`,
+		"pages/wrong.html": `<h1>{{ .Model.Author }}</h1>`,
+	})
+	posts := NewTypedTemplate[postView](re)
+
+	rec := httptest.NewRecorder()
+	td := (&TemplateData{}).Set("extra", "!")
+	if err := posts.Render(rec, httptest.NewRequest("GET", "/", nil), "post.html", postView{Title: "Hola <mundo>", Tags: []string{"go"}}, td); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if got, want := rec.Body.String(), "<h1>Hola &lt;mundo&gt;</h1><span>go</span>!"; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	if err := posts.Render(rec, httptest.NewRequest("GET", "/", nil), "post.html", postView{Title: "Sin datos"}, nil); err != nil {
+		t.Fatalf("Render with nil TemplateData: %v", err)
+	}
+	if !strings.HasPrefix(rec.Body.String(), "<h1>Sin datos</h1>") {
+		t.Errorf("body = %q", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	err := posts.Render(rec, httptest.NewRequest("GET", "/", nil), "wrong.html", postView{Title: "x"}, nil)
+	if !errors.Is(err, ErrExecute) || !strings.Contains(err.Error(), "can't evaluate field Author") {
+		t.Errorf("Render error = %v, want ErrExecute for the field missing in the model", err)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("failed render wrote %q", rec.Body.String())
+	}
+}
+
+func TestGetData(t *testing.T) {
+	td := &TemplateData{}
+	SetData(td, "post", postView{Title: "Hola"})
+	SetData(td, "count", 3)
+
+	if post, ok := GetData[postView](td, "post"); !ok || post.Title != "Hola" {
+		t.Errorf("GetData[postView] = %+v, %v", post, ok)
+	}
+	if count, ok := GetData[int](td, "count"); !ok || count != 3 {
+		t.Errorf("GetData[int] = %d, %v", count, ok)
+	}
+	if post, ok := GetData[*postView](td, "post"); ok || post != nil {
+		t.Errorf("GetData[*postView] = %v, %v, want a type mismatch", post, ok)
+	}
+	if s, ok := GetData[string](td, "missing"); ok || s != "" {
+		t.Errorf("GetData on a missing key = %q, %v", s, ok)
+	}
+}
@@ -0,0 +1,133 @@
+package gorender
+
+import (
+	"fmt"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Los validadores comprueban los valores de Values y, si no se cumplen, añaden
+// un mensaje con addFieldError. Los mensajes se escriben en español y, en los
+// formularios creados con Render.NewFormData o preparados con Localize, se
+// traducen con los catálogos del Render usando el texto original como clave.
+// Salvo Required, todos ignoran los campos vacíos para que los opcionales no
+// den error.
+//
+// Ejemplo:
+//
+//	fd := ren.NewFormData(r)
+//	fd.Required("name", "email")
+//	fd.IsEmail("email")
+//	fd.MinLength("password", 8)
+//	if !fd.Valid() {
+//	 // volver a mostrar el formulario con fd
+//	}
+
+// NewFormData funciona igual que la función NewFormData, pero los mensajes de
+// los validadores se traducen al idioma de la petición.
+func (re *Render) NewFormData(r *http.Request) FormData {
+	fd := NewFormData(r)
+	fd.Localize(re, re.Locale(r))
+
+	return fd
+}
+
+// Localize hace que los mensajes de los validadores que se llamen después se
+// traduzcan con los catálogos de re al idioma locale. Con locale vacío se usa
+// el idioma por defecto.
+func (fd *FormData) Localize(re *Render, locale string) {
+	fd.translate = func(key string) string {
+		return re.translateKey(key, locale)
+	}
+}
+
+// message devuelve la traducción de key o key tal cual si el formulario no
+// tiene traducciones.
+func (fd *FormData) message(key string) string {
+	if fd.translate == nil {
+		return key
+	}
+
+	return fd.translate(key)
+}
+
+// Required comprueba que los campos no estén vacíos.
+func (fd *FormData) Required(fields ...string) {
+	for _, field := range fields {
+		if strings.TrimSpace(fd.Values[field]) == "" {
+			fd.addFieldError(field, fd.message("Este campo es obligatorio."))
+		}
+	}
+}
+
+// MinLength comprueba que el campo tenga al menos n caracteres.
+func (fd *FormData) MinLength(field string, n int) {
+	value := fd.Values[field]
+	if value != "" && utf8.RuneCountInString(value) < n {
+		fd.addFieldError(field, fmt.Sprintf(fd.message("Debe tener al menos %d caracteres."), n))
+	}
+}
+
+// MaxLength comprueba que el campo no tenga más de n caracteres.
+func (fd *FormData) MaxLength(field string, n int) {
+	value := fd.Values[field]
+	if value != "" && utf8.RuneCountInString(value) > n {
+		fd.addFieldError(field, fmt.Sprintf(fd.message("No puede tener más de %d caracteres."), n))
+	}
+}
+
+// IsEmail comprueba que el campo sea una dirección de correo, sin nombre ni
+// otros adornos.
+func (fd *FormData) IsEmail(field string) {
+	value := fd.Values[field]
+	if value == "" {
+		return
+	}
+
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		fd.addFieldError(field, fd.message("Debe ser un correo electrónico válido."))
+	}
+}
+
+// Matches comprueba que el campo cumpla la expresión regular.
+func (fd *FormData) Matches(field string, re *regexp.Regexp) {
+	value := fd.Values[field]
+	if value != "" && !re.MatchString(value) {
+		fd.addFieldError(field, fd.message("El formato no es válido."))
+	}
+}
+
+// EqualTo comprueba que el campo sea igual a other, por ejemplo para repetir
+// la contraseña.
+func (fd *FormData) EqualTo(field, other string) {
+	value := fd.Values[field]
+	if value != "" && value != fd.Values[other] {
+		fd.addFieldError(field, fmt.Sprintf(fd.message("Debe coincidir con %s."), fd.message(other)))
+	}
+}
+
+// Valid indica si no se ha registrado ningún error.
+func (fd *FormData) Valid() bool {
+	return len(fd.Errors) == 0
+}
+
+// addFieldError acumula message en FieldErrors. Errors guarda el primero de
+// cada campo, que es el que se suele mostrar y el que usa containsErrors.
+func (fd *FormData) addFieldError(field, message string) {
+	if fd.Errors == nil {
+		fd.Errors = map[string]string{}
+	}
+	if fd.FieldErrors == nil {
+		fd.FieldErrors = map[string][]string{}
+	}
+
+	fd.HasErrors = true
+	fd.FieldErrors[field] = append(fd.FieldErrors[field], message)
+	if _, ok := fd.Errors[field]; !ok {
+		fd.Errors[field] = message
+	}
+}
@@ -0,0 +1,100 @@
+package gorender
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestValidators(t *testing.T) {
+	fd := NewForm()
+	fd.FromValues(url.Values{
+		"name":     {""},
+		"email":    {"Ana <ana@example.com>"},
+		"password": {"abc"},
+		"repeat":   {"abd"},
+		"code":     {"12a"},
+		"bio":      {"ñññññ"},
+	})
+
+	fd.Required("name")
+	fd.IsEmail("email")
+	fd.MinLength("password", 8)
+	fd.EqualTo("repeat", "password")
+	fd.Matches("code", regexp.MustCompile(`^\d+$`))
+	fd.MaxLength("bio", 5)
+	fd.MinLength("missing", 3)
+
+	if fd.Valid() {
+		t.Fatal("Valid() = true, want false")
+	}
+	want := map[string]string{
+		"name":     "Este campo es obligatorio.",
+		"email":    "Debe ser un correo electrónico válido.",
+		"password": "Debe tener al menos 8 caracteres.",
+		"repeat":   "Debe coincidir con password.",
+		"code":     "El formato no es válido.",
+	}
+	for field, msg := range want {
+		if got := fd.Error(field); got != msg {
+			t.Errorf("Error(%q) = %q, want %q", field, got, msg)
+		}
+	}
+	for _, field := range []string{"bio", "missing"} {
+		if fd.HasError(field) {
+			t.Errorf("%s has error %q, want none", field, fd.Error(field))
+		}
+	}
+	if !containsErrors(fd.Errors, "name") {
+		t.Error("containsErrors does not see the validator errors")
+	}
+}
+
+func TestValidatorsAccumulate(t *testing.T) {
+	fd := NewForm()
+	fd.FromValues(url.Values{"password": {"abc"}})
+	fd.MinLength("password", 8)
+	fd.Matches("password", regexp.MustCompile(`\d`))
+
+	if got := len(fd.FieldErrors["password"]); got != 2 {
+		t.Fatalf("password has %d errors, want 2", got)
+	}
+	if got := fd.Error("password"); !strings.HasPrefix(got, "Debe tener") {
+		t.Errorf("Error(password) = %q, want the first error", got)
+	}
+}
+
+func TestRenderNewFormDataTranslates(t *testing.T) {
+	catalogs := fstest.MapFS{
+		"en.json": &fstest.MapFile{Data: []byte(`{
+			"Este campo es obligatorio.": "This field is required.",
+			"Debe tener al menos %d caracteres.": "Must be at least %d characters long."
+		}`)},
+	}
+	re := newTestRender(t, nil, WithLocales("es", "en"), WithTranslationsFS(catalogs))
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader("password=abc"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Accept-Language", "en-US,en;q=0.9")
+
+	fd := re.NewFormData(r)
+	fd.Required("name")
+	fd.MinLength("password", 8)
+
+	if got, want := fd.Error("name"), "This field is required."; got != want {
+		t.Errorf("Error(name) = %q, want %q", got, want)
+	}
+	if got, want := fd.Error("password"), "Must be at least 8 characters long."; got != want {
+		t.Errorf("Error(password) = %q, want %q", got, want)
+	}
+
+	fd = NewForm()
+	fd.Localize(re, "es")
+	fd.Required("name")
+	if got, want := fd.Error("name"), "Este campo es obligatorio."; got != want {
+		t.Errorf("Error(name) in es = %q, want %q", got, want)
+	}
+}
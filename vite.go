@@ -0,0 +1,196 @@
+package gorender
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// manifestChunk es una entrada del manifest.json que genera Vite.
+type manifestChunk struct {
+	File    string   `json:"file"`
+	Src     string   `json:"src"`
+	IsEntry bool     `json:"isEntry"`
+	CSS     []string `json:"css"`
+	Imports []string `json:"imports"`
+}
+
+// WithAssetManifest lee el manifest.json de Vite, o de otra herramienta con el
+// mismo formato, para que las funciones vite y viteTags devuelvan los archivos
+// con su resumen en el nombre. Las URL empiezan por el prefijo de
+// WithStaticDir o por "/" si no se ha configurado. El manifest se lee en el
+// disco o en el sistema de archivos de WithFS, y se vuelve a leer junto con
+// las plantillas.
+//
+// Ejemplo:
+//
+//	<head>{{ viteTags "src/main.ts" .CSPNonce }}</head>
+func WithAssetManifest(path string) OptionFunc {
+	return func(re *Render) {
+		re.manifestPath = path
+	}
+}
+
+// WithViteDevServer hace que vite y viteTags apunten al servidor de desarrollo
+// de Vite, por ejemplo "http://localhost:5173", e incluyan el cliente de
+// recarga en caliente. En este modo no se lee el manifest.
+func WithViteDevServer(url string) OptionFunc {
+	return func(re *Render) {
+		re.viteDevServer = strings.TrimSuffix(url, "/")
+	}
+}
+
+// loadManifest lee de nuevo el manifest configurado.
+func (re *Render) loadManifest() error {
+	if re.manifestPath == "" || re.viteDevServer != "" {
+		return nil
+	}
+
+	var data []byte
+	var err error
+	if re.fs != nil {
+		data, err = fs.ReadFile(re.fs, fsPath(re.manifestPath))
+	} else {
+		data, err = os.ReadFile(re.manifestPath)
+	}
+	if err != nil {
+		return fmt.Errorf("reading asset manifest: %w", err)
+	}
+
+	var manifest map[string]manifestChunk
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("parsing asset manifest %s: %w", re.manifestPath, err)
+	}
+
+	re.manifestMu.Lock()
+	re.manifest = manifest
+	re.manifestMu.Unlock()
+
+	return nil
+}
+
+// vite devuelve la URL del archivo generado para entry.
+//
+// Ejemplo:
+//
+//	<script type="module" src="{{ vite "src/main.ts" }}"></script>
+func (re *Render) vite(entry string) (string, error) {
+	if re.viteDevServer != "" {
+		return re.viteDevServer + "/" + strings.TrimPrefix(entry, "/"), nil
+	}
+
+	chunk, err := re.manifestChunk(entry)
+	if err != nil {
+		return "", err
+	}
+
+	return re.manifestURL(chunk.File), nil
+}
+
+// viteCSS devuelve las URL de las hojas de estilo que necesita entry,
+// incluidas las de los módulos que importa. En modo desarrollo no hay ninguna
+// porque Vite las inyecta.
+func (re *Render) viteCSS(entry string) ([]string, error) {
+	if re.viteDevServer != "" {
+		return nil, nil
+	}
+
+	re.manifestMu.RLock()
+	defer re.manifestMu.RUnlock()
+
+	if _, ok := re.manifest[entry]; !ok {
+		return nil, fmt.Errorf("asset manifest has no entry %q", entry)
+	}
+
+	var css []string
+	seen := map[string]bool{}
+	var collect func(key string)
+	collect = func(key string) {
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+
+		chunk := re.manifest[key]
+		for _, file := range chunk.CSS {
+			css = append(css, re.manifestURL(file))
+		}
+		for _, imported := range chunk.Imports {
+			collect(imported)
+		}
+	}
+	collect(entry)
+
+	return css, nil
+}
+
+// viteTags devuelve las etiquetas <link> y <script> de entry. En modo
+// desarrollo añade también el cliente de Vite. Con WithCSP hay que pasarle
+// .CSPNonce para que los <script> lleven el nonce de la página y el navegador
+// no los bloquee.
+//
+// Ejemplo:
+//
+//	<head>{{ viteTags "src/main.ts" .CSPNonce }}</head>
+func (re *Render) viteTags(entry string, nonce ...string) (template.HTML, error) {
+	src, err := re.vite(entry)
+	if err != nil {
+		return "", err
+	}
+
+	css, err := re.viteCSS(entry)
+	if err != nil {
+		return "", err
+	}
+
+	var attr string
+	if len(nonce) > 0 {
+		attr = nonceAttr(nonce[0])
+	}
+
+	var b strings.Builder
+	if re.viteDevServer != "" {
+		fmt.Fprintf(&b, `<script type="module"%s src="%s/@vite/client"></script>`, attr, template.HTMLEscapeString(re.viteDevServer))
+	}
+	for _, href := range css {
+		fmt.Fprintf(&b, `<link rel="stylesheet" href="%s">`, template.HTMLEscapeString(href))
+	}
+	fmt.Fprintf(&b, `<script type="module"%s src="%s"></script>`, attr, template.HTMLEscapeString(src))
+
+	return template.HTML(b.String()), nil
+}
+
+func (re *Render) manifestChunk(entry string) (manifestChunk, error) {
+	re.manifestMu.RLock()
+	defer re.manifestMu.RUnlock()
+
+	chunk, ok := re.manifest[entry]
+	if !ok {
+		return manifestChunk{}, fmt.Errorf("asset manifest has no entry %q", entry)
+	}
+
+	return chunk, nil
+}
+
+func (re *Render) manifestURL(file string) string {
+	return path.Join("/", re.staticPrefix, file)
+}
+
+// manifestFiles devuelve el manifest del disco para que WithWatch detecte sus
+// cambios.
+func (re *Render) manifestFiles() map[string]fileState {
+	files := map[string]fileState{}
+	if re.manifestPath == "" || re.fs != nil {
+		return files
+	}
+
+	if info, err := os.Stat(re.manifestPath); err == nil {
+		files[re.manifestPath] = fileState{info.ModTime(), info.Size()}
+	}
+
+	return files
+}
@@ -0,0 +1,72 @@
+package gorender
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testManifest = `{
+  "src/main.ts": {"file": "assets/main-abc.js", "src": "src/main.ts", "isEntry": true, "css": ["assets/main-def.css"]}
+}`
+
+func TestViteTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl string
+		opts []OptionFunc
+		want []string
+		not  []string
+	}{
+		{
+			name: "manifest",
+			tmpl: `{{ viteTags "src/main.ts" }}`,
+			want: []string{
+				`<link rel="stylesheet" href="/assets/main-def.css">`,
+				`<script type="module" src="/assets/main-abc.js"></script>`,
+			},
+			not: []string{"nonce"},
+		},
+		{
+			name: "manifest with nonce",
+			tmpl: `{{ viteTags "src/main.ts" .CSPNonce }}`,
+			opts: []OptionFunc{WithCSP("script-src 'nonce-{nonce}'")},
+			want: []string{`<script type="module" nonce="`},
+		},
+		{
+			name: "dev server with nonce",
+			tmpl: `{{ viteTags "src/main.ts" "abc" }}`,
+			opts: []OptionFunc{WithViteDevServer("http://localhost:5173")},
+			want: []string{
+				`<script type="module" nonce="abc" src="http://localhost:5173/@vite/client"></script>`,
+				`<script type="module" nonce="abc" src="http://localhost:5173/src/main.ts"></script>`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{
+				"pages/index.html":   tt.tmpl,
+				"dist/manifest.json": testManifest,
+			}
+			re := newTestRender(t, files, append([]OptionFunc{WithAssetManifest("dist/manifest.json")}, tt.opts...)...)
+
+			rec := httptest.NewRecorder()
+			if err := re.Template(rec, httptest.NewRequest("GET", "/", nil), "index.html", nil); err != nil {
+				t.Fatalf("Template: %v", err)
+			}
+			buf := rec.Body
+			for _, w := range tt.want {
+				if !strings.Contains(buf.String(), w) {
+					t.Errorf("output %q does not contain %q", buf.String(), w)
+				}
+			}
+			for _, n := range tt.not {
+				if strings.Contains(buf.String(), n) {
+					t.Errorf("output %q contains %q", buf.String(), n)
+				}
+			}
+		})
+	}
+}
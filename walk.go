@@ -0,0 +1,111 @@
+package gorender
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WithFollowSymlinks hace que, al buscar plantillas en el disco, se entre en
+// los directorios enlazados con symlinks, por ejemplo un directorio de
+// fragmentos compartido entre varios servicios. Las plantillas encontradas
+// así se identifican por su ruta a través del enlace. Los enlaces que apuntan
+// a uno de los directorios que ya se están recorriendo se ignoran para no
+// entrar en un bucle. No tiene efecto con WithFS.
+func WithFollowSymlinks(follow bool) OptionFunc {
+	return func(re *Render) {
+		re.followSymlinks = follow
+	}
+}
+
+// WithHiddenFiles indica si se buscan plantillas en los archivos y
+// directorios ocultos, los que empiezan por ".". Por defecto se ignoran para
+// no procesar los archivos temporales de los editores, como ".#index.html".
+func WithHiddenFiles(include bool) OptionFunc {
+	return func(re *Render) {
+		re.hiddenFiles = include
+	}
+}
+
+// skipEntry indica si p se salta al recorrer root, por estar oculto o por
+// coincidir con WithExclude. Si p es un directorio devuelve además fs.SkipDir
+// para no recorrerlo.
+func (re *Render) skipEntry(root, p string, d fs.DirEntry) (bool, error) {
+	if !re.hiddenFiles && p != root && strings.HasPrefix(d.Name(), ".") {
+		if d.IsDir() {
+			return true, fs.SkipDir
+		}
+		return true, nil
+	}
+
+	return re.skipExcluded(root, p, d)
+}
+
+// walkDisk recorre root en el disco igual que filepath.WalkDir y, con
+// WithFollowSymlinks, entra también en los directorios enlazados.
+func (re *Render) walkDisk(root string, fn fs.WalkDirFunc) error {
+	if !re.followSymlinks {
+		return filepath.WalkDir(root, fn)
+	}
+
+	real, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+
+	return re.walkLinked(root, real, map[string]bool{real: true}, fn)
+}
+
+// walkLinked recorre el directorio real mostrando sus rutas dentro de shown.
+// ancestors son los directorios reales que ya se están recorriendo.
+func (re *Render) walkLinked(shown, real string, ancestors map[string]bool, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(real, func(p string, d fs.DirEntry, err error) error {
+		rel, relErr := filepath.Rel(real, p)
+		if relErr != nil {
+			return relErr
+		}
+		p = filepath.Join(shown, rel)
+
+		if err != nil || d.Type()&fs.ModeSymlink == 0 {
+			return fn(p, d, err)
+		}
+
+		// Los enlaces rotos o a archivos se tratan igual que sin seguirlos.
+		info, statErr := os.Stat(p)
+		if statErr != nil || !info.IsDir() {
+			return fn(p, d, nil)
+		}
+
+		err = fn(p, fs.FileInfoToDirEntry(info), nil)
+		if err != nil {
+			if err == fs.SkipDir {
+				return nil
+			}
+			return err
+		}
+
+		target, err := filepath.EvalSymlinks(p)
+		if err != nil {
+			return fn(p, d, err)
+		}
+		if ancestors[target] || isWithin(filepath.Join(real, rel), target) {
+			re.log().Warn("symlink loop detected, not following", "path", p, "target", target)
+			return nil
+		}
+
+		linked := make(map[string]bool, len(ancestors)+1)
+		for dir := range ancestors {
+			linked[dir] = true
+		}
+		linked[target] = true
+
+		return re.walkLinked(p, target, linked, fn)
+	})
+}
+
+// isWithin indica si p está dentro del directorio dir.
+func isWithin(p, dir string) bool {
+	rel, err := filepath.Rel(dir, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,110 @@
+package gorender
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFiles crea en dir los archivos de files, ruta con barras → contenido.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// symlink crea el enlace link → target o salta la prueba si el sistema no lo
+// permite.
+func symlink(t *testing.T, target, link string) {
+	t.Helper()
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+}
+
+func TestFollowSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"app/shared/base.html":      `{{ define "base" }}[{{ template "card" . }}]{{ end }}`,
+		"app/pages/index.html":      `{{ template "base" . }}`,
+		"app/pages/.#index.html":    `{{ if }}`,
+		"common/partials/card.html": `{{ define "card" }}card{{ end }}`,
+	})
+	symlink(t, filepath.Join(dir, "common", "partials"), filepath.Join(dir, "app", "shared", "partials"))
+	// a → b → a: partials/loop apunta al propio directorio de fragmentos.
+	symlink(t, filepath.Join(dir, "common", "partials"), filepath.Join(dir, "common", "partials", "loop"))
+
+	newRender := func(opts ...OptionFunc) (*Render, error) {
+		base := []OptionFunc{
+			WithTemplatesPath(filepath.Join(dir, "app", "shared")),
+			WithPageTemplatesPath(filepath.Join(dir, "app", "pages")),
+			WithCache(true),
+			WithCSRFTokenFunc(nil),
+			WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		}
+		return NewE(append(base, opts...)...)
+	}
+
+	re, err := newRender()
+	if err != nil {
+		t.Fatalf("NewE: %v", err)
+	}
+	if err := re.RenderTo(io.Discard, "index.html", nil); err == nil {
+		t.Error("without WithFollowSymlinks the linked card template was found")
+	}
+
+	re, err = newRender(WithFollowSymlinks(true))
+	if err != nil {
+		t.Fatalf("NewE: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := re.RenderTo(&buf, "index.html", nil); err != nil {
+		t.Fatalf("RenderTo: %v", err)
+	}
+	if got := buf.String(); got != "[card]" {
+		t.Errorf("output = %q, want %q", got, "[card]")
+	}
+}
+
+func TestHiddenFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"pages/index.html":        `hola`,
+		"pages/.#index.html":      `{{ if }}`,
+		"pages/.drafts/post.html": `borrador`,
+		"shared/.keep":            ``,
+	})
+
+	newRender := func(opts ...OptionFunc) (*Render, error) {
+		base := []OptionFunc{
+			WithTemplatesPath(filepath.Join(dir, "shared")),
+			WithPageTemplatesPath(filepath.Join(dir, "pages")),
+			WithCache(true),
+			WithCSRFTokenFunc(nil),
+			WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		}
+		return NewE(append(base, opts...)...)
+	}
+
+	re, err := newRender()
+	if err != nil {
+		t.Fatalf("NewE: %v", err)
+	}
+	if n := re.TemplateCache.Len(); n != 1 {
+		t.Errorf("cache has %d pages, want only index.html", n)
+	}
+
+	if _, err := newRender(WithHiddenFiles(true)); err == nil {
+		t.Error("NewE with WithHiddenFiles did not parse the broken editor lock file")
+	}
+}
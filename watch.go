@@ -0,0 +1,149 @@
+package gorender
+
+import (
+	"io/fs"
+	"time"
+)
+
+// WithWatch construye la caché una sola vez y la reconstruye cuando se crea,
+// modifica o elimina alguna plantilla dentro de TemplatesPath o
+// PageTemplatesPath. Pensado para desarrollo: evita reprocesar todas las
+// plantillas en cada petición sin tener que reiniciar el servidor tras cada
+// cambio.
+//
+// Los cambios se detectan revisando cada segundo la fecha de modificación y el
+// tamaño de los archivos en lugar de usar notificaciones del sistema como
+// fsnotify. Así funciona igual en disco que con WithFS, que no tiene forma de
+// avisar de cambios, y en los volúmenes de Docker o de red, donde inotify no
+// recibe los eventos. Los editores que guardan renombrando el archivo tampoco
+// rompen la vigilancia, y no añade dependencias. A cambio, un
+// cambio tarda hasta un segundo en notarse y cada revisión recorre los
+// directorios, algo que en desarrollo no se aprecia.
+func WithWatch(watch bool) OptionFunc {
+	return func(re *Render) {
+		re.watch = watch
+	}
+}
+
+// Close detiene la vigilancia de plantillas iniciada con WithWatch. Es seguro
+// llamarlo aunque la vigilancia no esté activa.
+func (re *Render) Close() error {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+
+	if re.stopWatch != nil {
+		close(re.stopWatch)
+		re.stopWatch = nil
+	}
+
+	return nil
+}
+
+// fileState es el estado de una plantilla usado para detectar cambios.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+func (re *Render) startWatch() {
+	stop := make(chan struct{})
+	re.mu.Lock()
+	re.stopWatch = stop
+	re.mu.Unlock()
+
+	snapshot := re.snapshotTemplates()
+
+	go func() {
+		ticker := time.NewTicker(re.watchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				current := re.snapshotTemplates()
+				file, event := diffSnapshots(snapshot, current)
+				if event == "" {
+					continue
+				}
+				snapshot = current
+
+				re.log().Info("template change detected, rebuilding cache", "file", file, "event", event)
+				_ = re.Reload()
+			}
+		}
+	}()
+}
+
+// snapshotTemplates devuelve el estado actual de todas las plantillas
+// vigiladas. Los directorios que no se pueden recorrer se ignoran para que un
+// borrado temporal no detenga la vigilancia.
+func (re *Render) snapshotTemplates() map[string]fileState {
+	snapshot := map[string]fileState{}
+
+	for _, root := range []string{re.TemplatesPath, re.PageTemplatesPath} {
+		_ = re.walkTemplates(root, func(path string, d fs.DirEntry) error {
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			snapshot[path] = fileState{info.ModTime(), info.Size()}
+			return nil
+		})
+	}
+
+	if re.overridePath != "" {
+		_ = re.walkDisk(re.overridePath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if skip, err := re.skipEntry(re.overridePath, path, d); skip {
+				return err
+			}
+			if d.IsDir() || !re.isTemplateFile(path) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			snapshot[path] = fileState{info.ModTime(), info.Size()}
+			return nil
+		})
+	}
+
+	for path, state := range re.translationFiles() {
+		snapshot[path] = state
+	}
+	for path, state := range re.staticFiles() {
+		snapshot[path] = state
+	}
+	for path, state := range re.manifestFiles() {
+		snapshot[path] = state
+	}
+
+	return snapshot
+}
+
+// diffSnapshots devuelve el primer archivo que ha cambiado entre dos estados y
+// el tipo de cambio, o una cadena vacía si no hay cambios.
+func diffSnapshots(old, current map[string]fileState) (string, string) {
+	for path, state := range current {
+		prev, ok := old[path]
+		if !ok {
+			return path, "created"
+		}
+		if !prev.modTime.Equal(state.modTime) || prev.size != state.size {
+			return path, "modified"
+		}
+	}
+
+	for path := range old {
+		if _, ok := current[path]; !ok {
+			return path, "deleted"
+		}
+	}
+
+	return "", ""
+}
@@ -0,0 +1,95 @@
+package gorender
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// withWatchInterval acorta el intervalo de revisión de WithWatch en las
+// pruebas.
+func withWatchInterval(d time.Duration) OptionFunc {
+	return func(re *Render) {
+		re.watchInterval = d
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"shared/base.html": `{{ define "base" }}<main>{{ template "content" . }}</main>{{ end }}`,
+		"pages/index.html": `{{ template "base" . }}{{ define "content" }}v1{{ end }}`,
+	})
+
+	re, err := NewE(
+		WithTemplatesPath(filepath.Join(dir, "shared")),
+		WithPageTemplatesPath(filepath.Join(dir, "pages")),
+		WithCache(false),
+		WithWatch(true),
+		withWatchInterval(10*time.Millisecond),
+		WithCSRFTokenFunc(nil),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	if err != nil {
+		t.Fatalf("NewE: %v", err)
+	}
+	t.Cleanup(func() { re.Close() })
+
+	render := func(name string) string {
+		var buf bytes.Buffer
+		if err := re.RenderTo(&buf, name, nil); err != nil {
+			return "error: " + err.Error()
+		}
+		return buf.String()
+	}
+	waitFor := func(name, want string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if render(name) == want {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		t.Fatalf("%s = %q, want %q after the change", name, render(name), want)
+	}
+
+	if got := render("index.html"); got != "<main>v1</main>" {
+		t.Fatalf("index.html = %q before any change", got)
+	}
+
+	// Sin caché pero con WithWatch, el disco no se vuelve a leer hasta que
+	// la revisión detecta el cambio.
+	writeFiles(t, dir, map[string]string{"pages/index.html": `{{ template "base" . }}{{ define "content" }}version 2{{ end }}`})
+	waitFor("index.html", "<main>version 2</main>")
+
+	writeFiles(t, dir, map[string]string{"shared/base.html": `{{ define "base" }}<body>{{ template "content" . }}</body>{{ end }}`})
+	waitFor("index.html", "<body>version 2</body>")
+
+	writeFiles(t, dir, map[string]string{"pages/new.html": `{{ template "base" . }}{{ define "content" }}nueva{{ end }}`})
+	waitFor("new.html", "<body>nueva</body>")
+
+	if err := os.Remove(filepath.Join(dir, "pages", "new.html")); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for render("new.html") == "<body>nueva</body>" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := render("new.html"); got == "<body>nueva</body>" {
+		t.Errorf("new.html still renders after being deleted")
+	}
+
+	if err := re.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	writeFiles(t, dir, map[string]string{"pages/index.html": `{{ template "base" . }}{{ define "content" }}after close{{ end }}`})
+	time.Sleep(50 * time.Millisecond)
+	if got := render("index.html"); got != "<body>version 2</body>" {
+		t.Errorf("index.html = %q after Close, want the cached version", got)
+	}
+}
@@ -0,0 +1,44 @@
+package gorender
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+)
+
+// XML codifica v como XML, con la cabecera <?xml ...?>, y lo escribe en la
+// respuesta con el código de estado indicado, o con 200 si es cero. Igual que
+// JSON, la codificación se hace sobre un búfer para no dejar respuestas a
+// medias si falla, y si falla la escritura se devuelve un error que envuelve
+// ErrWrite.
+func (re *Render) XML(w http.ResponseWriter, status int, v any) error {
+	if err := re.checkStarted(w, "xml"); err != nil {
+		return err
+	}
+
+	buf := getBuffer()
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+
+	err := xml.NewEncoder(buf).Encode(v)
+	if err != nil {
+		re.log().Error("error encoding xml:", "error", err)
+		putBuffer(buf)
+		return err
+	}
+
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	setContentLength(w, buf, status)
+	w.WriteHeader(status)
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		re.log().Error("error writing xml to browser:", "error", err)
+		return fmt.Errorf("%w: %w", ErrWrite, err)
+	}
+
+	putBuffer(buf)
+	return nil
+}
@@ -0,0 +1,60 @@
+package gorender
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type xmlBook struct {
+	XMLName xml.Name    `xml:"book"`
+	ID      int         `xml:"id,attr"`
+	Title   string      `xml:"title"`
+	Authors []xmlAuthor `xml:"authors>author"`
+}
+
+type xmlAuthor struct {
+	Role string `xml:"role,attr"`
+	Name string `xml:",chardata"`
+}
+
+func TestXML(t *testing.T) {
+	re := newTestRender(t, nil)
+	rec := httptest.NewRecorder()
+
+	book := xmlBook{ID: 7, Title: "Go & XML", Authors: []xmlAuthor{{"main", "Ana"}, {"editor", "Luis"}}}
+	if err := re.XML(rec, 0, book); err != nil {
+		t.Fatalf("XML: %v", err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<book id="7"><title>Go &amp; XML</title><authors><author role="main">Ana</author><author role="editor">Luis</author></authors></book>`
+	if got := rec.Body.String(); got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+}
+
+func TestXMLErrors(t *testing.T) {
+	re := newTestRender(t, nil)
+
+	rec := httptest.NewRecorder()
+	if err := re.XML(rec, http.StatusOK, map[string]string{"a": "b"}); err == nil {
+		t.Error("XML with a map returned no error")
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("XML wrote %q after failing to encode", rec.Body.String())
+	}
+
+	err := re.XML(failingWriter{httptest.NewRecorder()}, http.StatusOK, xmlBook{})
+	if !errors.Is(err, ErrWrite) {
+		t.Errorf("XML error = %v, want ErrWrite", err)
+	}
+}